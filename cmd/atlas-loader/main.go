@@ -6,7 +6,7 @@ import (
 	"os"
 
 	"ariga.io/atlas-provider-gorm/gormschema"
-	"github.com/konflux-ci/kite/internal/models"
+	"github.com/konflux-ci/kite/pkg/models"
 )
 
 func main() {
@@ -16,6 +16,8 @@ func main() {
 		&models.Issue{},
 		&models.Link{},
 		&models.RelatedIssue{},
+		&models.OutboxEvent{},
+		&models.SavedView{},
 	)
 
 	if err != nil {
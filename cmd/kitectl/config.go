@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/konflux-ci/kite/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// contextFile holds the servers kitectl can talk to, in the style of a kubeconfig
+type contextFile struct {
+	APIVersion     string         `json:"apiVersion"`
+	Kind           string         `json:"kind"`
+	CurrentContext string         `json:"current-context"`
+	Contexts       []namedContext `json:"contexts"`
+}
+
+type namedContext struct {
+	Name    string      `json:"name"`
+	Context contextSpec `json:"context"`
+}
+
+type contextSpec struct {
+	Server    string `json:"server"`
+	Namespace string `json:"namespace,omitempty"`
+	Token     string `json:"token,omitempty"`
+	// Read before every request, such as a service account token that's rotated
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// Helper function to get the path of the context file when --kiteconfig isn't set
+func defaultContextPath() string {
+	if path := os.Getenv("KITECONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".kite", "config")
+}
+
+// Helper function to load a context file, a missing file is empty
+func loadContextFile(path string) (*contextFile, error) {
+	file := &contextFile{APIVersion: "v1", Kind: "Config"}
+	if path == "" {
+		return file, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("invalid context file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+func (f *contextFile) save(path string) error {
+	if path == "" {
+		return errors.New("no context file, set --kiteconfig or $KITECONFIG")
+	}
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// Contexts can hold tokens
+	return os.WriteFile(path, data, 0o600)
+}
+
+func (f *contextFile) find(name string) *namedContext {
+	for i := range f.Contexts {
+		if f.Contexts[i].Name == name {
+			return &f.Contexts[i]
+		}
+	}
+	return nil
+}
+
+// globalOptions are the flags every command takes
+type globalOptions struct {
+	kiteconfig string
+	context    string
+	server     string
+	namespace  string
+	token      string
+	output     string
+}
+
+// Helper function to create a flag set for a command, with the global flags added to it
+func newFlagSet(name string, usage string) (*flag.FlagSet, *globalOptions) {
+	opts := &globalOptions{}
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kitectl %s\n\nFlags:\n", usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.kiteconfig, "kiteconfig", defaultContextPath(), "context file")
+	flags.StringVar(&opts.context, "context", "", "context to use instead of the current one")
+	flags.StringVar(&opts.server, "server", os.Getenv("KITE_SERVER"), "API server URL")
+	flags.StringVar(&opts.namespace, "namespace", os.Getenv("KITE_NAMESPACE"), "namespace")
+	flags.StringVar(&opts.namespace, "n", os.Getenv("KITE_NAMESPACE"), "namespace (shorthand)")
+	flags.StringVar(&opts.token, "token", os.Getenv("KITE_TOKEN"), "bearer token")
+	flags.StringVar(&opts.output, "output", "table", "output format: table, json or yaml")
+	flags.StringVar(&opts.output, "o", "table", "output format (shorthand)")
+	return flags, opts
+}
+
+// parseFlags parses flags anywhere among the arguments, so they can follow positional ones
+// like "issues get ID -o json". It returns the positional arguments, which must number exactly nargs
+// unless nargs is negative.
+func parseFlags(flags *flag.FlagSet, args []string, nargs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if nargs >= 0 && len(positional) != nargs {
+		flags.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// resolvedContext is the context a command runs in, after flags and environment variables are applied
+type resolvedContext struct {
+	contextSpec
+	output string
+}
+
+// Helper function to work out the server, namespace and credentials from the context file and flags
+func (o *globalOptions) resolve() (*resolvedContext, error) {
+	switch o.output {
+	case "table", "json", "yaml":
+	default:
+		return nil, fmt.Errorf("unknown output format %q, use table, json or yaml", o.output)
+	}
+
+	file, err := loadContextFile(o.kiteconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &resolvedContext{output: o.output}
+	name := o.context
+	if name == "" {
+		name = file.CurrentContext
+	}
+	if name != "" {
+		named := file.find(name)
+		if named == nil {
+			return nil, fmt.Errorf("context %q not found in %s", name, o.kiteconfig)
+		}
+		resolved.contextSpec = named.Context
+	}
+
+	if o.server != "" {
+		resolved.Server = o.server
+	}
+	if o.namespace != "" {
+		resolved.Namespace = o.namespace
+	}
+	if o.token != "" {
+		resolved.Token = o.token
+		resolved.TokenFile = ""
+	}
+	if resolved.Server == "" {
+		return nil, errors.New("no server, set --server or a context with kitectl config set-context")
+	}
+	return resolved, nil
+}
+
+// Helper function to create an API client for the context
+func (r *resolvedContext) client() (*client.Client, error) {
+	opts := []client.Option{client.WithUserAgent("kitectl")}
+	switch {
+	case r.TokenFile != "":
+		path := r.TokenFile
+		opts = append(opts, client.WithTokenSource(func(context.Context) (string, error) {
+			token, err := os.ReadFile(path)
+			return strings.TrimSpace(string(token)), err
+		}))
+	case r.Token != "":
+		opts = append(opts, client.WithToken(r.Token))
+	}
+	return client.New(r.Server, opts...)
+}
+
+// Helper function to check a namespace is set, for commands that run in one
+func (r *resolvedContext) requireNamespace() error {
+	if r.Namespace == "" {
+		return errors.New("no namespace, set -n or a namespace in the context")
+	}
+	return nil
+}
+
+// Helper function to parse a command's flags and connect to the API with them
+func setup(flags *flag.FlagSet, opts *globalOptions, args []string, nargs int) ([]string, *resolvedContext, *client.Client, error) {
+	positional, err := parseFlags(flags, args, nargs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	resolved, err := opts.resolve()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c, err := resolved.client()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return positional, resolved, c, nil
+}
+
+func configView(_ context.Context, args []string) error {
+	flags, opts := newFlagSet("config view", "config view [flags]")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	file, err := loadContextFile(opts.kiteconfig)
+	if err != nil {
+		return err
+	}
+
+	// Don't print tokens to terminals and logs
+	for i := range file.Contexts {
+		if file.Contexts[i].Context.Token != "" {
+			file.Contexts[i].Context.Token = "REDACTED"
+		}
+	}
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(data)
+	return err
+}
+
+func configGetContexts(_ context.Context, args []string) error {
+	flags, opts := newFlagSet("config get-contexts", "config get-contexts [flags]")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	file, err := loadContextFile(opts.kiteconfig)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tSERVER\tNAMESPACE")
+	for _, named := range file.Contexts {
+		current := ""
+		if named.Name == file.CurrentContext {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", current, named.Name, named.Context.Server, named.Context.Namespace)
+	}
+	return w.Flush()
+}
+
+func configUseContext(_ context.Context, args []string) error {
+	flags, opts := newFlagSet("config use-context", "config use-context NAME")
+	positional, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	file, err := loadContextFile(opts.kiteconfig)
+	if err != nil {
+		return err
+	}
+	if file.find(positional[0]) == nil {
+		return fmt.Errorf("context %q not found in %s", positional[0], opts.kiteconfig)
+	}
+	file.CurrentContext = positional[0]
+	return file.save(opts.kiteconfig)
+}
+
+func configSetContext(_ context.Context, args []string) error {
+	flags, opts := newFlagSet("config set-context", "config set-context NAME [--server URL] [-n NAMESPACE] [--token TOKEN | --token-file FILE]")
+	tokenFile := flags.String("token-file", "", "file to read the bearer token from before every request")
+	positional, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	file, err := loadContextFile(opts.kiteconfig)
+	if err != nil {
+		return err
+	}
+
+	named := file.find(positional[0])
+	if named == nil {
+		file.Contexts = append(file.Contexts, namedContext{Name: positional[0]})
+		named = &file.Contexts[len(file.Contexts)-1]
+	}
+	// Only the settings given are changed
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "server":
+			named.Context.Server = opts.server
+		case "namespace", "n":
+			named.Context.Namespace = opts.namespace
+		case "token":
+			named.Context.Token, named.Context.TokenFile = opts.token, ""
+		case "token-file":
+			named.Context.TokenFile, named.Context.Token = *tokenFile, ""
+		}
+	})
+	if file.CurrentContext == "" {
+		file.CurrentContext = named.Name
+	}
+	return file.save(opts.kiteconfig)
+}
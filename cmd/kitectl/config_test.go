@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Helper function to run commands against a new context file, without the environment of the test run
+func useContextFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "kite", "config")
+	t.Setenv("KITECONFIG", path)
+	t.Setenv("KITE_SERVER", "")
+	t.Setenv("KITE_NAMESPACE", "")
+	t.Setenv("KITE_TOKEN", "")
+	return path
+}
+
+// Helper function to collect what commands write to stdout
+func captureOutput(t *testing.T) *bytes.Buffer {
+	output := &bytes.Buffer{}
+	previous := stdout
+	stdout = output
+	t.Cleanup(func() { stdout = previous })
+	return output
+}
+
+func TestLoadContextFileMissing(t *testing.T) {
+	file, err := loadContextFile(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.APIVersion != "v1" || file.Kind != "Config" || len(file.Contexts) != 0 {
+		t.Errorf("missing file loaded as %+v, want an empty config", file)
+	}
+}
+
+func TestLoadContextFileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("contexts: {"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadContextFile(path); err == nil || !strings.Contains(err.Error(), "invalid context file") {
+		t.Errorf("error = %v, want an invalid context file", err)
+	}
+}
+
+func TestContextFileSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kite", "config")
+	file := &contextFile{APIVersion: "v1", Kind: "Config", CurrentContext: "prod", Contexts: []namedContext{
+		{Name: "prod", Context: contextSpec{Server: "https://kite.example.com", Namespace: "team-a", Token: "s3cret"}},
+		{Name: "local", Context: contextSpec{Server: "http://localhost:8080", TokenFile: "/var/run/token"}},
+	}}
+	if err := file.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("context file permissions = %o, want 600 as it holds tokens", perm)
+	}
+
+	loaded, err := loadContextFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.CurrentContext != "prod" || len(loaded.Contexts) != 2 {
+		t.Fatalf("loaded %+v, want the contexts saved", loaded)
+	}
+	if got := loaded.find("local"); got == nil || got.Context != file.Contexts[1].Context {
+		t.Errorf("local context = %+v, want %+v", got, file.Contexts[1])
+	}
+	if loaded.find("staging") != nil {
+		t.Error("found a context that wasn't saved")
+	}
+}
+
+func TestContextFileSaveWithoutPath(t *testing.T) {
+	if err := (&contextFile{}).save(""); err == nil {
+		t.Error("saving without a path should fail")
+	}
+}
+
+func TestConfigCommands(t *testing.T) {
+	path := useContextFile(t)
+	ctx := context.Background()
+
+	steps := [][]string{
+		{"prod", "--server", "https://kite.example.com", "-n", "team-a", "--token", "s3cret"},
+		{"local", "--server", "http://localhost:8080", "--token-file", "/var/run/token"},
+		// Only the settings given change, and a token file replaces the token
+		{"prod", "--namespace", "team-b", "--token-file", "/etc/kite/token"},
+	}
+	for _, args := range steps {
+		if err := configSetContext(ctx, args); err != nil {
+			t.Fatalf("set-context %v: %v", args, err)
+		}
+	}
+
+	file, err := loadContextFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.CurrentContext != "prod" {
+		t.Errorf("current context = %q, want the first context set", file.CurrentContext)
+	}
+	want := contextSpec{Server: "https://kite.example.com", Namespace: "team-b", TokenFile: "/etc/kite/token"}
+	if got := file.find("prod").Context; got != want {
+		t.Errorf("prod context = %+v, want %+v", got, want)
+	}
+
+	if err := configUseContext(ctx, []string{"local"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := configUseContext(ctx, []string{"staging"}); err == nil {
+		t.Error("use-context of a missing context should fail")
+	}
+	if file, err = loadContextFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if file.CurrentContext != "local" {
+		t.Errorf("current context = %q, want local", file.CurrentContext)
+	}
+
+	output := captureOutput(t)
+	if err := configGetContexts(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[2], "*") || !strings.Contains(lines[2], "local") {
+		t.Errorf("get-contexts printed:\n%s\nwant local marked as current", output)
+	}
+}
+
+func TestConfigViewRedactsTokens(t *testing.T) {
+	useContextFile(t)
+	ctx := context.Background()
+	if err := configSetContext(ctx, []string{"prod", "--server", "https://kite.example.com", "--token", "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureOutput(t)
+	if err := configView(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output.String(), "s3cret") || !strings.Contains(output.String(), "token: REDACTED") {
+		t.Errorf("config view printed:\n%s\nwant the token redacted", output)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	path := useContextFile(t)
+	file := &contextFile{CurrentContext: "prod", Contexts: []namedContext{
+		{Name: "prod", Context: contextSpec{Server: "https://kite.example.com", Namespace: "team-a", TokenFile: "/var/run/token"}},
+		{Name: "local", Context: contextSpec{Server: "http://localhost:8080"}},
+	}}
+	if err := file.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    globalOptions
+		want    contextSpec
+		wantErr string
+	}{
+		{name: "current context", opts: globalOptions{}, want: file.Contexts[0].Context},
+		{name: "context flag", opts: globalOptions{context: "local"}, want: file.Contexts[1].Context},
+		{name: "flags override the context", opts: globalOptions{server: "http://other", namespace: "team-b"}, want: contextSpec{Server: "http://other", Namespace: "team-b", TokenFile: "/var/run/token"}},
+		{name: "token flag replaces the token file", opts: globalOptions{token: "t0ken"}, want: contextSpec{Server: "https://kite.example.com", Namespace: "team-a", Token: "t0ken"}},
+		{name: "missing context", opts: globalOptions{context: "staging"}, wantErr: `context "staging" not found`},
+		{name: "unknown output", opts: globalOptions{output: "xml"}, wantErr: `unknown output format "xml"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.kiteconfig = path
+			if tt.opts.output == "" {
+				tt.opts.output = "table"
+			}
+			resolved, err := tt.opts.resolve()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolved.contextSpec != tt.want {
+				t.Errorf("resolved %+v, want %+v", resolved.contextSpec, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveWithoutServer(t *testing.T) {
+	opts := globalOptions{kiteconfig: useContextFile(t), output: "table"}
+	if _, err := opts.resolve(); err == nil || !strings.Contains(err.Error(), "no server") {
+		t.Errorf("error = %v, want no server", err)
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/client"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// How long to wait before reconnecting a watch that was disconnected
+const watchReconnectWait = 2 * time.Second
+
+// Helper function to add the issue filter flags, returning a function that builds the filters once flags are parsed
+func addFilterFlags(flags *flag.FlagSet) func(namespace string) client.IssueFilters {
+	severity := flags.String("severity", "", "only issues with this severity")
+	issueType := flags.String("type", "", "only issues of this type")
+	state := flags.String("state", "", "only issues in this state, ACTIVE or RESOLVED")
+	resourceType := flags.String("resource-type", "", "only issues for this kind of resource")
+	resourceName := flags.String("resource-name", "", "only issues for this resource")
+	search := flags.String("search", "", "full-text search of titles, descriptions and scopes")
+	query := flags.String("q", "", "structured filter, e.g. 'severity>=major AND detectedAt>now-7d'")
+	return func(namespace string) client.IssueFilters {
+		return client.IssueFilters{
+			Namespace:    namespace,
+			Severity:     models.Severity(*severity),
+			IssueType:    models.IssueType(*issueType),
+			State:        models.IssueState(strings.ToUpper(*state)),
+			ResourceType: *resourceType,
+			ResourceName: *resourceName,
+			Search:       *search,
+			Q:            *query,
+		}
+	}
+}
+
+func issuesList(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("issues list", "issues list [flags]")
+	filters := addFilterFlags(flags)
+	sort := flags.String("sort", "", "comma separated fields to sort by, prefixed with - for descending order")
+	limit := flags.Int("limit", 50, "number of issues to list")
+	all := flags.Bool("all", false, "list every matching issue instead of the first page")
+	watch := flags.Bool("watch", false, "after listing, stream changes to matching issues until interrupted")
+	_, resolved, c, err := setup(flags, opts, args, 0)
+	if err != nil {
+		return err
+	}
+	if err := resolved.requireNamespace(); err != nil {
+		return err
+	}
+
+	issueFilters := filters(resolved.Namespace)
+	listOpts := client.ListOptions{Sort: *sort, Limit: *limit}
+	issues := []models.Issue{}
+	if *all {
+		for issue, err := range c.AllIssues(ctx, issueFilters, listOpts) {
+			if err != nil {
+				return err
+			}
+			issues = append(issues, issue)
+		}
+	} else {
+		page, err := c.ListIssues(ctx, issueFilters, listOpts)
+		if err != nil {
+			return err
+		}
+		issues = page.Data
+	}
+
+	if !*watch {
+		return printResult(stdout, resolved.output, issues, issueTable(issues...))
+	}
+
+	// Watched lists are streamed item by item, the same as the changes that follow
+	if resolved.output == "table" {
+		fmt.Fprintln(stdout, strings.Join(append([]string{"EVENT"}, issueHeaders...), "   "))
+	}
+	for _, issue := range issues {
+		row := append([]string{"LISTED"}, issueRow(issue)...)
+		if err := printStreamed(stdout, resolved.output, issue, row); err != nil {
+			return err
+		}
+	}
+	return watchIssues(ctx, c, issueFilters, resolved.output)
+}
+
+// Helper function to print changes to issues until the context is cancelled, reconnecting when the stream drops
+func watchIssues(ctx context.Context, c *client.Client, filters client.IssueFilters, output string) error {
+	lastEventID := ""
+	for {
+		stream, err := c.StreamIssues(ctx, filters, lastEventID)
+		if err == nil {
+			for {
+				var event *client.IssueEvent
+				event, err = stream.Next()
+				if err != nil {
+					break
+				}
+				lastEventID = event.ID
+				streamed := map[string]any{"type": event.Type, "id": event.ID, "issue": event.Issue}
+				if err = printStreamed(stdout, output, streamed, eventRow(event)); err != nil {
+					stream.Close()
+					return err
+				}
+			}
+			stream.Close()
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Errors from the API won't go away by reconnecting
+		var apiErr *client.Error
+		if errors.As(err, &apiErr) {
+			return err
+		}
+		if !errors.Is(err, io.EOF) {
+			fmt.Fprintln(os.Stderr, "Watch disconnected, reconnecting:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchReconnectWait):
+		}
+	}
+}
+
+func issuesGet(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("issues get", "issues get ID [flags]")
+	positional, resolved, c, err := setup(flags, opts, args, 1)
+	if err != nil {
+		return err
+	}
+
+	issue, err := c.GetIssue(ctx, resolved.Namespace, positional[0])
+	if err != nil {
+		return err
+	}
+	return printResult(stdout, resolved.output, issue, issueDetailTable(*issue))
+}
+
+func issuesCreate(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("issues create", "issues create (-f FILE | --title TITLE --description TEXT --severity SEVERITY --type TYPE --resource-type TYPE --resource-name NAME) [flags]")
+	file := flags.String("f", "", "JSON file with the issue, in the format of POST /api/v1/issues, - for stdin")
+	title := flags.String("title", "", "issue title")
+	description := flags.String("description", "", "issue description")
+	severity := flags.String("severity", "", "info, minor, major or critical")
+	issueType := flags.String("type", "", "build, test, release, dependency or pipeline")
+	resourceType := flags.String("resource-type", "", "kind of resource the issue is about, such as pipelinerun")
+	resourceName := flags.String("resource-name", "", "name of the resource the issue is about")
+	resourceNamespace := flags.String("resource-namespace", "", "namespace of the resource, when it's not the issue's")
+	var links []dto.CreateLinkRequest
+	flags.Func("link", "link to add as TITLE=URL, can be repeated", func(value string) error {
+		linkTitle, url, ok := strings.Cut(value, "=")
+		if !ok {
+			return errors.New("links must be TITLE=URL")
+		}
+		links = append(links, dto.CreateLinkRequest{Title: linkTitle, URL: url})
+		return nil
+	})
+	_, resolved, c, err := setup(flags, opts, args, 0)
+	if err != nil {
+		return err
+	}
+
+	req := dto.CreateIssueRequest{
+		Title:       *title,
+		Description: *description,
+		Severity:    models.Severity(*severity),
+		IssueType:   models.IssueType(*issueType),
+		Scope: dto.ScopeReqBody{
+			ResourceType:      *resourceType,
+			ResourceName:      *resourceName,
+			ResourceNamespace: *resourceNamespace,
+		},
+		Links: links,
+	}
+	if *file != "" {
+		if req, err = readIssueFile(*file); err != nil {
+			return err
+		}
+	}
+	if req.Namespace == "" {
+		req.Namespace = resolved.Namespace
+	}
+	if req.Namespace == "" {
+		return resolved.requireNamespace()
+	}
+
+	issue, err := c.CreateIssue(ctx, req)
+	if err != nil {
+		return err
+	}
+	return printResult(stdout, resolved.output, issue, issueTable(*issue))
+}
+
+// Helper function to read an issue to create from a JSON file
+func readIssueFile(path string) (dto.CreateIssueRequest, error) {
+	var req dto.CreateIssueRequest
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return req, err
+		}
+		defer file.Close()
+		input = file
+	}
+	if err := json.NewDecoder(input).Decode(&req); err != nil {
+		return req, fmt.Errorf("invalid issue file: %w", err)
+	}
+	return req, nil
+}
+
+func issuesResolve(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("issues resolve", "issues resolve ID [flags]")
+	version := flags.Int64("if-version", 0, "only resolve the issue if it's still at this version")
+	positional, resolved, c, err := setup(flags, opts, args, 1)
+	if err != nil {
+		return err
+	}
+
+	issue, err := c.ResolveIssue(ctx, resolved.Namespace, positional[0], *version)
+	if err != nil {
+		return err
+	}
+	return printResult(stdout, resolved.output, issue, issueTable(*issue))
+}
+
+func issuesDelete(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("issues delete", "issues delete ID [flags]")
+	version := flags.Int64("if-version", 0, "only delete the issue if it's still at this version")
+	positional, resolved, c, err := setup(flags, opts, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if err := c.DeleteIssue(ctx, resolved.Namespace, positional[0], *version); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Deleted issue %s\n", positional[0])
+	return nil
+}
+
+func relatedAdd(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("related add", "related add ID RELATED_ID [flags]")
+	positional, resolved, c, err := setup(flags, opts, args, 2)
+	if err != nil {
+		return err
+	}
+
+	if err := c.AddRelatedIssue(ctx, resolved.Namespace, positional[0], positional[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Related issue %s to %s\n", positional[0], positional[1])
+	return nil
+}
+
+func relatedRemove(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("related rm", "related rm ID RELATED_ID [flags]")
+	positional, resolved, c, err := setup(flags, opts, args, 2)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RemoveRelatedIssue(ctx, resolved.Namespace, positional[0], positional[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Removed relationship between %s and %s\n", positional[0], positional[1])
+	return nil
+}
+
+func stats(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("stats", "stats [flags]")
+	filters := addFilterFlags(flags)
+	groupBy := flags.String("group-by", "severity", "comma separated fields to group by, day buckets issues by day")
+	dateField := flags.String("date-field", "", "date bucketed by day, detectedAt (the default) or resolvedAt")
+	_, resolved, c, err := setup(flags, opts, args, 0)
+	if err != nil {
+		return err
+	}
+	if err := resolved.requireNamespace(); err != nil {
+		return err
+	}
+
+	result, err := c.GetIssueStats(ctx, filters(resolved.Namespace), client.StatsOptions{GroupBy: *groupBy, DateField: *dateField})
+	if err != nil {
+		return err
+	}
+
+	t := table{}
+	for _, field := range result.GroupBy {
+		t.headers = append(t.headers, strings.ToUpper(field))
+	}
+	t.headers = append(t.headers, "COUNT")
+	for _, group := range result.Groups {
+		var row []string
+		for _, field := range result.GroupBy {
+			value := "<none>"
+			if v := group.Key[field]; v != nil {
+				value = *v
+			}
+			row = append(row, value)
+		}
+		t.rows = append(t.rows, append(row, strconv.FormatInt(group.Count, 10)))
+	}
+	if len(result.GroupBy) > 0 {
+		total := make([]string, len(result.GroupBy))
+		total[0] = "TOTAL"
+		t.rows = append(t.rows, append(total, strconv.FormatInt(result.Total, 10)))
+	} else {
+		t.rows = append(t.rows, []string{strconv.FormatInt(result.Total, 10)})
+	}
+	return printResult(stdout, resolved.output, result, t)
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/konflux-ci/kite/pkg/client"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// apiRequest is a request the fake API received
+type apiRequest struct {
+	method  string
+	path    string
+	query   url.Values
+	header  http.Header
+	body    string
+	pattern string
+}
+
+// fakeAPI serves the issues API from testIssues, recording the requests it gets
+type fakeAPI struct {
+	server string
+
+	mu       sync.Mutex
+	requests []apiRequest
+}
+
+func (f *fakeAPI) last(t *testing.T) apiRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.requests) == 0 {
+		t.Fatal("no request was sent")
+	}
+	return f.requests[len(f.requests)-1]
+}
+
+// Helper function to start the fake API, with a context file pointing kitectl at it in team-a
+func newFakeAPI(t *testing.T) *fakeAPI {
+	api := &fakeAPI{}
+	mux := http.NewServeMux()
+	issue := func(w http.ResponseWriter, r *http.Request) {
+		for _, issue := range testIssues {
+			if issue.ID == r.PathValue("id") {
+				writeJSON(w, http.StatusOK, issue)
+				return
+			}
+		}
+		writeJSON(w, http.StatusNotFound, dto.Problem{Title: "Not Found", Status: http.StatusNotFound, Detail: "issue not found", Code: "NOT_FOUND"})
+	}
+	mux.HandleFunc("GET /api/v1/issues/{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, dto.IssueResponse{Data: testIssues, Total: int64(len(testIssues)), Limit: 50})
+	})
+	mux.HandleFunc("POST /api/v1/issues/{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusCreated, testIssues[0])
+	})
+	mux.HandleFunc("GET /api/v1/issues/{id}", issue)
+	mux.HandleFunc("POST /api/v1/issues/{id}/resolve", issue)
+	mux.HandleFunc("DELETE /api/v1/issues/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /api/v1/issues/{id}/related", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("DELETE /api/v1/issues/{id}/related/{related}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /api/v1/issues/stats", func(w http.ResponseWriter, r *http.Request) {
+		critical := "critical"
+		writeJSON(w, http.StatusOK, dto.IssueStatsResponse{GroupBy: []string{"severity"}, Total: 3, Groups: []dto.IssueStatsGroup{
+			{Key: map[string]*string{"severity": &critical}, Count: 2},
+			{Key: map[string]*string{"severity": nil}, Count: 1},
+		}})
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, pattern := mux.Handler(r)
+		api.mu.Lock()
+		api.requests = append(api.requests, apiRequest{method: r.Method, path: r.URL.Path, query: r.URL.Query(), header: r.Header, body: string(body), pattern: pattern})
+		api.mu.Unlock()
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	api.server = server.URL
+
+	path := useContextFile(t)
+	file := &contextFile{CurrentContext: "test", Contexts: []namedContext{
+		{Name: "test", Context: contextSpec{Server: server.URL, Namespace: "team-a", Token: "s3cret"}},
+	}}
+	if err := file.save(path); err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestIssuesList(t *testing.T) {
+	api := newFakeAPI(t)
+	output := captureOutput(t)
+
+	err := issuesList(context.Background(), []string{"--severity", "critical", "--state", "active", "-q", "detectedAt>now-7d", "--sort", "-severity", "--limit", "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := api.last(t)
+	if req.pattern != "GET /api/v1/issues/{$}" {
+		t.Fatalf("sent %s %s, want an issue list", req.method, req.path)
+	}
+	for param, want := range map[string]string{
+		"namespace": "team-a",
+		"severity":  "critical",
+		"state":     "ACTIVE",
+		"q":         "detectedAt>now-7d",
+		"sort":      "-severity",
+		"limit":     "10",
+	} {
+		if got := req.query.Get(param); got != want {
+			t.Errorf("%s = %q, want %q", param, got, want)
+		}
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer s3cret" {
+		t.Errorf("Authorization = %q, want the context's token", got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.HasPrefix(lines[1], testIssues[0].ID) {
+		t.Errorf("printed:\n%s\nwant a table of the issues", output)
+	}
+}
+
+func TestIssuesListJSON(t *testing.T) {
+	newFakeAPI(t)
+	output := captureOutput(t)
+
+	if err := issuesList(context.Background(), []string{"-o", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	var issues []models.Issue
+	if err := json.Unmarshal(output.Bytes(), &issues); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, output)
+	}
+	if len(issues) != len(testIssues) {
+		t.Errorf("printed %d issues, want %d", len(issues), len(testIssues))
+	}
+}
+
+func TestIssuesListRequiresNamespace(t *testing.T) {
+	api := newFakeAPI(t)
+
+	err := issuesList(context.Background(), []string{"--kiteconfig", useContextFile(t), "--server", api.server})
+	if err == nil || !strings.Contains(err.Error(), "no namespace") {
+		t.Errorf("error = %v, want no namespace", err)
+	}
+	if len(api.requests) != 0 {
+		t.Errorf("sent %d requests without a namespace", len(api.requests))
+	}
+}
+
+func TestIssuesGet(t *testing.T) {
+	api := newFakeAPI(t)
+	output := captureOutput(t)
+
+	if err := issuesGet(context.Background(), []string{testIssues[1].ID, "-n", "team-b"}); err != nil {
+		t.Fatal(err)
+	}
+	req := api.last(t)
+	if req.path != "/api/v1/issues/"+testIssues[1].ID || req.query.Get("namespace") != "team-b" {
+		t.Errorf("sent %s?%s, want the issue in team-b", req.path, req.query.Encode())
+	}
+	if !strings.Contains(output.String(), "Flaky test") || !strings.Contains(output.String(), "FIELD") {
+		t.Errorf("printed:\n%s\nwant the issue's details", output)
+	}
+}
+
+func TestIssuesGetNotFound(t *testing.T) {
+	newFakeAPI(t)
+	captureOutput(t)
+
+	err := issuesGet(context.Background(), []string{"00000000-0000-0000-0000-000000000000"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Detail != "issue not found" {
+		t.Errorf("error = %v, want the API's not found error", err)
+	}
+}
+
+func TestIssuesGetUsage(t *testing.T) {
+	newFakeAPI(t)
+	if err := issuesGet(context.Background(), nil); !errors.Is(err, errUsage) {
+		t.Errorf("error = %v, want a usage error without an ID", err)
+	}
+}
+
+func TestIssuesCreate(t *testing.T) {
+	api := newFakeAPI(t)
+	captureOutput(t)
+
+	err := issuesCreate(context.Background(), []string{
+		"--title", "Build failed", "--description", "The build failed", "--severity", "critical", "--type", "build",
+		"--resource-type", "component", "--resource-name", "api", "--link", "Logs=https://logs.example.com/1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := api.last(t)
+	if req.pattern != "POST /api/v1/issues/{$}" || req.query.Get("namespace") != "team-a" {
+		t.Fatalf("sent %s %s?%s, want an issue created in team-a", req.method, req.path, req.query.Encode())
+	}
+	var body dto.CreateIssueRequest
+	if err := json.Unmarshal([]byte(req.body), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Namespace != "team-a" || body.Severity != models.SeverityCritical || body.Scope.ResourceName != "api" ||
+		len(body.Links) != 1 || body.Links[0].URL != "https://logs.example.com/1" {
+		t.Errorf("sent %+v", body)
+	}
+}
+
+func TestIssuesCreateInvalidLink(t *testing.T) {
+	newFakeAPI(t)
+	if err := issuesCreate(context.Background(), []string{"--link", "no-url"}); !errors.Is(err, errUsage) {
+		t.Errorf("error = %v, want a usage error", err)
+	}
+}
+
+func TestIssuesResolveAndDelete(t *testing.T) {
+	api := newFakeAPI(t)
+	captureOutput(t)
+	id := testIssues[0].ID
+
+	if err := issuesResolve(context.Background(), []string{id, "--if-version", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	req := api.last(t)
+	if req.pattern != "POST /api/v1/issues/{id}/resolve" || req.header.Get("If-Match") != `"3"` {
+		t.Errorf("sent %s %s with If-Match %q, want a resolve of version 3", req.method, req.path, req.header.Get("If-Match"))
+	}
+
+	if err := issuesDelete(context.Background(), []string{id}); err != nil {
+		t.Fatal(err)
+	}
+	req = api.last(t)
+	if req.pattern != "DELETE /api/v1/issues/{id}" || req.header.Get("If-Match") != "" {
+		t.Errorf("sent %s %s with If-Match %q, want an unconditional delete", req.method, req.path, req.header.Get("If-Match"))
+	}
+}
+
+func TestRelated(t *testing.T) {
+	api := newFakeAPI(t)
+	source, target := testIssues[0].ID, testIssues[1].ID
+
+	if err := relatedAdd(context.Background(), []string{source, target}); err != nil {
+		t.Fatal(err)
+	}
+	req := api.last(t)
+	if req.path != "/api/v1/issues/"+source+"/related" || !strings.Contains(req.body, target) {
+		t.Errorf("sent %s %s %s, want %s related to %s", req.method, req.path, req.body, source, target)
+	}
+
+	if err := relatedRemove(context.Background(), []string{source, target}); err != nil {
+		t.Fatal(err)
+	}
+	if req = api.last(t); req.method != http.MethodDelete || req.path != "/api/v1/issues/"+source+"/related/"+target {
+		t.Errorf("sent %s %s, want the relationship removed", req.method, req.path)
+	}
+}
+
+func TestStats(t *testing.T) {
+	api := newFakeAPI(t)
+	output := captureOutput(t)
+
+	if err := stats(context.Background(), []string{"--group-by", "severity", "--type", "build"}); err != nil {
+		t.Fatal(err)
+	}
+	req := api.last(t)
+	if req.query.Get("groupBy") != "severity" || req.query.Get("issueType") != "build" {
+		t.Errorf("sent %s?%s", req.path, req.query.Encode())
+	}
+
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+	want := [][]string{{"SEVERITY", "COUNT"}, {"critical", "2"}, {"<none>", "1"}, {"TOTAL", "3"}}
+	if len(rows) != len(want) {
+		t.Fatalf("printed:\n%s\nwant %v", output, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], " ") != strings.Join(want[i], " ") {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+}
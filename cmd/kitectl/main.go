@@ -0,0 +1,112 @@
+// kitectl is a command line client for the Konflux Issues API
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `kitectl triages Konflux issues from a terminal or a pipeline.
+
+Usage:
+  kitectl issues list [flags]            List issues, --watch streams changes
+  kitectl issues get ID                  Show an issue
+  kitectl issues create [flags]          Create an issue
+  kitectl issues resolve ID              Resolve an issue
+  kitectl issues delete ID               Delete an issue
+  kitectl related add ID RELATED_ID      Relate two issues
+  kitectl related rm ID RELATED_ID       Remove a relationship
+  kitectl stats [flags]                  Count issues
+  kitectl webhook pipeline-failure       Report a failed pipeline run
+  kitectl webhook pipeline-success       Report a pipeline run that succeeded
+  kitectl webhook test                   Check the webhooks create and resolve an issue
+  kitectl config view|get-contexts|use-context|set-context
+
+Every command takes:
+  --kiteconfig FILE   context file ($KITECONFIG, ~/.kite/config by default)
+  --context NAME      context to use instead of the current one
+  --server URL        API server ($KITE_SERVER)
+  -n, --namespace NS  namespace ($KITE_NAMESPACE)
+  --token TOKEN       bearer token ($KITE_TOKEN)
+  -o, --output FMT    table, json or yaml
+
+Run a command with -h for its flags.
+`
+
+// stdout is where commands write their results, messages about what they did go to stderr
+var stdout io.Writer = os.Stdout
+
+// errUsage is returned by commands called with invalid arguments, after printing how to use them
+var errUsage = errors.New("invalid usage")
+
+// command runs a subcommand with the arguments after its name
+type command func(ctx context.Context, args []string) error
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// Helper function to run the command line, returning the exit code
+func run(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	commands := map[string]map[string]command{
+		"issues": {
+			"list":    issuesList,
+			"get":     issuesGet,
+			"create":  issuesCreate,
+			"resolve": issuesResolve,
+			"delete":  issuesDelete,
+		},
+		"related": {
+			"add": relatedAdd,
+			"rm":  relatedRemove,
+		},
+		"webhook": {
+			"pipeline-failure": webhookPipelineFailure,
+			"pipeline-success": webhookPipelineSuccess,
+			"test":             webhookTest,
+		},
+		"config": {
+			"view":         configView,
+			"get-contexts": configGetContexts,
+			"use-context":  configUseContext,
+			"set-context":  configSetContext,
+		},
+	}
+
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	var cmd command
+	var cmdArgs []string
+	if args[0] == "stats" {
+		cmd, cmdArgs = stats, args[1:]
+	} else if group, ok := commands[args[0]]; ok && len(args) > 1 && group[args[1]] != nil {
+		cmd, cmdArgs = group[args[1]], args[2:]
+	} else {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	err := cmd(ctx, cmdArgs)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
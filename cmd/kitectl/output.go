@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/client"
+	"github.com/konflux-ci/kite/pkg/models"
+	"sigs.k8s.io/yaml"
+)
+
+// table is how a result is shown in table output
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// Helper function to write a result in the output format, as a table or the API's JSON as JSON or YAML
+func printResult(w io.Writer, format string, v any, t table) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case "yaml":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// Helper function to write one streamed item. JSON is written one item per line and YAML as separate documents,
+// so scripts can read items as they arrive.
+func printStreamed(w io.Writer, format string, v any, row []string) error {
+	switch format {
+	case "json":
+		return json.NewEncoder(w).Encode(v)
+	case "yaml":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "---\n%s", data)
+		return err
+	}
+	_, err := fmt.Fprintln(w, strings.Join(row, "   "))
+	return err
+}
+
+var issueHeaders = []string{"ID", "SEVERITY", "TYPE", "STATE", "SCOPE", "AGE", "TITLE"}
+
+// Helper function to show an issue as a table row
+func issueRow(issue models.Issue) []string {
+	return []string{
+		issue.ID,
+		string(issue.Severity),
+		string(issue.IssueType),
+		string(issue.State),
+		issue.Scope.ResourceType + "/" + issue.Scope.ResourceName,
+		age(issue.DetectedAt),
+		issue.Title,
+	}
+}
+
+func issueTable(issues ...models.Issue) table {
+	t := table{headers: issueHeaders}
+	for _, issue := range issues {
+		t.rows = append(t.rows, issueRow(issue))
+	}
+	return t
+}
+
+// Helper function to show an issue with its details, for commands that return a single issue
+func issueDetailTable(issue models.Issue) table {
+	t := table{headers: []string{"FIELD", "VALUE"}}
+	add := func(field, value string) {
+		if value != "" {
+			t.rows = append(t.rows, []string{field, value})
+		}
+	}
+	add("ID", issue.ID)
+	add("Title", issue.Title)
+	add("Namespace", issue.Namespace)
+	add("Severity", string(issue.Severity))
+	add("Type", string(issue.IssueType))
+	add("State", string(issue.State))
+	add("Scope", issue.Scope.ResourceType+"/"+issue.Scope.ResourceName)
+	add("Detected", formatTime(&issue.DetectedAt))
+	add("Resolved", formatTime(issue.ResolvedAt))
+	if issue.Assignee != nil {
+		add("Assignee", *issue.Assignee)
+	}
+	add("Labels", strings.Join(issue.Labels, ", "))
+	add("Version", fmt.Sprint(issue.Version))
+	for _, link := range issue.Links {
+		add("Link", link.Title+" "+link.URL)
+	}
+	add("Description", strings.ReplaceAll(issue.Description, "\n", " "))
+	return t
+}
+
+// Helper function to show a streamed change to an issue as a table row
+func eventRow(event *client.IssueEvent) []string {
+	return append([]string{strings.ToUpper(event.Type)}, issueRow(event.Issue)...)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(time.RFC3339) + " (" + age(*t) + " ago)"
+}
+
+// Helper function to show how long ago a time was in the largest unit, like kubectl
+func age(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"sigs.k8s.io/yaml"
+)
+
+var testIssues = []models.Issue{
+	{
+		ID:         "11111111-1111-1111-1111-111111111111",
+		Title:      "Build failed",
+		Namespace:  "team-a",
+		Severity:   models.SeverityCritical,
+		IssueType:  models.IssueTypeBuild,
+		State:      models.IssueStateActive,
+		DetectedAt: time.Now().Add(-3 * time.Hour),
+		Scope:      models.IssueScope{ResourceType: "component", ResourceName: "api"},
+		Labels:     []string{},
+	},
+	{
+		ID:         "22222222-2222-2222-2222-222222222222",
+		Title:      "Flaky test",
+		Namespace:  "team-a",
+		Severity:   models.SeverityMinor,
+		IssueType:  models.IssueTypeTest,
+		State:      models.IssueStateResolved,
+		DetectedAt: time.Now().Add(-72 * time.Hour),
+		Scope:      models.IssueScope{ResourceType: "pipelinerun", ResourceName: "api-on-push"},
+		Labels:     []string{"flaky"},
+	},
+}
+
+func TestPrintResultTable(t *testing.T) {
+	var output bytes.Buffer
+	if err := printResult(&output, "table", testIssues, issueTable(testIssues...)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("printed %d lines, want a header and 2 rows:\n%s", len(lines), output.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != strings.Join(issueHeaders, " ") {
+		t.Errorf("header = %q, want %v", lines[0], issueHeaders)
+	}
+	want := []string{testIssues[0].ID, "critical", "build", "ACTIVE", "component/api", "3h", "Build", "failed"}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != strings.Join(want, " ") {
+		t.Errorf("row = %q, want %v", lines[1], want)
+	}
+	if fields := strings.Fields(lines[2]); fields[5] != "3d" {
+		t.Errorf("age = %q, want 3d", fields[5])
+	}
+	// Columns are aligned
+	if strings.Index(lines[0], "SEVERITY") != strings.Index(lines[1], "critical") {
+		t.Errorf("columns aren't aligned:\n%s", output.String())
+	}
+}
+
+func TestPrintResultJSONAndYAML(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := printResult(&output, format, testIssues, issueTable(testIssues...)); err != nil {
+				t.Fatal(err)
+			}
+
+			// The API's JSON fields are printed, not the table
+			var printed []models.Issue
+			var err error
+			if format == "json" {
+				err = json.Unmarshal(output.Bytes(), &printed)
+			} else {
+				err = yaml.Unmarshal(output.Bytes(), &printed)
+			}
+			if err != nil {
+				t.Fatalf("output isn't %s: %v\n%s", format, err, output.String())
+			}
+			if len(printed) != 2 || printed[1].ID != testIssues[1].ID || printed[1].Scope.ResourceName != "api-on-push" {
+				t.Errorf("printed %+v, want the issues", printed)
+			}
+			if strings.Contains(output.String(), "SEVERITY") {
+				t.Errorf("%s output has the table header:\n%s", format, output.String())
+			}
+		})
+	}
+}
+
+func TestPrintStreamed(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "json", want: "{\"id\":\"1\"}\n{\"id\":\"2\"}\n"},
+		{format: "yaml", want: "---\nid: \"1\"\n---\nid: \"2\"\n"},
+		{format: "table", want: "CREATED   1\nUPDATED   2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var output bytes.Buffer
+			for _, item := range []struct{ id, event string }{{"1", "CREATED"}, {"2", "UPDATED"}} {
+				if err := printStreamed(&output, tt.format, map[string]string{"id": item.id}, []string{item.event, item.id}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if output.String() != tt.want {
+				t.Errorf("printed %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueDetailTable(t *testing.T) {
+	assignee := "alice"
+	issue := testIssues[1]
+	issue.Assignee = &assignee
+	issue.Version = 4
+	issue.Links = []models.Link{{Title: "Logs", URL: "https://logs.example.com/1"}}
+	issue.Description = "Failed twice\nin a row"
+
+	fields := map[string]string{}
+	for _, row := range issueDetailTable(issue).rows {
+		fields[row[0]] = row[1]
+	}
+	for field, want := range map[string]string{
+		"Assignee":    "alice",
+		"Labels":      "flaky",
+		"Version":     "4",
+		"Link":        "Logs https://logs.example.com/1",
+		"Description": "Failed twice in a row",
+	} {
+		if fields[field] != want {
+			t.Errorf("%s = %q, want %q", field, fields[field], want)
+		}
+	}
+	// Empty fields are left out
+	if _, ok := fields["Resolved"]; ok {
+		t.Errorf("detail table has a resolved time for an issue without one: %v", fields)
+	}
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{30 * time.Second, "30s"},
+		{90 * time.Second, "1m"},
+		{5 * time.Hour, "5h"},
+		{47 * time.Hour, "47h"},
+		{49 * time.Hour, "2d"},
+		{400 * 24 * time.Hour, "400d"},
+	}
+	for _, tt := range tests {
+		if got := age(time.Now().Add(-tt.ago)); got != tt.want {
+			t.Errorf("age of %s = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+	if got := age(time.Time{}); got != "" {
+		t.Errorf("age of the zero time = %q, want empty", got)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/client"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// Helper function to show a webhook result as a table
+func webhookTable(result *client.WebhookResult) table {
+	t := table{headers: []string{"STATUS", "ISSUE", "MESSAGE"}}
+	issueID := ""
+	if result.Issue != nil {
+		issueID = result.Issue.ID
+	}
+	t.rows = append(t.rows, []string{result.Status, issueID, result.Message})
+	return t
+}
+
+// Helper function to add the flags of the pipeline webhooks
+func addPipelineFlags(flags *flag.FlagSet) *string {
+	return flags.String("pipeline", "", "name of the pipeline")
+}
+
+func webhookPipelineFailure(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("webhook pipeline-failure", "webhook pipeline-failure --pipeline NAME --reason REASON [flags]")
+	pipeline := addPipelineFlags(flags)
+	reason := flags.String("reason", "", "why the pipeline failed")
+	runID := flags.String("run-id", "", "name of the pipeline run")
+	logsURL := flags.String("logs-url", "", "URL of the pipeline run logs")
+	_, resolved, c, err := setup(flags, opts, args, 0)
+	if err != nil {
+		return err
+	}
+	if err := resolved.requireNamespace(); err != nil {
+		return err
+	}
+	if *pipeline == "" || *reason == "" {
+		flags.Usage()
+		return errUsage
+	}
+
+	result, err := c.PipelineFailure(ctx, dto.PipelineFailureRequest{
+		PipelineName:  *pipeline,
+		Namespace:     resolved.Namespace,
+		FailureReason: *reason,
+		RunID:         *runID,
+		LogsURL:       *logsURL,
+	})
+	if err != nil {
+		return err
+	}
+	return printResult(stdout, resolved.output, result, webhookTable(result))
+}
+
+func webhookPipelineSuccess(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("webhook pipeline-success", "webhook pipeline-success --pipeline NAME [flags]")
+	pipeline := addPipelineFlags(flags)
+	_, resolved, c, err := setup(flags, opts, args, 0)
+	if err != nil {
+		return err
+	}
+	if err := resolved.requireNamespace(); err != nil {
+		return err
+	}
+	if *pipeline == "" {
+		flags.Usage()
+		return errUsage
+	}
+
+	result, err := c.PipelineSuccess(ctx, dto.PipelineSuccessRequest{PipelineName: *pipeline, Namespace: resolved.Namespace})
+	if err != nil {
+		return err
+	}
+	return printResult(stdout, resolved.output, result, webhookTable(result))
+}
+
+// webhookTest sends a pipeline failure and then a success for a test pipeline, checking the issue
+// is created and then resolved, so the webhooks can be checked from a namespace end to end
+func webhookTest(ctx context.Context, args []string) error {
+	flags, opts := newFlagSet("webhook test", "webhook test [--pipeline NAME] [flags]")
+	pipeline := addPipelineFlags(flags)
+	keep := flags.Bool("keep", false, "keep the test issue instead of deleting it")
+	_, resolved, c, err := setup(flags, opts, args, 0)
+	if err != nil {
+		return err
+	}
+	if err := resolved.requireNamespace(); err != nil {
+		return err
+	}
+	if *pipeline == "" {
+		*pipeline = fmt.Sprintf("kitectl-webhook-test-%d", time.Now().Unix())
+	}
+
+	t := table{headers: []string{"STEP", "RESULT", "ISSUE"}}
+	failure, err := c.PipelineFailure(ctx, dto.PipelineFailureRequest{
+		PipelineName:  *pipeline,
+		Namespace:     resolved.Namespace,
+		FailureReason: "Test failure sent by kitectl webhook test",
+	})
+	if err != nil {
+		return fmt.Errorf("pipeline-failure webhook failed: %w", err)
+	}
+	if failure.Issue == nil {
+		return errors.New("pipeline-failure webhook didn't return an issue")
+	}
+	t.rows = append(t.rows, []string{"pipeline-failure", failure.Status, failure.Issue.ID})
+
+	success, err := c.PipelineSuccess(ctx, dto.PipelineSuccessRequest{PipelineName: *pipeline, Namespace: resolved.Namespace})
+	if err != nil {
+		return fmt.Errorf("pipeline-success webhook failed: %w", err)
+	}
+	t.rows = append(t.rows, []string{"pipeline-success", success.Message, failure.Issue.ID})
+
+	issue, err := c.GetIssue(ctx, resolved.Namespace, failure.Issue.ID)
+	if err != nil {
+		return err
+	}
+	if issue.State != models.IssueStateResolved {
+		return fmt.Errorf("issue %s is %s after the pipeline-success webhook", issue.ID, issue.State)
+	}
+	t.rows = append(t.rows, []string{"check resolved", string(issue.State), issue.ID})
+
+	if !*keep {
+		if err := c.DeleteIssue(ctx, resolved.Namespace, issue.ID, 0); err != nil {
+			return fmt.Errorf("failed to delete test issue: %w", err)
+		}
+		t.rows = append(t.rows, []string{"delete", "deleted", issue.ID})
+	}
+
+	result := map[string]any{"pipeline": *pipeline, "issue": issue, "passed": true}
+	return printResult(stdout, resolved.output, result, t)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/sirupsen/logrus"
+)
+
+// runImport imports issues from a file, the same as POST /api/v1/admin/import.
+// The report is written to stdout and the exit code is returned.
+//
+//	server import [--namespace NAMESPACE] [--format csv|ndjson] [--dry-run] FILE
+func runImport(issueService *services.IssueService, args []string, logger *logrus.Logger) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server import [flags] FILE")
+		fmt.Fprintln(flags.Output(), "Imports issues from an NDJSON or CSV file, use - to read from stdin.")
+		flags.PrintDefaults()
+	}
+	namespace := flags.String("namespace", "", "namespace to import into, rows must match it (by default rows set their own)")
+	format := flags.String("format", "", "csv or ndjson (by default taken from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "validate and check for duplicates without importing")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	if *format != string(services.ImportFormatCSV) && *format != string(services.ImportFormatNDJSON) {
+		fmt.Fprintln(os.Stderr, "format must be csv or ndjson")
+		return 2
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			logger.WithError(err).Error("Failed to open import file")
+			return 1
+		}
+		defer file.Close()
+		input = file
+	}
+
+	report, err := issueService.ImportIssues(context.Background(), input, services.ImportOptions{
+		Format:    services.ImportFormat(*format),
+		Namespace: *namespace,
+		DryRun:    *dryRun,
+	})
+	if err != nil && !errors.Is(err, services.ErrInvalidImport) {
+		logger.WithError(err).Error("Failed to import issues")
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if encodeErr := encoder.Encode(report); encodeErr != nil {
+		logger.WithError(encodeErr).Error("Failed to write import report")
+		return 1
+	}
+	if report.Invalid > 0 {
+		return 1
+	}
+	return 0
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,7 +11,12 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/internal/digest"
+	"github.com/konflux-ci/kite/internal/events"
+	handler_grpc "github.com/konflux-ci/kite/internal/handlers/grpc"
 	handler_http "github.com/konflux-ci/kite/internal/handlers/http"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
 	"github.com/sirupsen/logrus"
 )
 
@@ -25,7 +31,7 @@ func main() {
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		fmt.Printf("Failed to load configuration: %w\n", err)
+		fmt.Printf("Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -50,12 +56,53 @@ func main() {
 	}
 	defer sqlDB.Close()
 
+	// Run a one-off command rather than the server when one is given
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		code := runImport(services.NewIssueService(repository.NewIssueRepository(db, logger), logger), os.Args[2:], logger)
+		sqlDB.Close()
+		os.Exit(code)
+	}
+
+	// Services shared by the HTTP and gRPC servers and the background workers
+	shared, err := handler_http.NewServices(db, cfg, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize services")
+	}
+
 	// Setup router
-	router, err := handler_http.SetupRouter(db, logger)
+	router, err := handler_http.SetupRouter(db, cfg, shared, logger)
 	if err != nil {
 		logger.WithError(err).Fatal("Failed to setup router")
 	}
 
+	// Context for background workers, cancelled on shutdown
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	// Start the email digest scheduler
+	if cfg.Digest.Enabled {
+		scheduler, err := digest.NewScheduler(cfg.Digest, shared.Issues, digest.NewSMTPMailer(cfg.Digest.SMTP), logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to setup digest scheduler")
+		}
+		go scheduler.Run(workerCtx)
+	}
+
+	// Start syncing issue states with Jira
+	if cfg.Jira.Enabled {
+		go shared.Trackers.Run(workerCtx, cfg.Jira.SyncInterval)
+	}
+
+	// Publish issue events from the outbox as CloudEvents
+	outboxRepo := repository.NewOutboxRepository(db, logger)
+	if cfg.Events.CloudEvents.Enabled {
+		sender := events.NewCloudEventsSender(cfg.Events.CloudEvents.SinkURL, cfg.Events.CloudEvents.Source)
+		dispatcher := events.NewDispatcher(outboxRepo, sender, cfg.Events.CloudEvents.PollInterval, cfg.Events.CloudEvents.BatchSize, cfg.Events.CloudEvents.MaxAttempts, logger)
+		go dispatcher.Run(workerCtx)
+	}
+	// Undispatched events are only kept around when something is dispatching them
+	go events.NewPruner(outboxRepo, cfg.Events.Retention, cfg.Events.CloudEvents.Enabled, logger).Run(workerCtx)
+
 	// Setup HTTP server with configuration
 	server := &http.Server{
 		Addr:         cfg.GetServerAddress(),
@@ -79,6 +126,20 @@ func main() {
 		}
 	}()
 
+	// Setup the gRPC server, with the same namespace checks as the REST API
+	grpcServer := handler_grpc.NewServer(shared.Issues, shared.NamespaceChecker, logger)
+	grpcListener, err := net.Listen("tcp", cfg.GetGRPCAddress())
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to listen for gRPC")
+	}
+	go func() {
+		logger.WithField("address", cfg.GetGRPCAddress()).Info("Starting gRPC Server")
+
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.WithError(err).Fatal("Failed to start gRPC server")
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown
 	// Create a channel that carries os.Signal values, buffer size 1
 	quit := make(chan os.Signal, 1)
@@ -89,17 +150,32 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopWorkers()
 
 	// Create a context with timeout for graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	// Shut down the gRPC server alongside the HTTP one, stopping it outright when calls outlast the timeout
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
 	// Shut down server
 	if err := server.Shutdown(ctx); err != nil {
 		logger.WithError(err).Error("Server forced to shutdown")
 	} else {
 		logger.Info("Server shutdown gracefully")
 	}
+
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		logger.Warn("gRPC server forced to shutdown")
+		grpcServer.Stop()
+	}
 }
 
 func setupLogger() *logrus.Logger {
@@ -0,0 +1,92 @@
+// Package apperrors holds the kinds of errors every layer returns for requests that can't be done,
+// which the HTTP and gRPC handlers respond to with the status of their kind.
+package apperrors
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/konflux-ci/kite/pkg/dto"
+)
+
+// Kinds of errors, which errors wrap so callers can tell them apart with errors.Is
+var (
+	// ErrNotFound is wrapped by errors about records that don't exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is wrapped by errors about changes that conflict with the current records
+	ErrConflict = errors.New("conflict")
+	// ErrPreconditionFailed is wrapped by errors about changes made to a version of a record that isn't the current one
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnauthenticated is wrapped by errors about callers that couldn't be identified
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is wrapped by errors about actions the caller isn't allowed to take
+	ErrForbidden = errors.New("forbidden")
+	// ErrValidation is matched by every ValidationError
+	ErrValidation = errors.New("validation failed")
+)
+
+// Error is an error with its own message that is of a more general kind, such as ErrNotFound
+type Error struct {
+	Kind    error
+	Message string
+}
+
+// New returns an error with the message passed that matches kind with errors.Is
+func New(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// ValidationError is returned when a request has invalid values, with the problem with each field.
+// Problems that aren't about a single field have no field name.
+type ValidationError struct {
+	Fields []dto.FieldError
+}
+
+// InvalidField returns a ValidationError for a single field
+func InvalidField(field, message string) *ValidationError {
+	return &ValidationError{Fields: []dto.FieldError{{Field: field, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+		if field.Field != "" {
+			messages[i] = field.Field + ": " + field.Message
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Problem is an error that is responded to with the problem code and detail it holds,
+// for requests handlers reject before reaching the services
+type Problem struct {
+	Code   string
+	Detail string
+	// Offset in the q filter where parsing failed, for invalid filters
+	Position *int
+}
+
+// NewProblem returns a Problem with one of the problem codes in dto
+func NewProblem(code, detail string) *Problem {
+	return &Problem{Code: code, Detail: detail}
+}
+
+func (e *Problem) Error() string {
+	if e.Detail == "" {
+		return e.Code
+	}
+	return e.Detail
+}
@@ -0,0 +1,79 @@
+// Package cache holds small in-memory caches shared by the API's services
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// TTL is a size bounded cache whose entries expire after a fixed time.
+// It's safe for concurrent use.
+type TTL[K comparable, V any] struct {
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[K]ttlEntry[V]
+}
+
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// NewTTL returns a cache keeping entries for ttl, holding at most maxSize entries.
+// A zero ttl disables the cache.
+func NewTTL[K comparable, V any](ttl time.Duration, maxSize int) *TTL[K, V] {
+	return &TTL[K, V]{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[K]ttlEntry[V]),
+	}
+}
+
+// Get returns the value stored for the key, if it hasn't expired
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set stores a value for the key
+func (c *TTL[K, V]) Set(key K, value V) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxSize {
+		c.evict(now)
+	}
+	c.entries[key] = ttlEntry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// Helper function to make room for a new entry, dropping expired entries or else the one expiring soonest
+func (c *TTL[K, V]) evict(now time.Time) {
+	var oldestKey K
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldest.IsZero() || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxSize {
+		delete(c.entries, oldestKey)
+	}
+}
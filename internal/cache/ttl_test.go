@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLExpiry(t *testing.T) {
+	c := NewTTL[string, int](20*time.Millisecond, 10)
+	c.Set("a", 1)
+
+	if value, ok := c.Get("a"); !ok || value != 1 {
+		t.Fatalf("Get() = %d, %v, want the stored value", value, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get() found a key that was never set")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if value, ok := c.Get("a"); ok {
+		t.Errorf("Get() = %d after the entry expired", value)
+	}
+
+	// Setting the key again restarts its time
+	c.Set("a", 2)
+	if value, ok := c.Get("a"); !ok || value != 2 {
+		t.Errorf("Get() = %d, %v, want the new value", value, ok)
+	}
+}
+
+func TestTTLEviction(t *testing.T) {
+	c := NewTTL[string, int](time.Minute, 2)
+	c.Set("a", 1)
+	time.Sleep(time.Millisecond)
+	c.Set("b", 2)
+
+	// Updating a key doesn't make room
+	c.Set("a", 3)
+	if _, ok := c.Get("b"); !ok {
+		t.Fatal("updating a key evicted another one")
+	}
+
+	// The entry expiring soonest makes room for a new one
+	c.Set("c", 4)
+	if _, ok := c.Get("b"); ok {
+		t.Error("the entry expiring soonest wasn't evicted")
+	}
+	for key, want := range map[string]int{"a": 3, "c": 4} {
+		if value, ok := c.Get(key); !ok || value != want {
+			t.Errorf("Get(%q) = %d, %v, want %d", key, value, ok, want)
+		}
+	}
+}
+
+func TestTTLEvictsExpiredEntriesFirst(t *testing.T) {
+	c := NewTTL[string, int](20*time.Millisecond, 2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	time.Sleep(30 * time.Millisecond)
+
+	c.Set("c", 3)
+	if len(c.entries) != 1 {
+		t.Errorf("cache holds %d entries, want only the new one", len(c.entries))
+	}
+}
+
+func TestTTLDisabled(t *testing.T) {
+	c := NewTTL[string, int](0, 10)
+	c.Set("a", 1)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a zero TTL cache stored a value")
+	}
+}
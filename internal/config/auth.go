@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// AuthConfig holds configuration for checking callers' access to namespaces
+type AuthConfig struct {
+	// Access to a namespace callers need to read its issues
+	Read ResourceAccess
+	// Access to a namespace callers need to create and change its issues
+	Write ResourceAccess
+	// How long token and access reviews are reused for, short so revoked access is noticed quickly
+	CacheTTL time.Duration
+	// Maximum number of reviews kept in each cache
+	CacheSize int
+	// OpenID Connect providers whose tokens are accepted along with Kubernetes tokens, from OIDC_CONFIG_FILE
+	OIDC []OIDCIssuerConfig
+}
+
+// ResourceAccess is a verb on a Kubernetes resource, checked with a SubjectAccessReview in the namespace
+type ResourceAccess struct {
+	// API group of the resource, empty for the core group
+	Group    string
+	Resource string
+	Verb     string
+}
+
+// Validate validates the auth configuration
+func (a AuthConfig) Validate() error {
+	for operation, access := range map[string]ResourceAccess{"read": a.Read, "write": a.Write} {
+		if access.Resource == "" || access.Verb == "" {
+			return fmt.Errorf("a resource and verb are required for %s access", operation)
+		}
+	}
+	if a.CacheTTL < 0 {
+		return fmt.Errorf("invalid auth cache TTL: %s", a.CacheTTL)
+	}
+	if a.CacheSize <= 0 {
+		return fmt.Errorf("invalid auth cache size: %d", a.CacheSize)
+	}
+
+	issuers := make(map[string]bool, len(a.OIDC))
+	for _, issuer := range a.OIDC {
+		if err := issuer.Validate(); err != nil {
+			return err
+		}
+		if issuers[issuer.Issuer] {
+			return fmt.Errorf("OIDC issuer %s is configured more than once", issuer.Issuer)
+		}
+		issuers[issuer.Issuer] = true
+	}
+	return nil
+}
@@ -2,11 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
 )
 
 // Config holds all application configuration
@@ -15,13 +18,21 @@ type Config struct {
 	Database DatabaseConfig
 	Logging  LoggingConfig
 	Security SecurityConfig
+	Auth     AuthConfig
 	Features FeatureFlags
+	Digest   DigestConfig
+	Jira     JiraConfig
+	Events   EventsConfig
+	Metrics  MetricsConfig
+	GraphQL  GraphQLConfig
 }
 
 // ServerConfig holds all server-related configuration
 type ServerConfig struct {
-	Host            string
-	Port            string
+	Host string
+	Port string
+	// The gRPC API is served on its own port, next to the REST API
+	GRPCPort        string
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
@@ -40,6 +51,8 @@ type SecurityConfig struct {
 	EnableCORS     bool
 	AllowedOrigins []string
 	RateLimitRPS   int
+	// Addresses or CIDR ranges of the proxies whose X-Forwarded-User header is trusted, none by default
+	TrustedProxies []string
 }
 
 // FeatureFlags holds feature flag configuration
@@ -54,6 +67,7 @@ func LoadConfig() (*Config, error) {
 		Server: ServerConfig{
 			Host:            GetEnvOrDefault("HOST", "0.0.0.0"),
 			Port:            getEnvOrDefault("PORT", "3000"),
+			GRPCPort:        GetEnvOrDefault("GRPC_PORT", "9090"),
 			ReadTimeout:     GetEnvDurationOrDefault("READ_TIMEOUT", 30*time.Second),
 			WriteTimeout:    GetEnvDurationOrDefault("WRITE_TIMEOUT", 39*time.Second),
 			IdleTimeout:     GetEnvDurationOrDefault("IDLE_TIMEOUT", 60*time.Second),
@@ -76,11 +90,84 @@ func LoadConfig() (*Config, error) {
 			EnableCORS:     GetEnvBoolOrDefault("ENABLE_CORS", true),
 			AllowedOrigins: GetEnvSliceOrDefault("ALLOWED_ORIGINS", []string{"*"}),
 			RateLimitRPS:   GetEnvIntOrDefault("RATE_LIMIT_RPS", 100),
+			TrustedProxies: GetEnvSliceOrDefault("TRUSTED_PROXIES", nil),
+		},
+		Auth: AuthConfig{
+			Read: ResourceAccess{
+				Group:    GetEnvOrDefault("AUTH_READ_GROUP", ""),
+				Resource: GetEnvOrDefault("AUTH_READ_RESOURCE", "pods"),
+				Verb:     GetEnvOrDefault("AUTH_READ_VERB", "get"),
+			},
+			Write: ResourceAccess{
+				Group:    GetEnvOrDefault("AUTH_WRITE_GROUP", "tekton.dev"),
+				Resource: GetEnvOrDefault("AUTH_WRITE_RESOURCE", "pipelineruns"),
+				Verb:     GetEnvOrDefault("AUTH_WRITE_VERB", "create"),
+			},
+			CacheTTL:  GetEnvDurationOrDefault("AUTH_CACHE_TTL", 30*time.Second),
+			CacheSize: GetEnvIntOrDefault("AUTH_CACHE_SIZE", 10000),
 		},
 		Features: FeatureFlags{
 			EnableNamespaceChecking: GetEnvBoolOrDefault("FEATURE_NAMESPACE_CHECKING", true),
 			EnableWebhooks:          GetEnvBoolOrDefault("FEATURE_WEBHOOKS", true),
 		},
+		Digest: DigestConfig{
+			Enabled:   GetEnvBoolOrDefault("DIGEST_ENABLED", false),
+			Frequency: GetEnvOrDefault("DIGEST_FREQUENCY", "daily"),
+			Hour:      GetEnvIntOrDefault("DIGEST_HOUR", 8),
+			Weekday:   parseWeekdayOrDefault(GetEnvOrDefault("DIGEST_WEEKDAY", "monday"), time.Monday),
+			Limit:     GetEnvIntOrDefault("DIGEST_LIMIT", 10),
+			SMTP: SMTPConfig{
+				Host:     GetEnvOrDefault("SMTP_HOST", ""),
+				Port:     GetEnvOrDefault("SMTP_PORT", "25"),
+				Username: GetEnvOrDefault("SMTP_USERNAME", ""),
+				Password: GetEnvOrDefault("SMTP_PASSWORD", ""),
+				From:     GetEnvOrDefault("SMTP_FROM", ""),
+			},
+		},
+		Jira: JiraConfig{
+			Enabled:            GetEnvBoolOrDefault("JIRA_ENABLED", false),
+			BaseURL:            GetEnvOrDefault("JIRA_BASE_URL", ""),
+			Username:           GetEnvOrDefault("JIRA_USERNAME", ""),
+			APIToken:           GetEnvOrDefault("JIRA_API_TOKEN", ""),
+			ProjectKey:         GetEnvOrDefault("JIRA_PROJECT_KEY", ""),
+			IssueType:          GetEnvOrDefault("JIRA_ISSUE_TYPE", "Bug"),
+			AutoExportSeverity: models.Severity(GetEnvOrDefault("JIRA_AUTO_EXPORT_SEVERITY", "")),
+			SyncInterval:       GetEnvDurationOrDefault("JIRA_SYNC_INTERVAL", 5*time.Minute),
+			WebhookSecret:      GetEnvOrDefault("JIRA_WEBHOOK_SECRET", ""),
+		},
+		Events: EventsConfig{
+			Retention: GetEnvDurationOrDefault("EVENTS_RETENTION", 24*time.Hour),
+			CloudEvents: CloudEventsConfig{
+				Enabled:      GetEnvBoolOrDefault("CLOUDEVENTS_ENABLED", false),
+				SinkURL:      GetEnvOrDefault("CLOUDEVENTS_SINK_URL", ""),
+				Source:       GetEnvOrDefault("CLOUDEVENTS_SOURCE", "/kite"),
+				PollInterval: GetEnvDurationOrDefault("CLOUDEVENTS_POLL_INTERVAL", 2*time.Second),
+				BatchSize:    GetEnvIntOrDefault("CLOUDEVENTS_BATCH_SIZE", 100),
+				MaxAttempts:  GetEnvIntOrDefault("CLOUDEVENTS_MAX_ATTEMPTS", 10),
+			},
+		},
+		Metrics: MetricsConfig{
+			CacheTTL:      GetEnvDurationOrDefault("METRICS_CACHE_TTL", time.Minute),
+			CacheSize:     GetEnvIntOrDefault("METRICS_CACHE_SIZE", 1000),
+			DefaultWindow: GetEnvDurationOrDefault("METRICS_DEFAULT_WINDOW", 30*24*time.Hour),
+		},
+		GraphQL: GraphQLConfig{
+			ComplexityLimit: GetEnvIntOrDefault("GRAPHQL_COMPLEXITY_LIMIT", 1000),
+		},
+	}
+
+	recipients, err := ParseDigestRecipients(GetEnvOrDefault("DIGEST_RECIPIENTS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	cfg.Digest.Recipients = recipients
+
+	if path := GetEnvOrDefault("OIDC_CONFIG_FILE", ""); path != "" {
+		issuers, err := LoadOIDCConfig(path)
+		if err != nil {
+			return nil, fmt.Errorf("configuration validation failed: %w", err)
+		}
+		cfg.Auth.OIDC = issuers
 	}
 
 	// Validate configuration
@@ -104,6 +191,14 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid server port: %s", c.Server.Port)
 	}
 
+	grpcPortNum, err := strconv.Atoi(c.Server.GRPCPort)
+	if err != nil || grpcPortNum < 1 || grpcPortNum > 65535 {
+		return fmt.Errorf("invalid gRPC port: %s", c.Server.GRPCPort)
+	}
+	if grpcPortNum == portNum {
+		return fmt.Errorf("gRPC port must be different from the server port %s", c.Server.Port)
+	}
+
 	// Validate project environment
 	validEnvs := []string{"development", "staging", "production", "test"}
 	if !slices.Contains(validEnvs, c.Server.Environment) {
@@ -135,6 +230,47 @@ func (c *Config) Validate() error {
 			c.Logging.Format, strings.Join(validLogFormats, ", "))
 	}
 
+	// Validate security configuration
+	for _, proxy := range c.Security.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			return fmt.Errorf("invalid trusted proxy: %q (must be an IP address or CIDR range)", proxy)
+		}
+	}
+
+	// Validate auth configuration
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
+
+	// Validate digest configuration
+	if err := c.Digest.Validate(); err != nil {
+		return err
+	}
+
+	// Validate jira configuration
+	if err := c.Jira.Validate(); err != nil {
+		return err
+	}
+
+	// Validate events configuration
+	if err := c.Events.Validate(); err != nil {
+		return err
+	}
+
+	// Validate metrics configuration
+	if err := c.Metrics.Validate(); err != nil {
+		return err
+	}
+
+	// Validate GraphQL configuration
+	if err := c.GraphQL.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -154,6 +290,11 @@ func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%s", c.Server.Host, c.Server.Port)
 }
 
+// GetGRPCAddress returns the full address of the gRPC server
+func (c *Config) GetGRPCAddress() string {
+	return fmt.Sprintf("%s:%s", c.Server.Host, c.Server.GRPCPort)
+}
+
 // Helper function to get an environment variable. Defaults to the value passed
 func GetEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -197,7 +338,7 @@ func GetEnvBoolOrDefault(key string, defaultValue bool) bool {
 // Defaults to the value passed.
 func GetEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
-		if timeValue, err := time.ParseDuration(value); err != nil {
+		if timeValue, err := time.ParseDuration(value); err == nil {
 			return timeValue
 		}
 	}
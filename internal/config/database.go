@@ -33,13 +33,18 @@ func GetDatabaseConfig() *DatabaseConfig {
 	}
 }
 
+// Returns the connection string for the database.
+func (c *DatabaseConfig) ConnectionString() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
+		c.Host, c.User, c.Password, c.Name, c.Port, c.SSLMode)
+}
+
 // Initializes the database.
 func InitDatabase() (*gorm.DB, error) {
 	config := GetDatabaseConfig()
 
 	// Build the connection string
-	connectionString := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
-		config.Host, config.User, config.Password, config.Name, config.Port, config.SSLMode)
+	connectionString := config.ConnectionString()
 
 	// Configure logger based on environment
 	var gormLogger logger.Interface
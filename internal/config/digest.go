@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DigestConfig holds configuration for the email digest reports
+type DigestConfig struct {
+	Enabled bool
+	// Frequency is either "daily" or "weekly"
+	Frequency string
+	// Hour of the day (UTC) the digest is sent at
+	Hour int
+	// Weekday the digest is sent on when running weekly
+	Weekday time.Weekday
+	// Maximum number of issues listed per section
+	Limit int
+	// Recipients maps a namespace to the email addresses that receive its digest
+	Recipients map[string][]string
+	SMTP       SMTPConfig
+}
+
+// SMTPConfig holds the SMTP server used to send emails
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Address returns the SMTP server address
+func (s SMTPConfig) Address() string {
+	return fmt.Sprintf("%s:%s", s.Host, s.Port)
+}
+
+// Period returns the length of time a single digest covers
+func (d DigestConfig) Period() time.Duration {
+	if d.Frequency == "weekly" {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Validate validates the digest configuration
+func (d DigestConfig) Validate() error {
+	if !d.Enabled {
+		return nil
+	}
+
+	validFrequencies := []string{"daily", "weekly"}
+	if !slices.Contains(validFrequencies, d.Frequency) {
+		return fmt.Errorf("invalid digest frequency: %s (must be one of: %s)",
+			d.Frequency, strings.Join(validFrequencies, ", "))
+	}
+	if d.Hour < 0 || d.Hour > 23 {
+		return fmt.Errorf("invalid digest hour: %d", d.Hour)
+	}
+	if d.SMTP.Host == "" {
+		return fmt.Errorf("SMTP host is required when digests are enabled")
+	}
+	if d.SMTP.From == "" {
+		return fmt.Errorf("SMTP from address is required when digests are enabled")
+	}
+	if len(d.Recipients) == 0 {
+		return fmt.Errorf("at least one digest recipient is required when digests are enabled")
+	}
+	return nil
+}
+
+// Helper function to parse digest recipients.
+//
+// The expected format is a comma separated list of namespace=addresses pairs,
+// where addresses are separated by semicolons:
+//
+//	team-alpha=lead@example.com;manager@example.com,team-beta=lead@example.com
+func ParseDigestRecipients(value string) (map[string][]string, error) {
+	recipients := map[string][]string{}
+	if strings.TrimSpace(value) == "" {
+		return recipients, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		namespace, addresses, found := strings.Cut(entry, "=")
+		namespace = strings.TrimSpace(namespace)
+		if !found || namespace == "" {
+			return nil, fmt.Errorf("invalid digest recipients entry: %q", entry)
+		}
+		for _, address := range strings.Split(addresses, ";") {
+			if address = strings.TrimSpace(address); address != "" {
+				recipients[namespace] = append(recipients[namespace], address)
+			}
+		}
+	}
+	return recipients, nil
+}
+
+// Helper function to parse a weekday name, defaults to the value passed.
+func parseWeekdayOrDefault(value string, defaultValue time.Weekday) time.Weekday {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), value) {
+			return day
+		}
+	}
+	return defaultValue
+}
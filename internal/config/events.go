@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventsConfig holds configuration for issue change events
+type EventsConfig struct {
+	// How long events are kept in the outbox
+	Retention   time.Duration
+	CloudEvents CloudEventsConfig
+}
+
+// CloudEventsConfig holds configuration for publishing issue changes as CloudEvents
+type CloudEventsConfig struct {
+	Enabled bool
+	// Sink events are posted to, e.g. a Knative broker URL
+	SinkURL string
+	// Value of the ce-source attribute
+	Source string
+	// How often the outbox is checked for new events
+	PollInterval time.Duration
+	// Maximum number of events sent per poll
+	BatchSize int
+	// Failed attempts after which an event is parked, so it stops holding up the events after it
+	MaxAttempts int
+}
+
+// Validate validates the events configuration
+func (e EventsConfig) Validate() error {
+	if e.Retention <= 0 {
+		return fmt.Errorf("invalid event retention: %s", e.Retention)
+	}
+	if !e.CloudEvents.Enabled {
+		return nil
+	}
+	if e.CloudEvents.SinkURL == "" {
+		return fmt.Errorf("cloudevents sink URL is required when cloudevents are enabled")
+	}
+	if e.CloudEvents.PollInterval <= 0 {
+		return fmt.Errorf("invalid cloudevents poll interval: %s", e.CloudEvents.PollInterval)
+	}
+	if e.CloudEvents.BatchSize <= 0 {
+		return fmt.Errorf("invalid cloudevents batch size: %d", e.CloudEvents.BatchSize)
+	}
+	if e.CloudEvents.MaxAttempts <= 0 {
+		return fmt.Errorf("invalid cloudevents max attempts: %d", e.CloudEvents.MaxAttempts)
+	}
+	return nil
+}
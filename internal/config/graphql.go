@@ -0,0 +1,17 @@
+package config
+
+import "fmt"
+
+// GraphQLConfig holds configuration for the GraphQL endpoint
+type GraphQLConfig struct {
+	// Highest complexity of a query, estimated from the fields and page sizes it asks for
+	ComplexityLimit int
+}
+
+// Validate validates the GraphQL configuration
+func (g GraphQLConfig) Validate() error {
+	if g.ComplexityLimit <= 0 {
+		return fmt.Errorf("invalid GraphQL complexity limit: %d", g.ComplexityLimit)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// JiraConfig holds configuration for the Jira integration
+type JiraConfig struct {
+	Enabled    bool
+	BaseURL    string
+	Username   string // Leave empty to authenticate with APIToken as a bearer token
+	APIToken   string
+	ProjectKey string
+	IssueType  string
+	// Active issues at or above this severity are exported automatically, leave empty to disable
+	AutoExportSeverity models.Severity
+	// How often Kite and Jira issue states are synced
+	SyncInterval time.Duration
+	// Shared secret Jira webhooks must pass in the "secret" query param
+	WebhookSecret string
+}
+
+// Validate validates the Jira configuration
+func (j JiraConfig) Validate() error {
+	if !j.Enabled {
+		return nil
+	}
+	if j.BaseURL == "" {
+		return fmt.Errorf("jira base URL is required when jira is enabled")
+	}
+	if j.ProjectKey == "" {
+		return fmt.Errorf("jira project key is required when jira is enabled")
+	}
+	if j.AutoExportSeverity != "" && j.AutoExportSeverity.Rank() < 0 {
+		return fmt.Errorf("invalid jira auto export severity: %s", j.AutoExportSeverity)
+	}
+	if j.SyncInterval <= 0 {
+		return fmt.Errorf("invalid jira sync interval: %s", j.SyncInterval)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// MetricsConfig holds configuration for reliability metrics
+type MetricsConfig struct {
+	// How long computed metrics are reused for identical requests
+	CacheTTL time.Duration
+	// Maximum number of results kept in the cache
+	CacheSize int
+	// Window metrics cover when the request doesn't set one
+	DefaultWindow time.Duration
+}
+
+// Validate validates the metrics configuration
+func (m MetricsConfig) Validate() error {
+	if m.CacheTTL < 0 {
+		return fmt.Errorf("invalid metrics cache TTL: %s", m.CacheTTL)
+	}
+	if m.CacheSize <= 0 {
+		return fmt.Errorf("invalid metrics cache size: %d", m.CacheSize)
+	}
+	if m.DefaultWindow <= 0 {
+		return fmt.Errorf("invalid metrics default window: %s", m.DefaultWindow)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+)
+
+// OIDCIssuerConfig is an OpenID Connect provider whose ID tokens are accepted, such as the SSO of the web UI
+type OIDCIssuerConfig struct {
+	// URL of the provider, which tokens have in their iss claim
+	Issuer string `json:"issuer"`
+	// URL of the provider's JSON Web Key Set, found with OIDC discovery when not set
+	JWKSURL string `json:"jwksURL,omitempty"`
+	// Client ID tokens have to be issued to, in their aud claim
+	Audience string `json:"audience"`
+	// Claim holding the username, sub by default
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// Prepended to usernames, so they can't be mistaken for Kubernetes users
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+	// Claim holding the list of groups, groups by default
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// Namespaces the members of each group can access
+	Groups map[string]GroupAccess `json:"groups"`
+}
+
+// GroupAccess is the access to namespaces members of a group get
+type GroupAccess struct {
+	Namespaces []string `json:"namespaces"`
+	// read or write, write access includes read access
+	Access string `json:"access"`
+}
+
+// oidcConfigFile is the format of OIDC_CONFIG_FILE
+type oidcConfigFile struct {
+	Issuers []OIDCIssuerConfig `json:"issuers"`
+}
+
+// LoadOIDCConfig reads the issuers in an OIDC config file, in YAML or JSON
+func LoadOIDCConfig(path string) ([]OIDCIssuerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OIDC config file: %w", err)
+	}
+
+	var file oidcConfigFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse OIDC config file %s: %w", path, err)
+	}
+
+	for i := range file.Issuers {
+		issuer := &file.Issuers[i]
+		if issuer.UsernameClaim == "" {
+			issuer.UsernameClaim = "sub"
+		}
+		if issuer.GroupsClaim == "" {
+			issuer.GroupsClaim = "groups"
+		}
+	}
+	return file.Issuers, nil
+}
+
+// Validate validates the configuration of an OIDC issuer
+func (o OIDCIssuerConfig) Validate() error {
+	if issuer, err := url.Parse(o.Issuer); err != nil || issuer.Scheme != "https" && issuer.Scheme != "http" || issuer.Host == "" {
+		return fmt.Errorf("invalid OIDC issuer URL: %q", o.Issuer)
+	}
+	if o.JWKSURL != "" {
+		if _, err := url.Parse(o.JWKSURL); err != nil {
+			return fmt.Errorf("invalid JWKS URL for OIDC issuer %s: %w", o.Issuer, err)
+		}
+	}
+	if o.Audience == "" {
+		return fmt.Errorf("an audience is required for OIDC issuer %s", o.Issuer)
+	}
+	for group, access := range o.Groups {
+		if !slices.Contains([]string{"read", "write"}, access.Access) {
+			return fmt.Errorf("invalid access %q for group %s of OIDC issuer %s (must be read or write)", access.Access, group, o.Issuer)
+		}
+	}
+	return nil
+}
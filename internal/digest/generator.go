@@ -0,0 +1,94 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/repository"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// Message is a rendered digest, ready to be sent
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string // Plain-text fallback for clients that can't render HTML
+}
+
+// Generator renders digest summaries into emails
+type Generator struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+type templateData struct {
+	Subject string
+	Summary *repository.DigestSummary
+}
+
+var templateFuncs = map[string]any{
+	"formatTime": func(t time.Time) string {
+		return t.UTC().Format("Jan 2, 2006 15:04 MST")
+	},
+	"openFor": func(t time.Time) string {
+		return formatDuration(time.Since(t))
+	},
+}
+
+// NewGenerator parses the embedded digest templates
+func NewGenerator() (*Generator, error) {
+	html, err := htmltemplate.New("digest.html").Funcs(templateFuncs).ParseFS(templateFS, "templates/digest.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML digest template: %w", err)
+	}
+	text, err := texttemplate.New("digest.txt").Funcs(templateFuncs).ParseFS(templateFS, "templates/digest.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text digest template: %w", err)
+	}
+	return &Generator{html: html, text: text}, nil
+}
+
+// Render renders the HTML and plain-text versions of a digest
+func (g *Generator) Render(summary *repository.DigestSummary) (*Message, error) {
+	data := templateData{
+		Subject: fmt.Sprintf("[Kite] Issue digest for %s: %d new, %d resolved",
+			summary.Namespace, summary.NewCount, summary.ResolvedCount),
+		Summary: summary,
+	}
+
+	var html bytes.Buffer
+	if err := g.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render HTML digest: %w", err)
+	}
+
+	var text bytes.Buffer
+	if err := g.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render text digest: %w", err)
+	}
+
+	return &Message{
+		Subject: data.Subject,
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+// Helper function to format how long an issue has been open in days or hours
+func formatDuration(d time.Duration) string {
+	if days := int(d.Hours() / 24); days > 0 {
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	if hours := int(d.Hours()); hours != 1 {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return "1 hour"
+}
@@ -0,0 +1,91 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+func testSummary() *repository.DigestSummary {
+	now := time.Now()
+	return &repository.DigestSummary{
+		Namespace:     "team-a",
+		Since:         now.Add(-24 * time.Hour),
+		Until:         now,
+		NewCount:      12,
+		ResolvedCount: 3,
+		ActiveCount:   9,
+		NewIssues: []models.Issue{{
+			Title:      "Build <failed> & retried",
+			Severity:   models.SeverityMajor,
+			DetectedAt: now.Add(-2 * time.Hour),
+			Scope:      models.IssueScope{ResourceType: "component", ResourceName: "frontend"},
+		}},
+		LongestOpen: []models.Issue{{
+			Title:      "Flaky test",
+			Severity:   models.SeverityMinor,
+			DetectedAt: now.Add(-73 * time.Hour),
+		}},
+		TopFailingScopes: []repository.ScopeFailureCount{{ResourceType: "component", ResourceName: "frontend", Count: 7}},
+	}
+}
+
+func TestGeneratorRender(t *testing.T) {
+	generator, err := NewGenerator()
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	msg, err := generator.Render(testSummary())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if want := "[Kite] Issue digest for team-a: 12 new, 3 resolved"; msg.Subject != want {
+		t.Errorf("subject = %q, want %q", msg.Subject, want)
+	}
+	for _, want := range []string{
+		"12 new, 3 resolved, 9 still active",
+		"[major] Build <failed> & retried (component/frontend)",
+		"No resolved issues.",
+		"[minor] Flaky test, open for 3 days",
+		"component/frontend: 7",
+	} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("text digest doesn't contain %q:\n%s", want, msg.Text)
+		}
+	}
+	for _, want := range []string{
+		"Build &lt;failed&gt; &amp; retried",
+		"<p>No resolved issues.</p>",
+		"open for 3 days",
+	} {
+		if !strings.Contains(msg.HTML, want) {
+			t.Errorf("HTML digest doesn't contain %q:\n%s", want, msg.HTML)
+		}
+	}
+	if strings.Contains(msg.HTML, "<failed>") {
+		t.Error("HTML digest doesn't escape issue titles")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Minute, "0 hours"},
+		{time.Hour, "1 hour"},
+		{5 * time.Hour, "5 hours"},
+		{25 * time.Hour, "1 day"},
+		{72 * time.Hour, "3 days"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
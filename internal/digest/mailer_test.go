@@ -0,0 +1,178 @@
+package digest
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/konflux-ci/kite/internal/config"
+)
+
+// smtpSession is what a client sent to the fake SMTP server
+type smtpSession struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// Helper function to serve a single SMTP session on a local listener, returning the address and the session once it's done
+func fakeSMTPServer(t *testing.T, requireAuth bool) (string, <-chan smtpSession) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var session smtpSession
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				if requireAuth {
+					text.PrintfLine("250-localhost")
+					text.PrintfLine("250 AUTH PLAIN")
+				} else {
+					text.PrintfLine("250 localhost")
+				}
+			case "AUTH":
+				session.auth = arg
+				text.PrintfLine("235 Authenticated")
+			case "MAIL":
+				session.from = arg
+				text.PrintfLine("250 OK")
+			case "RCPT":
+				session.to = append(session.to, arg)
+				text.PrintfLine("250 OK")
+			case "DATA":
+				text.PrintfLine("354 Go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				session.data = string(data)
+				text.PrintfLine("250 OK")
+			case "QUIT":
+				text.PrintfLine("221 Bye")
+				sessions <- session
+				return
+			default:
+				text.PrintfLine("502 Not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), sessions
+}
+
+func smtpConfig(t *testing.T, addr string) config.SMTPConfig {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config.SMTPConfig{Host: host, Port: port, From: "kite@example.com"}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	addr, sessions := fakeSMTPServer(t, false)
+	mailer := NewSMTPMailer(smtpConfig(t, addr))
+
+	msg := &Message{Subject: "[Kite] Issue digest", HTML: "<p>Hello</p>", Text: "Hello"}
+	if err := mailer.Send([]string{"a@example.com", "b@example.com"}, msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	session := <-sessions
+
+	if session.from != "FROM:<kite@example.com>" {
+		t.Errorf("MAIL %s, want the configured sender", session.from)
+	}
+	if len(session.to) != 2 || session.to[0] != "TO:<a@example.com>" || session.to[1] != "TO:<b@example.com>" {
+		t.Errorf("RCPT %v, want both recipients", session.to)
+	}
+	if session.auth != "" {
+		t.Errorf("authenticated with %q without credentials configured", session.auth)
+	}
+
+	email, err := mail.ReadMessage(strings.NewReader(session.data))
+	if err != nil {
+		t.Fatalf("failed to parse email: %v", err)
+	}
+	if subject := email.Header.Get("Subject"); subject != msg.Subject {
+		t.Errorf("subject = %q, want %q", subject, msg.Subject)
+	}
+	mediaType, params, err := mime.ParseMediaType(email.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q, want multipart/alternative", email.Header.Get("Content-Type"))
+	}
+
+	// Clients show the last part they support, so the plain-text part comes first
+	var parts []string
+	reader := multipart.NewReader(email.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read email part: %v", err)
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, part.Header.Get("Content-Type")+": "+string(body))
+	}
+	want := []string{"text/plain; charset=utf-8: Hello", "text/html; charset=utf-8: <p>Hello</p>"}
+	if len(parts) != 2 || parts[0] != want[0] || parts[1] != want[1] {
+		t.Errorf("parts = %q, want %q", parts, want)
+	}
+}
+
+func TestSMTPMailerSendAuthenticates(t *testing.T) {
+	addr, sessions := fakeSMTPServer(t, true)
+	cfg := smtpConfig(t, addr)
+	cfg.Username, cfg.Password = "kite", "secret"
+
+	if err := NewSMTPMailer(cfg).Send([]string{"a@example.com"}, &Message{Subject: "Digest"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	session := <-sessions
+
+	mechanism, credentials, _ := strings.Cut(session.auth, " ")
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if mechanism != "PLAIN" || err != nil || string(decoded) != "\x00kite\x00secret" {
+		t.Errorf("AUTH %s, want PLAIN with the configured credentials", session.auth)
+	}
+}
+
+func TestSMTPMailerSendFails(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	if err := NewSMTPMailer(smtpConfig(t, addr)).Send([]string{"a@example.com"}, &Message{}); err == nil {
+		t.Error("Send() succeeded without an SMTP server")
+	}
+}
@@ -0,0 +1,106 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/sirupsen/logrus"
+)
+
+// Scheduler periodically sends issue digests for each configured namespace
+type Scheduler struct {
+	cfg          config.DigestConfig
+	issueService *services.IssueService
+	generator    *Generator
+	mailer       Mailer
+	logger       *logrus.Logger
+}
+
+// NewScheduler returns a new digest scheduler
+func NewScheduler(cfg config.DigestConfig, issueService *services.IssueService, mailer Mailer, logger *logrus.Logger) (*Scheduler, error) {
+	generator, err := NewGenerator()
+	if err != nil {
+		return nil, err
+	}
+	return &Scheduler{
+		cfg:          cfg,
+		issueService: issueService,
+		generator:    generator,
+		mailer:       mailer,
+		logger:       logger,
+	}, nil
+}
+
+// Run blocks, sending digests on schedule until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		next := s.NextRun(time.Now())
+		s.logger.WithFields(logrus.Fields{
+			"frequency": s.cfg.Frequency,
+			"next_run":  next,
+		}).Info("Scheduled next issue digest")
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.SendAll(ctx)
+		}
+	}
+}
+
+// NextRun returns the next time a digest should be sent after the time passed
+func (s *Scheduler) NextRun(after time.Time) time.Time {
+	after = after.UTC()
+	next := time.Date(after.Year(), after.Month(), after.Day(), s.cfg.Hour, 0, 0, 0, time.UTC)
+
+	if s.cfg.Frequency == "weekly" {
+		// Move forward to the configured weekday
+		days := (int(s.cfg.Weekday) - int(next.Weekday()) + 7) % 7
+		next = next.AddDate(0, 0, days)
+		if !next.After(after) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	}
+
+	if !next.After(after) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// SendAll sends a digest to the recipients of every configured namespace
+func (s *Scheduler) SendAll(ctx context.Context) {
+	since := time.Now().Add(-s.cfg.Period())
+	for namespace, recipients := range s.cfg.Recipients {
+		if err := s.Send(ctx, namespace, recipients, since); err != nil {
+			s.logger.WithError(err).WithField("namespace", namespace).Error("Failed to send issue digest")
+			continue
+		}
+		s.logger.WithFields(logrus.Fields{
+			"namespace":  namespace,
+			"recipients": len(recipients),
+		}).Info("Sent issue digest")
+	}
+}
+
+// Send generates and sends the digest of a single namespace
+func (s *Scheduler) Send(ctx context.Context, namespace string, recipients []string, since time.Time) error {
+	summary, err := s.issueService.GetDigestSummary(ctx, namespace, since, s.cfg.Limit)
+	if err != nil {
+		return fmt.Errorf("failed to get digest summary: %w", err)
+	}
+
+	msg, err := s.generator.Render(summary)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(recipients, msg)
+}
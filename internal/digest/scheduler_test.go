@@ -0,0 +1,145 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSchedulerNextRun(t *testing.T) {
+	// A Wednesday
+	wednesday := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		cfg   config.DigestConfig
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "daily before the hour",
+			cfg:   config.DigestConfig{Frequency: "daily", Hour: 9},
+			after: wednesday.Add(8 * time.Hour),
+			want:  wednesday.Add(9 * time.Hour),
+		},
+		{
+			name:  "daily at the hour",
+			cfg:   config.DigestConfig{Frequency: "daily", Hour: 9},
+			after: wednesday.Add(9 * time.Hour),
+			want:  wednesday.AddDate(0, 0, 1).Add(9 * time.Hour),
+		},
+		{
+			name:  "daily after the hour",
+			cfg:   config.DigestConfig{Frequency: "daily", Hour: 9},
+			after: wednesday.Add(23 * time.Hour),
+			want:  wednesday.AddDate(0, 0, 1).Add(9 * time.Hour),
+		},
+		{
+			name:  "daily in another time zone",
+			cfg:   config.DigestConfig{Frequency: "daily", Hour: 9},
+			after: wednesday.Add(8 * time.Hour).In(time.FixedZone("UTC+10", 10*60*60)),
+			want:  wednesday.Add(9 * time.Hour),
+		},
+		{
+			name:  "weekly later in the week",
+			cfg:   config.DigestConfig{Frequency: "weekly", Hour: 9, Weekday: time.Friday},
+			after: wednesday.Add(12 * time.Hour),
+			want:  wednesday.AddDate(0, 0, 2).Add(9 * time.Hour),
+		},
+		{
+			name:  "weekly earlier in the week",
+			cfg:   config.DigestConfig{Frequency: "weekly", Hour: 9, Weekday: time.Monday},
+			after: wednesday.Add(12 * time.Hour),
+			want:  wednesday.AddDate(0, 0, 5).Add(9 * time.Hour),
+		},
+		{
+			name:  "weekly on the day after the hour",
+			cfg:   config.DigestConfig{Frequency: "weekly", Hour: 9, Weekday: time.Wednesday},
+			after: wednesday.Add(10 * time.Hour),
+			want:  wednesday.AddDate(0, 0, 7).Add(9 * time.Hour),
+		},
+		{
+			name:  "weekly on the day before the hour",
+			cfg:   config.DigestConfig{Frequency: "weekly", Hour: 9, Weekday: time.Wednesday},
+			after: wednesday.Add(8 * time.Hour),
+			want:  wednesday.Add(9 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := &Scheduler{cfg: tt.cfg}
+			if got := scheduler.NextRun(tt.after); !got.Equal(tt.want) {
+				t.Errorf("NextRun(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+// digestRepository returns the same summary for every namespace, the rest of the repository isn't used
+type digestRepository struct {
+	repository.IssueRepository
+	namespaces []string
+}
+
+func (r *digestRepository) GetDigestSummary(_ context.Context, namespace string, since time.Time, _ int) (*repository.DigestSummary, error) {
+	r.namespaces = append(r.namespaces, namespace)
+	if namespace == "broken" {
+		return nil, errors.New("connection refused")
+	}
+	summary := testSummary()
+	summary.Namespace = namespace
+	summary.Since = since
+	return summary, nil
+}
+
+// recordingMailer keeps the messages it's asked to send by recipient
+type recordingMailer struct {
+	sent map[string]*Message
+}
+
+func (m *recordingMailer) Send(to []string, msg *Message) error {
+	for _, recipient := range to {
+		m.sent[recipient] = msg
+	}
+	return nil
+}
+
+func TestSchedulerSendAll(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repo := &digestRepository{}
+	mailer := &recordingMailer{sent: map[string]*Message{}}
+	cfg := config.DigestConfig{
+		Frequency: "daily",
+		Recipients: map[string][]string{
+			"team-a": {"a@example.com"},
+			"team-b": {"b@example.com", "lead@example.com"},
+			"broken": {"broken@example.com"},
+		},
+	}
+	scheduler, err := NewScheduler(cfg, services.NewIssueService(repo, logger), mailer, logger)
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+
+	scheduler.SendAll(context.Background())
+
+	// A namespace failing doesn't stop the others being sent
+	if len(repo.namespaces) != 3 || len(mailer.sent) != 3 {
+		t.Fatalf("summarized %v and sent to %d recipients, want 3 namespaces and 3 recipients", repo.namespaces, len(mailer.sent))
+	}
+	if msg := mailer.sent["lead@example.com"]; msg == nil || msg.Subject != "[Kite] Issue digest for team-b: 12 new, 3 resolved" {
+		t.Errorf("sent %+v to lead@example.com, want the digest of team-b", msg)
+	}
+	if _, ok := mailer.sent["broken@example.com"]; ok {
+		t.Error("sent a digest for a namespace that couldn't be summarized")
+	}
+}
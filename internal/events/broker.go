@@ -0,0 +1,197 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Events buffered per subscriber before it's considered too slow and dropped
+	subscriberBuffer = 64
+	// Maximum number of events loaded from the outbox at once
+	fetchBatchSize = 500
+	// How often the outbox is read again while notified events are held back by transactions that haven't finished
+	heldBackPollInterval = time.Second
+)
+
+// Subscription receives every new outbox event
+type Subscription struct {
+	// Events is closed when the subscriber falls too far behind,
+	// it should reconnect and resume from the last event it received.
+	Events chan models.OutboxEvent
+}
+
+// Broker fans outbox events out to subscribers in this replica.
+//
+// It listens for Postgres notifications sent when events are committed,
+// so subscribers receive changes made through any replica.
+// Events are published in the order of repository.EventPosition, so none is skipped when transactions
+// commit out of order, but an event waits while any transaction started before it is still running.
+// The listener only runs while there's at least one subscriber.
+type Broker struct {
+	dsn    string
+	repo   repository.OutboxRepository
+	logger *logrus.Logger
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	stop        context.CancelFunc
+}
+
+// NewBroker returns a new event broker listening on the database passed
+func NewBroker(dsn string, repo repository.OutboxRepository, logger *logrus.Logger) *Broker {
+	return &Broker{
+		dsn:         dsn,
+		repo:        repo,
+		logger:      logger,
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Subscribe registers a new subscriber, starting the listener if needed.
+//
+// Every event committed after Subscribe returns is delivered to the subscription,
+// earlier events can be loaded with Replay.
+func (b *Broker) Subscribe(ctx context.Context) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stop == nil {
+		// Pin the starting point before returning so nothing falls between Replay and the listener
+		position, err := b.repo.LatestPosition(ctx)
+		if err != nil {
+			return nil, err
+		}
+		listenCtx, cancel := context.WithCancel(context.Background())
+		b.stop = cancel
+		go b.listen(listenCtx, position)
+	}
+
+	sub := &Subscription{Events: make(chan models.OutboxEvent, subscriberBuffer)}
+	b.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe removes a subscriber, stopping the listener if it was the last one
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.Events)
+	}
+
+	if len(b.subscribers) == 0 && b.stop != nil {
+		b.stop()
+		b.stop = nil
+	}
+}
+
+// Position returns the position of the event with the ID passed, to replay the events after it.
+// Returns repository.ErrEventNotFound once the event has been pruned from the event log.
+func (b *Broker) Position(ctx context.Context, id int64) (repository.EventPosition, error) {
+	return b.repo.FindPosition(ctx, id)
+}
+
+// Replay returns events after the position passed, in the order they're published in
+func (b *Broker) Replay(ctx context.Context, after repository.EventPosition) ([]models.OutboxEvent, error) {
+	return b.repo.FindAfter(ctx, after, fetchBatchSize)
+}
+
+// Helper function to listen for notifications until the context is cancelled, reconnecting on failure
+func (b *Broker) listen(ctx context.Context, position repository.EventPosition) {
+	var err error
+	backoff := time.Second
+	for {
+		position, err = b.listenOnce(ctx, position)
+		if ctx.Err() != nil {
+			return
+		}
+		b.logger.WithError(err).WithField("retry_in", backoff).Warn("Event listener disconnected")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+// Helper function to run a single listener connection, returns the position of the last event published
+func (b *Broker) listenOnce(ctx context.Context, position repository.EventPosition) (repository.EventPosition, error) {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return position, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{repository.EventNotifyChannel}.Sanitize()); err != nil {
+		return position, err
+	}
+	b.logger.Debug("Listening for issue events")
+
+	for {
+		// Catch up from the outbox rather than trusting notification payloads,
+		// this also picks up anything committed while we were disconnected.
+		if position, err = b.publishAfter(ctx, position); err != nil {
+			return position, err
+		}
+		heldBack, err := b.repo.HeldBack(ctx, position)
+		if err != nil {
+			return position, err
+		}
+
+		// Nothing is notified when the transactions holding events back finish, unless they record events too
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if heldBack {
+			waitCtx, cancel = context.WithTimeout(ctx, heldBackPollInterval)
+		}
+		_, err = conn.WaitForNotification(waitCtx)
+		cancel()
+		if err != nil && (waitCtx.Err() == nil || ctx.Err() != nil) {
+			return position, err
+		}
+	}
+}
+
+// Helper function to publish every event after the position passed, returns the position of the last event published
+func (b *Broker) publishAfter(ctx context.Context, position repository.EventPosition) (repository.EventPosition, error) {
+	for {
+		events, err := b.repo.FindAfter(ctx, position, fetchBatchSize)
+		if err != nil {
+			return position, err
+		}
+		for _, event := range events {
+			b.publish(event)
+			position = repository.PositionOf(event)
+		}
+		if len(events) < fetchBatchSize {
+			return position, nil
+		}
+	}
+}
+
+// Helper function to send an event to all subscribers
+func (b *Broker) publish(event models.OutboxEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.Events <- event:
+		default:
+			// Don't let one slow client hold everyone else up
+			delete(b.subscribers, sub)
+			close(sub.Events)
+			b.logger.Warn("Dropped slow event subscriber")
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// CloudEventsSender posts outbox events to a sink as CloudEvents in binary content mode,
+// where the event attributes are sent as ce-* headers and the body is the event data.
+type CloudEventsSender struct {
+	sinkURL    string
+	source     string
+	httpClient *http.Client
+}
+
+// NewCloudEventsSender returns a sender for the sink URL passed
+func NewCloudEventsSender(sinkURL, source string) *CloudEventsSender {
+	return &CloudEventsSender{
+		sinkURL:    sinkURL,
+		source:     source,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send posts a single event to the sink
+func (s *CloudEventsSender) Send(ctx context.Context, event models.OutboxEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.sinkURL, bytes.NewReader(event.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create cloudevent request: %w", err)
+	}
+
+	// Outbox IDs are unique and stable across retries, so sinks can dedupe on ce-id
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", strconv.FormatInt(event.ID, 10))
+	req.Header.Set("ce-type", event.Type)
+	req.Header.Set("ce-source", strings.TrimSuffix(s.source, "/")+"/namespaces/"+event.Namespace)
+	req.Header.Set("ce-subject", event.IssueID)
+	req.Header.Set("ce-time", event.CreatedAt.UTC().Format(time.RFC3339Nano))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send cloudevent: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
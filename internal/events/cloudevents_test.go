@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+func TestCloudEventsSenderBinaryMode(t *testing.T) {
+	var header http.Header
+	var body []byte
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	event := models.OutboxEvent{
+		ID:        42,
+		Type:      models.EventTypeIssueResolved,
+		IssueID:   "11111111-1111-1111-1111-111111111111",
+		Namespace: "team-a",
+		Payload:   json.RawMessage(`{"id":"11111111-1111-1111-1111-111111111111","state":"RESOLVED"}`),
+		CreatedAt: time.Date(2024, 5, 10, 14, 30, 0, 123000000, time.FixedZone("CEST", 2*60*60)),
+	}
+	if err := NewCloudEventsSender(sink.URL, "https://kite.example.com/").Send(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"ce-specversion": "1.0",
+		"ce-id":          "42",
+		"ce-type":        models.EventTypeIssueResolved,
+		"ce-source":      "https://kite.example.com/namespaces/team-a",
+		"ce-subject":     event.IssueID,
+		"ce-time":        "2024-05-10T12:30:00.123Z",
+		"Content-Type":   "application/json",
+	} {
+		if got := header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	// The body is the event data itself, not a structured mode envelope
+	if string(body) != string(event.Payload) {
+		t.Errorf("body = %s, want the payload", body)
+	}
+}
+
+func TestCloudEventsSenderRejectedEvent(t *testing.T) {
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad event", http.StatusBadRequest)
+	}))
+	defer sink.Close()
+
+	err := NewCloudEventsSender(sink.URL, "https://kite.example.com").Send(context.Background(), models.OutboxEvent{ID: 1, Payload: json.RawMessage(`{}`)})
+	if err == nil || err.Error() != "sink responded with status 400" {
+		t.Errorf("error = %v, want the sink's status", err)
+	}
+}
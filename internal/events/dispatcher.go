@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Sender delivers a single outbox event
+type Sender interface {
+	Send(ctx context.Context, event models.OutboxEvent) error
+}
+
+// Dispatcher delivers events from the outbox
+type Dispatcher struct {
+	repo      repository.OutboxRepository
+	sender    Sender
+	interval  time.Duration
+	batchSize int
+	// Failed attempts after which an event is parked
+	maxAttempts int
+	logger      *logrus.Logger
+}
+
+// NewDispatcher returns a new outbox dispatcher
+func NewDispatcher(repo repository.OutboxRepository, sender Sender, interval time.Duration, batchSize, maxAttempts int, logger *logrus.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:        repo,
+		sender:      sender,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+	}
+}
+
+// Run dispatches pending events every interval until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while there's a backlog of full batches, parked events count towards a full batch
+		for {
+			count, err := d.repo.DispatchPending(ctx, d.batchSize, d.maxAttempts, func(event models.OutboxEvent) error {
+				return d.sender.Send(ctx, event)
+			})
+			if err != nil || count < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Pruner removes old events from the outbox
+type Pruner struct {
+	repo      repository.OutboxRepository
+	retention time.Duration
+	// Whether undispatched events are kept past the retention period
+	keepPending bool
+	logger      *logrus.Logger
+}
+
+// NewPruner returns a new outbox pruner
+func NewPruner(repo repository.OutboxRepository, retention time.Duration, keepPending bool, logger *logrus.Logger) *Pruner {
+	return &Pruner{
+		repo:        repo,
+		retention:   retention,
+		keepPending: keepPending,
+		logger:      logger,
+	}
+}
+
+// Run prunes the outbox periodically until the context is cancelled
+func (p *Pruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := p.repo.DeleteOlderThan(ctx, time.Now().Add(-p.retention), !p.keepPending)
+		if err == nil && deleted > 0 {
+			p.logger.WithField("count", deleted).Info("Pruned old outbox events")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
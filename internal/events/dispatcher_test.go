@@ -0,0 +1,152 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeSender records the events it's asked to send, failing them while fail returns an error
+type fakeSender struct {
+	fail func(event models.OutboxEvent, attempt int) error
+
+	mu       sync.Mutex
+	attempts []int64
+}
+
+func (s *fakeSender) Send(_ context.Context, event models.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts = append(s.attempts, event.ID)
+	attempt := 0
+	for _, id := range s.attempts {
+		if id == event.ID {
+			attempt++
+		}
+	}
+	if s.fail != nil {
+		return s.fail(event, attempt)
+	}
+	return nil
+}
+
+// passes reports the count returned by every DispatchPending call
+type passes struct {
+	repository.OutboxRepository
+	counts chan int
+}
+
+func (p *passes) DispatchPending(ctx context.Context, limit, maxAttempts int, dispatch func(event models.OutboxEvent) error) (int, error) {
+	count, err := p.OutboxRepository.DispatchPending(ctx, limit, maxAttempts, dispatch)
+	p.counts <- count
+	return count, err
+}
+
+// Helper function to open an outbox in an in-memory database holding the number of events passed
+func testOutbox(t *testing.T, events int) *passes {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is a new database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.Exec(`CREATE TABLE outbox_events (id INTEGER PRIMARY KEY, tx_id INTEGER, type TEXT, issue_id TEXT, namespace TEXT,
+		payload BLOB, attempts INTEGER NOT NULL DEFAULT 0, last_error TEXT, created_at DATETIME, dispatched_at DATETIME, parked_at DATETIME)`).Error; err != nil {
+		t.Fatal(err)
+	}
+	for range events {
+		if err := db.Exec(`INSERT INTO outbox_events (type, issue_id, namespace, payload, created_at)
+			VALUES ('issue.created', 'issue-1', 'team-a', CAST('{}' AS BLOB), CURRENT_TIMESTAMP)`).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &passes{OutboxRepository: repository.NewOutboxRepository(db, logger), counts: make(chan int, 100)}
+}
+
+// Helper function to run the dispatcher until wantHandled events were dispatched or parked,
+// returning the count of each pass
+func runDispatcher(t *testing.T, dispatcher *Dispatcher, outbox *passes, wantHandled int) []int {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var counts []int
+	handled := 0
+	timeout := time.After(5 * time.Second)
+	for handled < wantHandled {
+		select {
+		case count := <-outbox.counts:
+			counts = append(counts, count)
+			handled += count
+		case <-timeout:
+			t.Fatalf("handled %d events in passes %v, want %d", handled, counts, wantHandled)
+		}
+	}
+	return counts
+}
+
+func TestDispatcherDrainsBacklogWithParkedEvents(t *testing.T) {
+	outbox := testOutbox(t, 5)
+	sender := &fakeSender{fail: func(event models.OutboxEvent, _ int) error {
+		if event.ID == 2 {
+			return errors.New("sink rejected the event")
+		}
+		return nil
+	}}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	// The next poll is too far away to be reached, the backlog has to be drained in one go
+	dispatcher := NewDispatcher(outbox, sender, time.Hour, 2, 1, logger)
+
+	counts := runDispatcher(t, dispatcher, outbox, 5)
+
+	if !slices.Equal(counts, []int{2, 2, 1}) {
+		t.Errorf("passes handled %v, want [2 2 1]", counts)
+	}
+	// The parked event was tried once and didn't hold back the events after it
+	if !slices.Equal(sender.attempts, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("sent %v, want every event once in order", sender.attempts)
+	}
+}
+
+func TestDispatcherRetriesFailuresInOrder(t *testing.T) {
+	outbox := testOutbox(t, 3)
+	sender := &fakeSender{fail: func(event models.OutboxEvent, attempt int) error {
+		if event.ID == 2 && attempt <= 2 {
+			return errors.New("sink unavailable")
+		}
+		return nil
+	}}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dispatcher := NewDispatcher(outbox, sender, 10*time.Millisecond, 10, 5, logger)
+
+	runDispatcher(t, dispatcher, outbox, 3)
+
+	// Nothing after the failed event is sent until it succeeds
+	if !slices.Equal(sender.attempts, []int64{1, 2, 2, 2, 3}) {
+		t.Errorf("sent %v, want the failed event retried before the next one", sender.attempts)
+	}
+}
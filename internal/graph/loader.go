@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// How long a loader waits for more keys before fetching a batch. Resolvers for the items of a list
+// run concurrently, so this is long enough for all of them to ask for their keys.
+const loaderWait = 2 * time.Millisecond
+
+// loader batches the keys that resolvers running at the same time ask for into a single fetch,
+// so resolving a field of every issue in a list takes one query instead of one per issue.
+// Results are cached for the loader's lifetime, which is a single response.
+type loader[K comparable, V any] struct {
+	// fetch returns the values of the keys passed, keys without a value get the zero value
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	results map[K]*loaderResult[V]
+	pending []K
+}
+
+type loaderResult[V any] struct {
+	value V
+	err   error
+	done  chan struct{}
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, results: map[K]*loaderResult[V]{}}
+}
+
+// Load returns the value of a key, waiting for the batch it's fetched in
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	result, ok := l.results[key]
+	if !ok {
+		result = &loaderResult[V]{done: make(chan struct{})}
+		l.results[key] = result
+		l.pending = append(l.pending, key)
+		// The first key of a batch schedules fetching it
+		if len(l.pending) == 1 {
+			time.AfterFunc(loaderWait, func() { l.dispatch(ctx) })
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.value, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Helper function to fetch the keys waiting for a batch
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	batch := make([]*loaderResult[V], len(keys))
+	for i, key := range keys {
+		batch[i] = l.results[key]
+	}
+	l.mu.Unlock()
+
+	values, err := l.fetch(ctx, keys)
+	for i, key := range keys {
+		batch[i].value, batch[i].err = values[key], err
+		close(batch[i].done)
+	}
+}
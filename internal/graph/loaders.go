@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// errAccessDenied is returned for issues in namespaces the caller can't access
+var errAccessDenied = errors.New("access denied to this namespace")
+
+// loaders hold the batched lookups of a single response
+type loaders struct {
+	issues    *loader[string, *models.Issue]
+	scopes    *loader[string, *models.IssueScope]
+	links     *loader[string, []models.Link]
+	relations *loader[string, []models.RelatedIssue]
+	// Namespace access checks, so each namespace is only checked once
+	access *loader[string, error]
+}
+
+type loadersKey struct{}
+
+// WithLoaders returns a context with new loaders, for resolving a response
+func (r *Resolver) WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		issues:    newLoader(r.fetchIssues),
+		scopes:    newLoader(r.fetchScopes),
+		links:     newLoader(r.fetchLinks),
+		relations: newLoader(r.fetchRelations),
+		access:    newLoader(r.checkAccess),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func (r *Resolver) fetchIssues(ctx context.Context, ids []string) (map[string]*models.Issue, error) {
+	page, err := r.issueService.FindIssues(ctx, repository.IssueQueryFilters{
+		IDs:   ids,
+		Limit: len(ids),
+		// Associations are loaded by their own loaders when they're asked for
+		Include: &repository.IssueIncludes{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	issues := make(map[string]*models.Issue, len(page.Data))
+	for i := range page.Data {
+		issues[page.Data[i].ID] = &page.Data[i]
+	}
+	return issues, nil
+}
+
+func (r *Resolver) fetchScopes(ctx context.Context, ids []string) (map[string]*models.IssueScope, error) {
+	found, err := r.issueService.FindScopes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	scopes := make(map[string]*models.IssueScope, len(found))
+	for i := range found {
+		scopes[found[i].ID] = &found[i]
+	}
+	return scopes, nil
+}
+
+func (r *Resolver) fetchLinks(ctx context.Context, issueIDs []string) (map[string][]models.Link, error) {
+	found, err := r.issueService.FindLinks(ctx, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	links := make(map[string][]models.Link, len(issueIDs))
+	for _, link := range found {
+		links[link.IssueID] = append(links[link.IssueID], link)
+	}
+	return links, nil
+}
+
+// Helper function to fetch the relationships of issues, each relationship is returned for both of its issues
+func (r *Resolver) fetchRelations(ctx context.Context, issueIDs []string) (map[string][]models.RelatedIssue, error) {
+	found, err := r.issueService.FindRelations(ctx, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	relations := make(map[string][]models.RelatedIssue, len(issueIDs))
+	for _, relation := range found {
+		relations[relation.SourceID] = append(relations[relation.SourceID], relation)
+		relations[relation.TargetID] = append(relations[relation.TargetID], relation)
+	}
+	return relations, nil
+}
+
+// Helper function to check access to namespaces, the error for each namespace is its result
+func (r *Resolver) checkAccess(ctx context.Context, namespaces []string) (map[string]error, error) {
+	results := make(map[string]error, len(namespaces))
+	for _, namespace := range namespaces {
+		// The GraphQL API only reads issues
+		if err := r.namespaceChecker.CheckAccess(ctx, namespace, middleware.OperationRead); err != nil {
+			r.logger.WithError(err).WithField("namespace", namespace).Warn("Access Denied")
+			results[namespace] = errAccessDenied
+			if errors.Is(err, middleware.ErrUnauthenticated) {
+				results[namespace] = err
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,98 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+type IssueConnection struct {
+	Nodes      []*models.Issue `json:"nodes"`
+	TotalCount int             `json:"totalCount"`
+	// Pass as after to get the next page
+	NextCursor *string `json:"nextCursor,omitempty"`
+	PrevCursor *string `json:"prevCursor,omitempty"`
+}
+
+type IssueEvent struct {
+	// Pass to the Last-Event-ID header of GET /api/v1/issues/stream to catch up on missed events
+	ID   string         `json:"id"`
+	Type IssueEventType `json:"type"`
+	// The issue after the change, or before it when it was deleted
+	Issue *models.Issue `json:"issue"`
+}
+
+// Filters in the same format as the query params of GET /api/v1/issues
+type IssueFilter struct {
+	Ids          []string           `json:"ids,omitempty"`
+	Severity     *models.Severity   `json:"severity,omitempty"`
+	IssueType    *models.IssueType  `json:"issueType,omitempty"`
+	State        *models.IssueState `json:"state,omitempty"`
+	ResourceType *string            `json:"resourceType,omitempty"`
+	ResourceName *string            `json:"resourceName,omitempty"`
+	// Full-text search of titles, descriptions and scopes
+	Search *string `json:"search,omitempty"`
+	// Structured filter, e.g. "severity>=major AND detectedAt>now-7d"
+	Query *string `json:"query,omitempty"`
+}
+
+type IssueStatsKey struct {
+	Field string `json:"field"`
+	// Null for issues without a value for the field
+	Value *string `json:"value,omitempty"`
+}
+
+type Query struct {
+}
+
+type Subscription struct {
+}
+
+type IssueEventType string
+
+const (
+	IssueEventTypeCreated  IssueEventType = "CREATED"
+	IssueEventTypeUpdated  IssueEventType = "UPDATED"
+	IssueEventTypeResolved IssueEventType = "RESOLVED"
+	IssueEventTypeDeleted  IssueEventType = "DELETED"
+)
+
+var AllIssueEventType = []IssueEventType{
+	IssueEventTypeCreated,
+	IssueEventTypeUpdated,
+	IssueEventTypeResolved,
+	IssueEventTypeDeleted,
+}
+
+func (e IssueEventType) IsValid() bool {
+	switch e {
+	case IssueEventTypeCreated, IssueEventTypeUpdated, IssueEventTypeResolved, IssueEventTypeDeleted:
+		return true
+	}
+	return false
+}
+
+func (e IssueEventType) String() string {
+	return string(e)
+}
+
+func (e *IssueEventType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IssueEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid IssueEventType", str)
+	}
+	return nil
+}
+
+func (e IssueEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
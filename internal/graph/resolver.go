@@ -0,0 +1,157 @@
+// Package graph serves issues and their relationships over GraphQL
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate --config gqlgen.yml
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/konflux-ci/kite/internal/events"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Resolver resolves the schema with the same services as the REST API
+type Resolver struct {
+	issueService     *services.IssueService
+	broker           EventSubscriber
+	namespaceChecker *middleware.NamespaceChecker
+	logger           *logrus.Logger
+}
+
+// EventSubscriber delivers outbox events to subscriptions, an *events.Broker when serving
+type EventSubscriber interface {
+	Subscribe(ctx context.Context) (*events.Subscription, error)
+	Unsubscribe(sub *events.Subscription)
+}
+
+// Most issues returned in a page
+const maxPageSize = 100
+
+func NewResolver(issueService *services.IssueService, broker EventSubscriber, namespaceChecker *middleware.NamespaceChecker, logger *logrus.Logger) *Resolver {
+	return &Resolver{
+		issueService:     issueService,
+		broker:           broker,
+		namespaceChecker: namespaceChecker,
+		logger:           logger,
+	}
+}
+
+// Helper function to check the caller can access a namespace, with the same check as the REST API
+func (r *Resolver) authorize(ctx context.Context, namespace string) error {
+	if namespace == "" {
+		return errors.New("missing namespace")
+	}
+	denied, err := loadersFrom(ctx).access.Load(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return denied
+}
+
+// Helper function to log an unexpected error, returning an error that's safe to show
+func (r *Resolver) internalError(err error, message string) error {
+	r.logger.WithError(err).Error(message)
+	return errors.New(message)
+}
+
+// Helper function to resolve the relationships of an issue that match, loaded along with those of other issues
+func (r *Resolver) relations(ctx context.Context, issueID string, match func(relation models.RelatedIssue) bool) ([]*models.RelatedIssue, error) {
+	relations, err := loadersFrom(ctx).relations.Load(ctx, issueID)
+	if err != nil {
+		return nil, r.internalError(err, "failed to fetch related issues")
+	}
+	matching := []*models.RelatedIssue{}
+	for i := range relations {
+		if match(relations[i]) {
+			matching = append(matching, &relations[i])
+		}
+	}
+	return matching, nil
+}
+
+// Helper function to resolve an issue at one end of a relationship, which can be in another namespace.
+// The issue is used as it is when it was loaded with the relationship.
+func (r *Resolver) relatedIssue(ctx context.Context, id string, loaded *models.Issue) (*models.Issue, error) {
+	issue := loaded
+	if issue.ID == "" {
+		var err error
+		if issue, err = loadersFrom(ctx).issues.Load(ctx, id); err != nil {
+			return nil, r.internalError(err, "failed to fetch related issue")
+		}
+		if issue == nil {
+			return nil, nil
+		}
+	}
+	if err := r.authorize(ctx, issue.Namespace); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// Helper function to convert a filter to query filters, in the same way as the GET /issues query params
+func issueFilters(namespace string, filter *IssueFilter) (repository.IssueQueryFilters, error) {
+	filters := repository.IssueQueryFilters{Namespace: namespace}
+	if filter == nil {
+		return filters, nil
+	}
+
+	filters.IDs = filter.Ids
+	filters.Severity = filter.Severity
+	filters.IssueType = filter.IssueType
+	filters.State = filter.State
+	if filter.ResourceType != nil {
+		filters.ResourceType = *filter.ResourceType
+	}
+	if filter.ResourceName != nil {
+		filters.ResourceName = *filter.ResourceName
+	}
+	if filter.Search != nil {
+		filters.Search = *filter.Search
+	}
+	if filter.Query != nil && *filter.Query != "" {
+		parsed, err := repository.ParseIssueQuery(*filter.Query)
+		if err != nil {
+			return filters, fmt.Errorf("invalid query: %w", err)
+		}
+		filters.Query = parsed
+	}
+	return filters, nil
+}
+
+// Maps the types of outbox events to those in the schema
+var issueEventTypes = map[string]IssueEventType{
+	models.EventTypeIssueCreated:  IssueEventTypeCreated,
+	models.EventTypeIssueUpdated:  IssueEventTypeUpdated,
+	models.EventTypeIssueResolved: IssueEventTypeResolved,
+	models.EventTypeIssueDeleted:  IssueEventTypeDeleted,
+}
+
+// Helper function to convert an outbox event to a subscription event
+func issueEvent(event models.OutboxEvent) (*IssueEvent, error) {
+	eventType, ok := issueEventTypes[event.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", event.Type)
+	}
+	var issue models.Issue
+	if err := json.Unmarshal(event.Payload, &issue); err != nil {
+		return nil, err
+	}
+	return &IssueEvent{ID: strconv.FormatInt(event.ID, 10), Type: eventType, Issue: &issue}, nil
+}
+
+// Helper function to point at each item of a slice, as lists of objects are resolved
+func pointers[T any](items []T) []*T {
+	result := make([]*T, len(items))
+	for i := range items {
+		result[i] = &items[i]
+	}
+	return result
+}
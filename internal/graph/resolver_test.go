@@ -0,0 +1,353 @@
+package graph
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/events"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryIssues keeps issues and their relationships, recording the keys of each batched lookup
+type memoryIssues struct {
+	repository.IssueRepository
+	issues    []models.Issue
+	relations []models.RelatedIssue
+
+	mu            sync.Mutex
+	listCalls     int
+	idLookups     [][]string
+	relationCalls [][]string
+}
+
+func (m *memoryIssues) FindAll(_ context.Context, filters repository.IssueQueryFilters) (*repository.IssuePage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(filters.IDs) > 0 {
+		m.idLookups = append(m.idLookups, filters.IDs)
+	} else {
+		m.listCalls++
+	}
+
+	page := &repository.IssuePage{Issues: []models.Issue{}}
+	for _, issue := range m.issues {
+		if filters.Matches(&issue) && len(page.Issues) < filters.Limit {
+			page.Issues = append(page.Issues, issue)
+		}
+	}
+	page.Total = int64(len(page.Issues))
+	return page, nil
+}
+
+func (m *memoryIssues) FindRelations(_ context.Context, issueIDs []string) ([]models.RelatedIssue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.relationCalls = append(m.relationCalls, issueIDs)
+
+	var found []models.RelatedIssue
+	for _, relation := range m.relations {
+		if slices.Contains(issueIDs, relation.SourceID) || slices.Contains(issueIDs, relation.TargetID) {
+			found = append(found, relation)
+		}
+	}
+	return found, nil
+}
+
+// memorySubscriber hands each subscription to the test, which sends it events
+type memorySubscriber struct {
+	subscribed chan *events.Subscription
+}
+
+func (m *memorySubscriber) Subscribe(context.Context) (*events.Subscription, error) {
+	sub := &events.Subscription{Events: make(chan models.OutboxEvent, 1)}
+	m.subscribed <- sub
+	return sub, nil
+}
+
+func (m *memorySubscriber) Unsubscribe(*events.Subscription) {}
+
+// namespaceAuthenticator authenticates every token as a principal that can only access one namespace
+type namespaceAuthenticator string
+
+func (a namespaceAuthenticator) AuthenticateToken(context.Context, string) (*middleware.Principal, error) {
+	return &middleware.Principal{Name: "alice", Authorizer: a}, nil
+}
+
+func (a namespaceAuthenticator) Allowed(_ context.Context, namespace string, _ middleware.Operation) (bool, error) {
+	return namespace == string(a), nil
+}
+
+type graphResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+		Path    []any  `json:"path"`
+	} `json:"errors"`
+}
+
+func issueID(n int) string {
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", n)
+}
+
+// Helper function to serve the schema the way the router does, to a caller that can only access team-a
+func newTestServer(t *testing.T, repo *memoryIssues, subscriber EventSubscriber, complexityLimit int) *httptest.Server {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	authenticator := namespaceAuthenticator("team-a")
+	checker := middleware.NewNamespaceCheckerWith(logger, authenticator)
+	resolver := NewResolver(services.NewIssueService(repo, logger), subscriber, checker, logger)
+	gql := NewServer(resolver, complexityLimit)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ := authenticator.AuthenticateToken(r.Context(), "")
+		gql.ServeHTTP(w, r.WithContext(middleware.WithPrincipal(r.Context(), principal)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Helper function to run a query over POST
+func postQuery(t *testing.T, server *httptest.Server, query string) graphResponse {
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var result graphResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestQueryOverComplexityLimitIsRejected(t *testing.T) {
+	repo := &memoryIssues{issues: []models.Issue{{ID: issueID(1), Namespace: "team-a"}}}
+	server := newTestServer(t, repo, nil, 1000)
+
+	// 100 issues with an estimated 10 relationships each
+	result := postQuery(t, server, `{ issues(namespace: "team-a", first: 100) { nodes { relatedFrom { target { id } } } } }`)
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "exceeds the limit of 1000") {
+		t.Fatalf("errors = %+v, want the complexity limit to be exceeded", result.Errors)
+	}
+	if repo.listCalls != 0 {
+		t.Errorf("issues were listed %d times for a rejected query", repo.listCalls)
+	}
+
+	result = postQuery(t, server, `{ issues(namespace: "team-a", first: 10) { nodes { relatedFrom { target { id } } } } }`)
+	if len(result.Errors) != 0 {
+		t.Errorf("errors = %+v for a query under the limit", result.Errors)
+	}
+}
+
+func TestRelatedIssuesLoadInOneBatch(t *testing.T) {
+	const n = 5
+	repo := &memoryIssues{}
+	for i := 1; i <= n; i++ {
+		repo.issues = append(repo.issues, models.Issue{ID: issueID(i), Namespace: "team-a"})
+		repo.relations = append(repo.relations, models.RelatedIssue{ID: issueID(100 + i), SourceID: issueID(i), TargetID: issueID(n + i)})
+	}
+	for i := 1; i <= n; i++ {
+		repo.issues = append(repo.issues, models.Issue{ID: issueID(n + i), Namespace: "team-a"})
+	}
+	server := newTestServer(t, repo, nil, 10000)
+
+	result := postQuery(t, server, fmt.Sprintf(`{ issues(namespace: "team-a", first: %d) { nodes { id relatedFrom { target { id } } relatedTo { id } } } }`, n))
+	if len(result.Errors) != 0 {
+		t.Fatalf("errors = %+v", result.Errors)
+	}
+
+	var data struct {
+		Issues struct {
+			Nodes []struct {
+				ID          string
+				RelatedFrom []struct{ Target struct{ ID string } }
+				RelatedTo   []struct{ ID string }
+			}
+		}
+	}
+	if err := json.Unmarshal(result.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Issues.Nodes) != n {
+		t.Fatalf("got %d issues, want %d", len(data.Issues.Nodes), n)
+	}
+	for i, node := range data.Issues.Nodes {
+		if len(node.RelatedFrom) != 1 || node.RelatedFrom[0].Target.ID != issueID(n+i+1) {
+			t.Errorf("issue %s related from %+v, want target %s", node.ID, node.RelatedFrom, issueID(n+i+1))
+		}
+		if len(node.RelatedTo) != 0 {
+			t.Errorf("issue %s related to %+v, want none", node.ID, node.RelatedTo)
+		}
+	}
+
+	if len(repo.relationCalls) != 1 || len(repo.relationCalls[0]) != n {
+		t.Errorf("relations fetched in %v, want one batch of %d issues", repo.relationCalls, n)
+	}
+	if len(repo.idLookups) != 1 || len(repo.idLookups[0]) != n {
+		t.Errorf("related issues fetched in %v, want one batch of %d issues", repo.idLookups, n)
+	}
+}
+
+func TestCrossNamespaceQueriesAreDenied(t *testing.T) {
+	repo := &memoryIssues{
+		issues: []models.Issue{
+			{ID: issueID(1), Namespace: "team-a"},
+			{ID: issueID(2), Namespace: "team-b"},
+		},
+		relations: []models.RelatedIssue{{ID: issueID(3), SourceID: issueID(1), TargetID: issueID(2)}},
+	}
+	server := newTestServer(t, repo, nil, 10000)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantPath  string
+		wantLists bool
+	}{
+		{name: "issues in another namespace", query: `{ issues(namespace: "team-b") { nodes { id } } }`, wantPath: "issues"},
+		{name: "stats in another namespace", query: `{ issueStats(namespace: "team-b") { total } }`, wantPath: "issueStats"},
+		{name: "issue of another namespace", query: fmt.Sprintf(`{ issue(namespace: "team-a", id: %q) { id } }`, issueID(2)), wantPath: "issue"},
+		{name: "related issue in another namespace", query: `{ issues(namespace: "team-a") { nodes { relatedFrom { target { id } } } } }`, wantPath: "issues.nodes.0.relatedFrom.0.target", wantLists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.listCalls = 0
+			result := postQuery(t, server, tt.query)
+
+			if len(result.Errors) != 1 || result.Errors[0].Message != errAccessDenied.Error() {
+				t.Fatalf("errors = %+v, want access denied", result.Errors)
+			}
+			var path []string
+			for _, segment := range result.Errors[0].Path {
+				path = append(path, fmt.Sprint(segment))
+			}
+			if got := strings.Join(path, "."); got != tt.wantPath {
+				t.Errorf("error path = %s, want %s", got, tt.wantPath)
+			}
+			if strings.Contains(string(result.Data), issueID(2)) {
+				t.Errorf("data %s has the issue in another namespace", result.Data)
+			}
+			if listed := repo.listCalls > 0; listed != tt.wantLists {
+				t.Errorf("issues listed = %v, want %v", listed, tt.wantLists)
+			}
+		})
+	}
+}
+
+// Helper function to start a subscription over Server-Sent Events, returning its events
+func subscribe(t *testing.T, server *httptest.Server, query string) *bufio.Scanner {
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return bufio.NewScanner(resp.Body)
+}
+
+// Helper function to read the next response sent to a subscription
+func nextResponse(t *testing.T, scanner *bufio.Scanner) graphResponse {
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var result graphResponse
+		if err := json.Unmarshal([]byte(data), &result); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	t.Fatalf("subscription ended: %v", scanner.Err())
+	return graphResponse{}
+}
+
+func TestSubscriptionReceivesOutboxEvents(t *testing.T) {
+	subscriber := &memorySubscriber{subscribed: make(chan *events.Subscription, 1)}
+	server := newTestServer(t, &memoryIssues{}, subscriber, 10000)
+
+	stream := subscribe(t, server, `subscription { issueChanged(namespace: "team-a", filter: {severity: CRITICAL}) { id type issue { id namespace } } }`)
+	var sub *events.Subscription
+	select {
+	case sub = <-subscriber.subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription wasn't started")
+	}
+
+	// Only the last event is in the namespace and matches the filter
+	for id, issue := range []models.Issue{
+		{ID: issueID(1), Namespace: "team-b", Severity: models.SeverityCritical},
+		{ID: issueID(2), Namespace: "team-a", Severity: models.SeverityMinor},
+		{ID: issueID(3), Namespace: "team-a", Severity: models.SeverityCritical},
+	} {
+		payload, err := json.Marshal(issue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sub.Events <- models.OutboxEvent{ID: int64(id + 1), Type: models.EventTypeIssueUpdated, Namespace: issue.Namespace, Payload: payload}
+	}
+
+	result := nextResponse(t, stream)
+	if len(result.Errors) != 0 {
+		t.Fatalf("errors = %+v", result.Errors)
+	}
+	var data struct {
+		IssueChanged struct {
+			ID    string
+			Type  string
+			Issue struct{ ID, Namespace string }
+		}
+	}
+	if err := json.Unmarshal(result.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if got := data.IssueChanged; got.ID != "3" || got.Type != "UPDATED" || got.Issue.ID != issueID(3) || got.Issue.Namespace != "team-a" {
+		t.Errorf("event = %+v, want event 3 updating %s", got, issueID(3))
+	}
+}
+
+func TestSubscriptionToAnotherNamespaceIsDenied(t *testing.T) {
+	subscriber := &memorySubscriber{subscribed: make(chan *events.Subscription, 1)}
+	server := newTestServer(t, &memoryIssues{}, subscriber, 10000)
+
+	stream := subscribe(t, server, `subscription { issueChanged(namespace: "team-b") { id } }`)
+	result := nextResponse(t, stream)
+	if len(result.Errors) != 1 || result.Errors[0].Message != errAccessDenied.Error() {
+		t.Errorf("errors = %+v, want access denied", result.Errors)
+	}
+	if len(subscriber.subscribed) != 0 {
+		t.Error("subscribed to events of another namespace")
+	}
+}
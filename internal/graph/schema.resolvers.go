@@ -0,0 +1,253 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.64
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// Scope is the resolver for the scope field.
+func (r *issueResolver) Scope(ctx context.Context, obj *models.Issue) (*models.IssueScope, error) {
+	if obj.Scope.ID != "" {
+		return &obj.Scope, nil
+	}
+	scope, err := loadersFrom(ctx).scopes.Load(ctx, obj.ScopeID)
+	if err != nil {
+		return nil, r.internalError(err, "failed to fetch scope")
+	}
+	if scope == nil {
+		return nil, fmt.Errorf("scope %s not found", obj.ScopeID)
+	}
+	return scope, nil
+}
+
+// Links is the resolver for the links field.
+func (r *issueResolver) Links(ctx context.Context, obj *models.Issue) ([]*models.Link, error) {
+	links := obj.Links
+	if links == nil {
+		var err error
+		if links, err = loadersFrom(ctx).links.Load(ctx, obj.ID); err != nil {
+			return nil, r.internalError(err, "failed to fetch links")
+		}
+	}
+	return pointers(links), nil
+}
+
+// RelatedFrom is the resolver for the relatedFrom field.
+func (r *issueResolver) RelatedFrom(ctx context.Context, obj *models.Issue) ([]*models.RelatedIssue, error) {
+	if obj.RelatedFrom != nil {
+		return pointers(obj.RelatedFrom), nil
+	}
+	return r.relations(ctx, obj.ID, func(relation models.RelatedIssue) bool { return relation.SourceID == obj.ID })
+}
+
+// RelatedTo is the resolver for the relatedTo field.
+func (r *issueResolver) RelatedTo(ctx context.Context, obj *models.Issue) ([]*models.RelatedIssue, error) {
+	if obj.RelatedTo != nil {
+		return pointers(obj.RelatedTo), nil
+	}
+	return r.relations(ctx, obj.ID, func(relation models.RelatedIssue) bool { return relation.TargetID == obj.ID })
+}
+
+// Source is the resolver for the source field.
+func (r *issueRelationResolver) Source(ctx context.Context, obj *models.RelatedIssue) (*models.Issue, error) {
+	return r.relatedIssue(ctx, obj.SourceID, &obj.Source)
+}
+
+// Target is the resolver for the target field.
+func (r *issueRelationResolver) Target(ctx context.Context, obj *models.RelatedIssue) (*models.Issue, error) {
+	return r.relatedIssue(ctx, obj.TargetID, &obj.Target)
+}
+
+// Key is the resolver for the key field.
+func (r *issueStatsGroupResolver) Key(ctx context.Context, obj *dto.IssueStatsGroup) ([]*IssueStatsKey, error) {
+	key := make([]*IssueStatsKey, 0, len(obj.Key))
+	for _, field := range slices.Sorted(maps.Keys(obj.Key)) {
+		key = append(key, &IssueStatsKey{Field: field, Value: obj.Key[field]})
+	}
+	return key, nil
+}
+
+// Issues is the resolver for the issues field.
+func (r *queryResolver) Issues(ctx context.Context, namespace string, filter *IssueFilter, sort *string, first int, after *string, offset int) (*IssueConnection, error) {
+	if err := r.authorize(ctx, namespace); err != nil {
+		return nil, err
+	}
+	filters, err := issueFilters(namespace, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if first < 1 || first > maxPageSize {
+		return nil, fmt.Errorf("first must be between 1 and %d", maxPageSize)
+	}
+	if offset < 0 {
+		return nil, errors.New("offset can't be negative")
+	}
+	filters.Limit = first
+	filters.Offset = offset
+	if sort != nil && *sort != "" {
+		if filters.Sort, err = repository.ParseSort(*sort); err != nil {
+			return nil, fmt.Errorf("invalid sort: %w", err)
+		}
+	}
+	if after != nil && *after != "" {
+		if len(filters.Sort) > 0 {
+			return nil, errors.New("cursor pagination is only supported with the default sort")
+		}
+		if filters.Cursor, err = repository.DecodeCursor(*after); err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		filters.Offset = 0
+	}
+	// Associations are loaded by their own loaders when they're asked for
+	filters.Include = &repository.IssueIncludes{}
+
+	result, err := r.issueService.FindIssues(ctx, filters)
+	if err != nil {
+		return nil, r.internalError(err, "failed to fetch issues")
+	}
+
+	connection := &IssueConnection{Nodes: pointers(result.Data), TotalCount: int(result.Total)}
+	if result.NextCursor != "" {
+		connection.NextCursor = &result.NextCursor
+	}
+	if result.PrevCursor != "" {
+		connection.PrevCursor = &result.PrevCursor
+	}
+	return connection, nil
+}
+
+// Issue is the resolver for the issue field.
+func (r *queryResolver) Issue(ctx context.Context, namespace string, id string) (*models.Issue, error) {
+	if err := r.authorize(ctx, namespace); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid issue ID %q", id)
+	}
+
+	issue, err := loadersFrom(ctx).issues.Load(ctx, id)
+	if err != nil {
+		return nil, r.internalError(err, "failed to fetch issue")
+	}
+	if issue == nil {
+		return nil, nil
+	}
+	if issue.Namespace != namespace {
+		return nil, errAccessDenied
+	}
+	return issue, nil
+}
+
+// IssueStats is the resolver for the issueStats field.
+func (r *queryResolver) IssueStats(ctx context.Context, namespace string, filter *IssueFilter, groupBy []string, dateField *string) (*dto.IssueStatsResponse, error) {
+	if err := r.authorize(ctx, namespace); err != nil {
+		return nil, err
+	}
+	filters, err := issueFilters(namespace, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var options repository.IssueStatsOptions
+	if len(groupBy) > 0 {
+		if options.GroupBy, err = repository.ParseGroupBy(strings.Join(groupBy, ",")); err != nil {
+			return nil, fmt.Errorf("invalid groupBy: %w", err)
+		}
+	}
+	if dateField != nil && *dateField != "" {
+		if options.DateField, err = repository.ParseDateField(*dateField); err != nil {
+			return nil, fmt.Errorf("invalid dateField: %w", err)
+		}
+	}
+
+	stats, err := r.issueService.GetIssueStats(ctx, filters, options)
+	if err != nil {
+		return nil, r.internalError(err, "failed to fetch issue stats")
+	}
+	return stats, nil
+}
+
+// IssueChanged is the resolver for the issueChanged field.
+func (r *subscriptionResolver) IssueChanged(ctx context.Context, namespace string, filter *IssueFilter) (<-chan *IssueEvent, error) {
+	if err := r.authorize(ctx, namespace); err != nil {
+		return nil, err
+	}
+	filters, err := issueFilters(namespace, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := r.broker.Subscribe(ctx)
+	if err != nil {
+		return nil, r.internalError(err, "failed to subscribe to issue changes")
+	}
+
+	changes := make(chan *IssueEvent)
+	go func() {
+		defer close(changes)
+		defer r.broker.Unsubscribe(sub)
+		for {
+			var event models.OutboxEvent
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok = <-sub.Events:
+				if !ok {
+					// Dropped for falling behind, clients resubscribe and catch up with the REST stream
+					return
+				}
+			}
+
+			change, err := issueEvent(event)
+			if err != nil {
+				r.logger.WithError(err).WithField("event_id", event.ID).Warn("Failed to decode issue event")
+				continue
+			}
+			if !filters.Matches(change.Issue) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case changes <- change:
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// Issue returns IssueResolver implementation.
+func (r *Resolver) Issue() IssueResolver { return &issueResolver{r} }
+
+// IssueRelation returns IssueRelationResolver implementation.
+func (r *Resolver) IssueRelation() IssueRelationResolver { return &issueRelationResolver{r} }
+
+// IssueStatsGroup returns IssueStatsGroupResolver implementation.
+func (r *Resolver) IssueStatsGroup() IssueStatsGroupResolver { return &issueStatsGroupResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+type issueResolver struct{ *Resolver }
+type issueRelationResolver struct{ *Resolver }
+type issueStatsGroupResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Relationships assumed per issue when estimating the complexity of a query
+const estimatedRelations = 10
+
+// Links assumed per issue when estimating the complexity of a query
+const estimatedLinks = 5
+
+// How often subscribers over websockets are pinged, keeping idle connections open through proxies
+const keepAliveInterval = 15 * time.Second
+
+// NewServer returns a GraphQL server for the resolver. Queries estimated to be more complex than
+// complexityLimit are rejected before they run, with lists counted as their page size or expected length.
+func NewServer(resolver *Resolver, complexityLimit int) *handler.Server {
+	cfg := Config{Resolvers: resolver}
+	cfg.Complexity.Query.Issues = func(childComplexity int, _ string, _ *IssueFilter, _ *string, first int, _ *string, _ int) int {
+		return 1 + first*childComplexity
+	}
+	cfg.Complexity.Issue.Links = func(childComplexity int) int {
+		return 1 + estimatedLinks*childComplexity
+	}
+	cfg.Complexity.Issue.RelatedFrom = func(childComplexity int) int {
+		return 1 + estimatedRelations*childComplexity
+	}
+	cfg.Complexity.Issue.RelatedTo = func(childComplexity int) int {
+		return 1 + estimatedRelations*childComplexity
+	}
+
+	server := handler.New(NewExecutableSchema(cfg))
+	// Subscriptions are served over websockets and Server-Sent Events, queries over GET and POST
+	server.AddTransport(transport.Websocket{KeepAlivePingInterval: keepAliveInterval})
+	server.AddTransport(transport.SSE{})
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+
+	server.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(complexityLimit))
+
+	// Subscriptions resolve their root field once per operation, before any response is made
+	server.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(resolver.WithLoaders(ctx))
+	})
+	// Every response gets its own loaders, so subscriptions don't keep serving what earlier events loaded
+	server.AroundResponses(func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+		return next(resolver.WithLoaders(ctx))
+	})
+	return server
+}
@@ -0,0 +1,182 @@
+package grpc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/repository"
+	kitev1 "github.com/konflux-ci/kite/pkg/api/kite/v1"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var severities = map[models.Severity]kitev1.Severity{
+	models.SeverityInfo:     kitev1.Severity_SEVERITY_INFO,
+	models.SeverityMinor:    kitev1.Severity_SEVERITY_MINOR,
+	models.SeverityMajor:    kitev1.Severity_SEVERITY_MAJOR,
+	models.SeverityCritical: kitev1.Severity_SEVERITY_CRITICAL,
+}
+
+var issueTypes = map[models.IssueType]kitev1.IssueType{
+	models.IssueTypeBuild:      kitev1.IssueType_ISSUE_TYPE_BUILD,
+	models.IssueTypeTest:       kitev1.IssueType_ISSUE_TYPE_TEST,
+	models.IssueTypeRelease:    kitev1.IssueType_ISSUE_TYPE_RELEASE,
+	models.IssueTypeDependency: kitev1.IssueType_ISSUE_TYPE_DEPENDENCY,
+	models.IssueTypePipeline:   kitev1.IssueType_ISSUE_TYPE_PIPELINE,
+}
+
+var issueStates = map[models.IssueState]kitev1.IssueState{
+	models.IssueStateActive:   kitev1.IssueState_ISSUE_STATE_ACTIVE,
+	models.IssueStateResolved: kitev1.IssueState_ISSUE_STATE_RESOLVED,
+}
+
+// Helper function to find the model value of a protobuf enum value, empty when it's unspecified or unknown
+func fromEnum[M ~string, P comparable](values map[M]P, value P) M {
+	for model, proto := range values {
+		if proto == value {
+			return model
+		}
+	}
+	return ""
+}
+
+// Helper function to convert an optional protobuf enum value, which must be a known value when it's set
+func fromOptionalEnum[M ~string, P interface {
+	comparable
+	fmt.Stringer
+}](values map[M]P, value *P) (*M, error) {
+	if value == nil {
+		return nil, nil
+	}
+	model := fromEnum(values, *value)
+	if model == "" {
+		return nil, fmt.Errorf("invalid value %s", *value)
+	}
+	return &model, nil
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func issueToProto(issue *models.Issue) *kitev1.Issue {
+	result := &kitev1.Issue{
+		Id:          issue.ID,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Severity:    severities[issue.Severity],
+		IssueType:   issueTypes[issue.IssueType],
+		State:       issueStates[issue.State],
+		DetectedAt:  timestamp(&issue.DetectedAt),
+		ResolvedAt:  timestamp(issue.ResolvedAt),
+		Namespace:   issue.Namespace,
+		Assignee:    issue.Assignee,
+		Labels:      issue.Labels,
+		Scope: &kitev1.IssueScope{
+			Id:                issue.Scope.ID,
+			ResourceType:      issue.Scope.ResourceType,
+			ResourceName:      issue.Scope.ResourceName,
+			ResourceNamespace: issue.Scope.ResourceNamespace,
+		},
+		Version:   issue.Version,
+		CreatedAt: timestamp(&issue.CreatedAt),
+		UpdatedAt: timestamp(&issue.UpdatedAt),
+	}
+	for _, link := range issue.Links {
+		result.Links = append(result.Links, &kitev1.Link{Id: link.ID, Title: link.Title, Url: link.URL})
+	}
+	for _, related := range issue.RelatedFrom {
+		result.RelatedFrom = append(result.RelatedFrom, relatedToProto(related))
+	}
+	for _, related := range issue.RelatedTo {
+		result.RelatedTo = append(result.RelatedTo, relatedToProto(related))
+	}
+	return result
+}
+
+func relatedToProto(related models.RelatedIssue) *kitev1.RelatedIssue {
+	return &kitev1.RelatedIssue{Id: related.ID, SourceId: related.SourceID, TargetId: related.TargetID}
+}
+
+// Helper function to convert links to create, which must not be nil
+func linksFromProto(links []*kitev1.Link) []dto.CreateLinkRequest {
+	result := []dto.CreateLinkRequest{}
+	for _, link := range links {
+		result = append(result, dto.CreateLinkRequest{Title: link.GetTitle(), URL: link.GetUrl()})
+	}
+	return result
+}
+
+func createIssueFromProto(req *kitev1.CreateIssueRequest) dto.CreateIssueRequest {
+	result := dto.CreateIssueRequest{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Severity:    fromEnum(severities, req.GetSeverity()),
+		IssueType:   fromEnum(issueTypes, req.GetIssueType()),
+		State:       fromEnum(issueStates, req.GetState()),
+		Namespace:   req.GetNamespace(),
+		Scope: dto.ScopeReqBody{
+			ResourceType:      req.GetScope().GetResourceType(),
+			ResourceName:      req.GetScope().GetResourceName(),
+			ResourceNamespace: req.GetScope().GetResourceNamespace(),
+		},
+	}
+	if len(req.GetLinks()) > 0 {
+		result.Links = linksFromProto(req.GetLinks())
+	}
+	return result
+}
+
+func updateIssueFromProto(req *kitev1.UpdateIssueRequest) (dto.UpdateIssueRequest, error) {
+	result := dto.UpdateIssueRequest{
+		Title:       req.Title,
+		Description: req.Description,
+	}
+
+	var err error
+	if result.Severity, err = fromOptionalEnum(severities, req.Severity); err != nil {
+		return result, fmt.Errorf("invalid severity: %w", err)
+	}
+	if result.IssueType, err = fromOptionalEnum(issueTypes, req.IssueType); err != nil {
+		return result, fmt.Errorf("invalid issue type: %w", err)
+	}
+	if result.State, err = fromOptionalEnum(issueStates, req.State); err != nil {
+		return result, fmt.Errorf("invalid state: %w", err)
+	}
+
+	if req.GetResolvedAt() != nil {
+		if err := req.GetResolvedAt().CheckValid(); err != nil {
+			return result, fmt.Errorf("invalid resolved at: %w", err)
+		}
+		resolvedAt := req.GetResolvedAt().AsTime()
+		result.ResolvedAt = &resolvedAt
+	}
+	if req.GetLinks() != nil {
+		result.Links = linksFromProto(req.GetLinks().GetLinks())
+	}
+	return result, nil
+}
+
+// Helper function to convert list filters, in the same way as the GET /issues query params
+func issueFiltersFromProto(req *kitev1.ListIssuesRequest) repository.IssueQueryFilters {
+	filters := repository.IssueQueryFilters{
+		Namespace:    req.GetNamespace(),
+		ResourceType: req.GetResourceType(),
+		ResourceName: req.GetResourceName(),
+		Search:       req.GetSearch(),
+	}
+	if severity := fromEnum(severities, req.GetSeverity()); severity != "" {
+		filters.Severity = &severity
+	}
+	if issueType := fromEnum(issueTypes, req.GetIssueType()); issueType != "" {
+		filters.IssueType = &issueType
+	}
+	if state := fromEnum(issueStates, req.GetState()); state != "" {
+		filters.State = &state
+	}
+	return filters
+}
@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/middleware"
+	kitev1 "github.com/konflux-ci/kite/pkg/api/kite/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// namespacedRequest is a request made in a namespace, every kite.v1 request has one
+type namespacedRequest interface {
+	GetNamespace() string
+}
+
+// Methods that only read issues, every other method needs write access
+var readMethods = map[string]bool{
+	kitev1.IssueService_ListIssues_FullMethodName: true,
+	kitev1.IssueService_GetIssue_FullMethodName:   true,
+}
+
+// namespaceAuthorizer applies the same namespace access check as NamespaceChecker does to the REST API,
+// authenticating callers from the bearer token in the authorization metadata.
+// Requests without a namespace field, such as health checks and reflection, aren't checked.
+type namespaceAuthorizer struct {
+	checker *middleware.NamespaceChecker
+	logger  *logrus.Logger
+}
+
+// Helper function to add the caller authenticated from the call's metadata to its context
+func (a *namespaceAuthorizer) authenticate(ctx context.Context) (context.Context, error) {
+	token, ok := middleware.BearerToken(strings.Join(metadata.ValueFromIncomingContext(ctx, "authorization"), ""))
+	if !ok {
+		return ctx, nil
+	}
+
+	principal, err := a.checker.AuthenticateToken(ctx, token)
+	if err != nil {
+		a.logger.WithError(err).Warn("Authentication failed")
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	if principal == nil {
+		return ctx, nil
+	}
+	return middleware.WithPrincipal(ctx, principal), nil
+}
+
+// Helper function to check access to the namespace of a request
+func (a *namespaceAuthorizer) authorize(ctx context.Context, method string, req any) error {
+	namespaced, ok := req.(namespacedRequest)
+	if !ok {
+		return nil
+	}
+
+	namespace := namespaced.GetNamespace()
+	if namespace == "" {
+		return status.Error(codes.InvalidArgument, "missing namespace")
+	}
+
+	operation := middleware.OperationWrite
+	if readMethods[method] {
+		operation = middleware.OperationRead
+	}
+	if err := a.checker.CheckAccess(ctx, namespace, operation); err != nil {
+		a.logger.WithError(err).WithField("namespace", namespace).Warn("Access Denied")
+		if errors.Is(err, middleware.ErrUnauthenticated) {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		return status.Error(codes.PermissionDenied, "access denied to this namespace")
+	}
+	return nil
+}
+
+func (a *namespaceAuthorizer) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.authorize(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *namespaceAuthorizer) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx, authorize: func(req any) error {
+		return a.authorize(ctx, info.FullMethod, req)
+	}})
+}
+
+// authorizedStream checks access to the namespace of each message received, before the handler sees it
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	authorize func(req any) error
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.authorize(m)
+}
+
+// Helper function to log a call the same way middleware.Logger logs HTTP requests
+func logCall(logger *logrus.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	logEntry := logger.WithFields(logrus.Fields{
+		"method":   method,
+		"code":     code.String(),
+		"duration": time.Since(start),
+	})
+
+	if code != codes.OK {
+		logEntry.Warn("gRPC Call")
+	} else {
+		logEntry.Info("gRPC Call")
+	}
+}
+
+func unaryLogger(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func streamLogger(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(logger, info.FullMethod, start, err)
+		return err
+	}
+}
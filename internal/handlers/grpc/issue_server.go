@@ -0,0 +1,237 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	kitev1 "github.com/konflux-ci/kite/pkg/api/kite/v1"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Issues loaded at a time when streaming a list
+const listPageSize = 100
+
+// IssueServer implements kite.v1.IssueService on top of the same service as the REST API
+type IssueServer struct {
+	kitev1.UnimplementedIssueServiceServer
+
+	issueService *services.IssueService
+	logger       *logrus.Logger
+}
+
+func NewIssueServer(issueService *services.IssueService, logger *logrus.Logger) *IssueServer {
+	return &IssueServer{
+		issueService: issueService,
+		logger:       logger,
+	}
+}
+
+// ListIssues streams the issues matching the filters a page at a time, following cursors so
+// issues created while streaming don't shift the pages
+func (s *IssueServer) ListIssues(req *kitev1.ListIssuesRequest, stream kitev1.IssueService_ListIssuesServer) error {
+	filters := issueFiltersFromProto(req)
+	if req.GetQuery() != "" {
+		parsed, err := repository.ParseIssueQuery(req.GetQuery())
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid query: %v", err)
+		}
+		filters.Query = parsed
+	}
+	if req.GetLimit() < 0 {
+		return status.Error(codes.InvalidArgument, "limit can't be negative")
+	}
+
+	remaining := int(req.GetLimit())
+	for {
+		filters.Limit = listPageSize
+		if remaining > 0 && remaining < listPageSize {
+			filters.Limit = remaining
+		}
+
+		page, err := s.issueService.FindIssues(stream.Context(), filters)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to fetch issues")
+			return status.Error(codes.Internal, "failed to fetch issues")
+		}
+		for i := range page.Data {
+			if err := stream.Send(&kitev1.ListIssuesResponse{Issue: issueToProto(&page.Data[i])}); err != nil {
+				return err
+			}
+		}
+
+		if req.GetLimit() > 0 {
+			if remaining -= len(page.Data); remaining <= 0 {
+				return nil
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		if filters.Cursor, err = repository.DecodeCursor(page.NextCursor); err != nil {
+			s.logger.WithError(err).Error("Failed to decode next page cursor")
+			return status.Error(codes.Internal, "failed to fetch issues")
+		}
+	}
+}
+
+// GetIssue gets an issue in the request's namespace
+func (s *IssueServer) GetIssue(ctx context.Context, req *kitev1.GetIssueRequest) (*kitev1.GetIssueResponse, error) {
+	issue, err := s.findIssue(ctx, req.GetNamespace(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &kitev1.GetIssueResponse{Issue: issueToProto(issue)}, nil
+}
+
+// CreateIssue creates an issue, validated the same as POST /issues
+func (s *IssueServer) CreateIssue(ctx context.Context, req *kitev1.CreateIssueRequest) (*kitev1.CreateIssueResponse, error) {
+	createReq := createIssueFromProto(req)
+	if err := binding.Validator.ValidateStruct(createReq); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	if err := services.ValidateCreateIssueRequest(createReq); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	issue, err := s.issueService.CreateIssue(ctx, createReq)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create issue")
+		return nil, status.Error(codes.Internal, "failed to create issue")
+	}
+	return &kitev1.CreateIssueResponse{Issue: issueToProto(issue)}, nil
+}
+
+// UpdateIssue changes the fields set in the request
+func (s *IssueServer) UpdateIssue(ctx context.Context, req *kitev1.UpdateIssueRequest) (*kitev1.UpdateIssueResponse, error) {
+	updateReq, err := updateIssueFromProto(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	for _, link := range updateReq.Links {
+		if err := binding.Validator.ValidateStruct(link); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid link: %v", err)
+		}
+	}
+
+	if _, err := s.findIssue(ctx, req.GetNamespace(), req.GetId()); err != nil {
+		return nil, err
+	}
+
+	issue, err := s.issueService.UpdateIssue(ctx, req.GetId(), updateReq, req.GetVersion())
+	if err != nil {
+		return nil, s.writeError(err, req.GetId(), "failed to update issue")
+	}
+	return &kitev1.UpdateIssueResponse{Issue: issueToProto(issue)}, nil
+}
+
+// ResolveIssue marks an issue as resolved now
+func (s *IssueServer) ResolveIssue(ctx context.Context, req *kitev1.ResolveIssueRequest) (*kitev1.ResolveIssueResponse, error) {
+	if _, err := s.findIssue(ctx, req.GetNamespace(), req.GetId()); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	state := models.IssueStateResolved
+	updateReq := dto.UpdateIssueRequest{
+		State:      &state,
+		ResolvedAt: &now,
+	}
+	issue, err := s.issueService.UpdateIssue(ctx, req.GetId(), updateReq, req.GetVersion())
+	if err != nil {
+		return nil, s.writeError(err, req.GetId(), "failed to resolve issue")
+	}
+	return &kitev1.ResolveIssueResponse{Issue: issueToProto(issue)}, nil
+}
+
+// ResolveIssuesByScope resolves the active issues for a resource in the request's namespace
+func (s *IssueServer) ResolveIssuesByScope(ctx context.Context, req *kitev1.ResolveIssuesByScopeRequest) (*kitev1.ResolveIssuesByScopeResponse, error) {
+	if req.GetResourceType() == "" || req.GetResourceName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "resource type and resource name are required")
+	}
+
+	resolved, err := s.issueService.ResolveIssuesByScope(ctx, req.GetResourceType(), req.GetResourceName(), req.GetNamespace())
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to resolve issues by scope")
+		return nil, status.Error(codes.Internal, "failed to resolve issues")
+	}
+	return &kitev1.ResolveIssuesByScopeResponse{Resolved: resolved}, nil
+}
+
+// AddRelatedIssue relates two different issues, which must both be in the request's namespace
+func (s *IssueServer) AddRelatedIssue(ctx context.Context, req *kitev1.AddRelatedIssueRequest) (*kitev1.AddRelatedIssueResponse, error) {
+	if req.GetId() == req.GetRelatedId() {
+		return nil, status.Error(codes.InvalidArgument, "an issue can't be related to itself")
+	}
+	for _, id := range []string{req.GetId(), req.GetRelatedId()} {
+		if _, err := s.findIssue(ctx, req.GetNamespace(), id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.issueService.AddRelatedIssue(ctx, req.GetId(), req.GetRelatedId()); err != nil {
+		return nil, s.writeError(err, req.GetId(), "failed to create issue relationship")
+	}
+	return &kitev1.AddRelatedIssueResponse{}, nil
+}
+
+// RemoveRelatedIssue removes the relationship between two issues, which must both be in the request's namespace
+func (s *IssueServer) RemoveRelatedIssue(ctx context.Context, req *kitev1.RemoveRelatedIssueRequest) (*kitev1.RemoveRelatedIssueResponse, error) {
+	for _, id := range []string{req.GetId(), req.GetRelatedId()} {
+		if _, err := s.findIssue(ctx, req.GetNamespace(), id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.issueService.RemoveRelatedIssue(ctx, req.GetId(), req.GetRelatedId()); err != nil {
+		return nil, s.writeError(err, req.GetId(), "failed to delete issue relationship")
+	}
+	return &kitev1.RemoveRelatedIssueResponse{}, nil
+}
+
+// Helper function to find an issue, which must be in the namespace the caller was authorized for
+func (s *IssueServer) findIssue(ctx context.Context, namespace, id string) (*models.Issue, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid issue ID %q", id)
+	}
+
+	issue, err := s.issueService.FindIssueByID(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField("issue_id", id).Error("Failed to fetch issue")
+		return nil, status.Error(codes.Internal, "failed to fetch issue")
+	}
+	if issue == nil {
+		return nil, status.Errorf(codes.NotFound, "issue %s not found", id)
+	}
+	if issue.Namespace != namespace {
+		return nil, status.Error(codes.PermissionDenied, "access denied to this namespace")
+	}
+	return issue, nil
+}
+
+// Helper function to convert an error changing an issue to a status, by the kind of error it is
+func (s *IssueServer) writeError(err error, id string, message string) error {
+	switch {
+	case errors.Is(err, apperrors.ErrPreconditionFailed):
+		return status.Error(codes.FailedPrecondition, "issue has been changed since the version given")
+	case errors.Is(err, apperrors.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, apperrors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, apperrors.ErrConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, apperrors.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	s.logger.WithError(err).WithField("issue_id", id).Error(message)
+	return status.Error(codes.Internal, message)
+}
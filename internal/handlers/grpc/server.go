@@ -0,0 +1,34 @@
+// Package grpc serves the issue API over gRPC, alongside the REST API served by the http package
+package grpc
+
+import (
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/services"
+	kitev1 "github.com/konflux-ci/kite/pkg/api/kite/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer returns a gRPC server with the issue service, health checking and reflection registered.
+// Calls are authorized with the namespace checker, which can be nil when namespace checking is disabled.
+func NewServer(issueService *services.IssueService, namespaceChecker *middleware.NamespaceChecker, logger *logrus.Logger) *grpc.Server {
+	authorizer := &namespaceAuthorizer{checker: namespaceChecker, logger: logger}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLogger(logger), authorizer.unary),
+		grpc.ChainStreamInterceptor(streamLogger(logger), authorizer.stream),
+	)
+
+	kitev1.RegisterIssueServiceServer(server, NewIssueServer(issueService, logger))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(kitev1.IssueService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	// Lets tools like grpcurl list and call the services without the proto files
+	reflection.Register(server)
+
+	return server
+}
@@ -0,0 +1,368 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	kitev1 "github.com/konflux-ci/kite/pkg/api/kite/v1"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	issueID = "0b6f8b4e-3f5e-4a43-9d0e-0c8a3a7c4f1e"
+	// Another issue in team-a, and one in team-b
+	relatedID        = "5c7e2a1d-8f3b-4e6a-9b0c-7d1e2f3a4b5c"
+	otherNamespaceID = "9d1c3a8e-5b7f-4c2d-8e6a-1f0b2c3d4e5f"
+)
+
+// issueRepository serves issues from memory, the rest of the repository isn't used
+type issueRepository struct {
+	repository.IssueRepository
+	issues []models.Issue
+}
+
+func (r *issueRepository) FindAll(_ context.Context, filters repository.IssueQueryFilters) (*repository.IssuePage, error) {
+	page := &repository.IssuePage{}
+	for _, issue := range r.issues {
+		if filters.Namespace == "" || issue.Namespace == filters.Namespace {
+			page.Issues = append(page.Issues, issue)
+		}
+	}
+	page.Total = int64(len(page.Issues))
+	return page, nil
+}
+
+func (r *issueRepository) AddRelatedIssue(context.Context, string, string) error {
+	return nil
+}
+
+func (r *issueRepository) RemoveRelatedIssue(context.Context, string, string) error {
+	return nil
+}
+
+func (r *issueRepository) FindByID(_ context.Context, id string) (*models.Issue, error) {
+	for i := range r.issues {
+		if r.issues[i].ID == id {
+			return &r.issues[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// tokenAuthenticator knows the namespaces each token can read and write
+type tokenAuthenticator struct {
+	readers, writers map[string][]string
+	reviewErr        error
+}
+
+func (a *tokenAuthenticator) AuthenticateToken(_ context.Context, token string) (*middleware.Principal, error) {
+	if _, ok := a.readers[token]; !ok {
+		return nil, middleware.ErrUnauthenticated
+	}
+	return &middleware.Principal{Name: token, AuthMethod: "test", Authorizer: tokenAuthorizer{a, token}}, nil
+}
+
+type tokenAuthorizer struct {
+	authenticator *tokenAuthenticator
+	token         string
+}
+
+func (a tokenAuthorizer) Allowed(_ context.Context, namespace string, operation middleware.Operation) (bool, error) {
+	if a.authenticator.reviewErr != nil {
+		return false, a.authenticator.reviewErr
+	}
+	namespaces := a.authenticator.readers[a.token]
+	if operation == middleware.OperationWrite {
+		namespaces = a.authenticator.writers[a.token]
+	}
+	return slices.Contains(namespaces, namespace), nil
+}
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// Helper function to serve the issues over an in-memory connection, returning a client for it
+func testClient(t *testing.T, authenticator *tokenAuthenticator) kitev1.IssueServiceClient {
+	repo := &issueRepository{issues: []models.Issue{
+		{ID: issueID, Namespace: "team-a", Title: "Build failed"},
+		{ID: relatedID, Namespace: "team-a", Title: "Deploy failed"},
+		{ID: otherNamespaceID, Namespace: "team-b", Title: "Test failed"},
+	}}
+	checker := middleware.NewNamespaceCheckerWith(testLogger(), authenticator)
+	server := NewServer(services.NewIssueService(repo, testLogger()), checker, testLogger())
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return kitev1.NewIssueServiceClient(conn)
+}
+
+func withToken(token string) context.Context {
+	if token == "" {
+		return context.Background()
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServerAuthorizesCalls(t *testing.T) {
+	authenticator := &tokenAuthenticator{
+		readers: map[string][]string{"reader": {"team-a"}, "writer": {"team-a"}},
+		writers: map[string][]string{"writer": {"team-a"}},
+	}
+	client := testClient(t, authenticator)
+
+	tests := []struct {
+		name     string
+		token    string
+		call     func(ctx context.Context) error
+		wantCode codes.Code
+	}{
+		{
+			name:  "reader can get an issue",
+			token: "reader",
+			call: func(ctx context.Context) error {
+				_, err := client.GetIssue(ctx, &kitev1.GetIssueRequest{Namespace: "team-a", Id: issueID})
+				return err
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:  "reader can't resolve an issue",
+			token: "reader",
+			call: func(ctx context.Context) error {
+				_, err := client.ResolveIssue(ctx, &kitev1.ResolveIssueRequest{Namespace: "team-a", Id: issueID})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:  "other namespace is denied",
+			token: "writer",
+			call: func(ctx context.Context) error {
+				_, err := client.GetIssue(ctx, &kitev1.GetIssueRequest{Namespace: "team-b", Id: issueID})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "no token",
+			call: func(ctx context.Context) error {
+				_, err := client.GetIssue(ctx, &kitev1.GetIssueRequest{Namespace: "team-a", Id: issueID})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:  "invalid token",
+			token: "forged",
+			call: func(ctx context.Context) error {
+				_, err := client.GetIssue(ctx, &kitev1.GetIssueRequest{Namespace: "team-a", Id: issueID})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:  "missing namespace",
+			token: "reader",
+			call: func(ctx context.Context) error {
+				_, err := client.GetIssue(ctx, &kitev1.GetIssueRequest{Id: issueID})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:  "stream of another namespace is denied",
+			token: "reader",
+			call: func(ctx context.Context) error {
+				stream, err := client.ListIssues(ctx, &kitev1.ListIssuesRequest{Namespace: "team-b"})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "stream without a token",
+			call: func(ctx context.Context) error {
+				stream, err := client.ListIssues(ctx, &kitev1.ListIssuesRequest{Namespace: "team-a"})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(withToken(tt.token))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %s, want %s: %v", code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestServerRelatesIssuesOfTheNamespace(t *testing.T) {
+	client := testClient(t, &tokenAuthenticator{
+		readers: map[string][]string{"writer": {"team-a"}},
+		writers: map[string][]string{"writer": {"team-a"}},
+	})
+	add := func(id, relatedID string) error {
+		_, err := client.AddRelatedIssue(withToken("writer"), &kitev1.AddRelatedIssueRequest{Namespace: "team-a", Id: id, RelatedId: relatedID})
+		return err
+	}
+	remove := func(id, relatedID string) error {
+		_, err := client.RemoveRelatedIssue(withToken("writer"), &kitev1.RemoveRelatedIssueRequest{Namespace: "team-a", Id: id, RelatedId: relatedID})
+		return err
+	}
+
+	tests := []struct {
+		name     string
+		call     func(id, relatedID string) error
+		id       string
+		related  string
+		wantCode codes.Code
+	}{
+		{name: "relate issues", call: add, id: issueID, related: relatedID, wantCode: codes.OK},
+		{name: "relate an issue to itself", call: add, id: issueID, related: issueID, wantCode: codes.InvalidArgument},
+		{name: "relate to another namespace", call: add, id: issueID, related: otherNamespaceID, wantCode: codes.PermissionDenied},
+		{name: "relate to a missing issue", call: add, id: issueID, related: "00000000-0000-0000-0000-000000000000", wantCode: codes.NotFound},
+		{name: "unrelate issues", call: remove, id: issueID, related: relatedID, wantCode: codes.OK},
+		{name: "unrelate from another namespace", call: remove, id: issueID, related: otherNamespaceID, wantCode: codes.PermissionDenied},
+		{name: "unrelate an invalid ID", call: remove, id: issueID, related: "not-a-uuid", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(tt.id, tt.related)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %s, want %s: %v", code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestServerHidesReviewFailures(t *testing.T) {
+	authenticator := &tokenAuthenticator{
+		readers:   map[string][]string{"reader": {"team-a"}},
+		reviewErr: errors.New("apiserver unavailable"),
+	}
+	client := testClient(t, authenticator)
+
+	_, err := client.GetIssue(withToken("reader"), &kitev1.GetIssueRequest{Namespace: "team-a", Id: issueID})
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Errorf("code = %s, want %s: %v", code, codes.PermissionDenied, err)
+	}
+	if msg := status.Convert(err).Message(); msg != "access denied to this namespace" {
+		t.Errorf("message = %q, the review error leaked to the caller", msg)
+	}
+}
+
+func TestServerStreamsIssuesOfTheNamespace(t *testing.T) {
+	client := testClient(t, &tokenAuthenticator{readers: map[string][]string{"reader": {"team-a"}}})
+
+	stream, err := client.ListIssues(withToken("reader"), &kitev1.ListIssuesRequest{Namespace: "team-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		ids = append(ids, resp.GetIssue().GetId())
+	}
+	if want := []string{issueID, relatedID}; !slices.Equal(ids, want) {
+		t.Errorf("streamed %v, want %v", ids, want)
+	}
+}
+
+// recordedStream is a server stream receiving the messages it was given
+type recordedStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*kitev1.ListIssuesRequest
+}
+
+func (s *recordedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *recordedStream) RecvMsg(m any) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(*kitev1.ListIssuesRequest), s.messages[0])
+	s.messages = s.messages[1:]
+	return nil
+}
+
+func TestStreamInterceptorChecksEachMessage(t *testing.T) {
+	authenticator := &tokenAuthenticator{readers: map[string][]string{"reader": {"team-a"}}}
+	authorizer := &namespaceAuthorizer{checker: middleware.NewNamespaceCheckerWith(testLogger(), authenticator), logger: testLogger()}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer reader"))
+	stream := &recordedStream{ctx: ctx, messages: []*kitev1.ListIssuesRequest{{Namespace: "team-a"}, {Namespace: "team-b"}, {Namespace: "team-a"}}}
+	info := &grpc.StreamServerInfo{FullMethod: kitev1.IssueService_ListIssues_FullMethodName}
+
+	var received []string
+	var errs []codes.Code
+	err := authorizer.stream(nil, stream, info, func(_ any, ss grpc.ServerStream) error {
+		for {
+			var req kitev1.ListIssuesRequest
+			err := ss.RecvMsg(&req)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				errs = append(errs, status.Code(err))
+				continue
+			}
+			received = append(received, req.GetNamespace())
+		}
+	})
+	if err != nil {
+		t.Fatalf("stream() error = %v", err)
+	}
+	if want := []string{"team-a", "team-a"}; !slices.Equal(received, want) {
+		t.Errorf("handler received %v, want %v", received, want)
+	}
+	if want := []codes.Code{codes.PermissionDenied}; !slices.Equal(errs, want) {
+		t.Errorf("handler got errors %v, want %v", errs, want)
+	}
+}
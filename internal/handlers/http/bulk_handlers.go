@@ -0,0 +1,126 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/sirupsen/logrus"
+)
+
+type BulkHandler struct {
+	issueService     *services.IssueService
+	namespaceChecker *middleware.NamespaceChecker
+	logger           *logrus.Logger
+}
+
+// NewBulkHandler returns a new handler for bulk issue actions.
+// Access to each issue's namespace is checked with namespaceChecker, which may be nil to allow every namespace.
+func NewBulkHandler(issueService *services.IssueService, namespaceChecker *middleware.NamespaceChecker, logger *logrus.Logger) *BulkHandler {
+	return &BulkHandler{
+		issueService:     issueService,
+		namespaceChecker: namespaceChecker,
+		logger:           logger,
+	}
+}
+
+// BulkUpdateIssues handles POST /issues/bulk
+//
+// Resolves, changes the severity of, assigns, labels or deletes the issues listed by ID,
+// or those matching a q filter in a namespace. Issues in namespaces the caller can't access are reported as not found.
+// With preview=true the matching issues are only counted and listed.
+func (h *BulkHandler) BulkUpdateIssues(c *gin.Context) {
+	var req dto.BulkIssueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	preview := false
+	if value := c.Query("preview"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "preview must be true or false"))
+			return
+		}
+		preview = parsed
+	}
+
+	bulkReq := services.BulkRequest{
+		IDs: req.IDs,
+		Action: repository.BulkAction{
+			Type:     repository.BulkActionType(req.Action),
+			Severity: req.Severity,
+			Label:    strings.TrimSpace(req.Label),
+		},
+		Preview: preview,
+	}
+	if req.Assignee != nil && *req.Assignee != "" {
+		bulkReq.Action.Assignee = req.Assignee
+	}
+	if err := services.ValidateBulkAction(bulkReq.Action); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if (len(req.IDs) > 0) == (req.Filter != "") {
+		c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "either ids or filter is required"))
+		return
+	}
+	for i, id := range req.IDs {
+		if _, err := uuid.Parse(id); err != nil {
+			c.Error(&apperrors.ValidationError{Fields: []dto.FieldError{{Field: fmt.Sprintf("ids[%d]", i), Message: "invalid ID " + id}}})
+			return
+		}
+	}
+
+	// Previews only read the issues they list
+	operation := middleware.OperationWrite
+	if preview {
+		operation = middleware.OperationRead
+	}
+	checkAccess := func(namespace string) error {
+		return h.namespaceChecker.CheckAccess(c.Request.Context(), namespace, operation)
+	}
+
+	// Filters only match issues in a single namespace, so they can't reveal issues elsewhere
+	if req.Filter != "" {
+		if req.Namespace == "" {
+			c.Error(&apperrors.ValidationError{Fields: []dto.FieldError{{Field: "namespace", Message: "is required with filter"}}})
+			return
+		}
+		if err := checkAccess(req.Namespace); err != nil {
+			h.logger.WithError(err).WithField("namespace", req.Namespace).Warn("Access Denied")
+			c.Error(err)
+			return
+		}
+
+		parsed, err := repository.ParseIssueQuery(req.Filter)
+		if err != nil {
+			c.Error(invalidFilter("invalid filter", err))
+			return
+		}
+		bulkReq.Filters = repository.IssueQueryFilters{Namespace: req.Namespace, Query: parsed}
+	}
+
+	result, err := h.issueService.BulkUpdateIssues(c.Request.Context(), bulkReq, checkAccess)
+	if err != nil {
+		if errors.Is(err, services.ErrBulkTooLarge) {
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, err.Error()))
+			return
+		}
+		c.Error(fmt.Errorf("failed to apply bulk action %s: %w", req.Action, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/openapi"
+)
+
+//go:embed static/docs.html
+var docsPage []byte
+
+// DocsHandler serves the OpenAPI document and a page for browsing it
+type DocsHandler struct {
+	spec []byte
+}
+
+// NewDocsHandler returns a new handler for the API documentation, which is served once SetDocument is called
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// SetDocument sets the document served. The document describes the routes on the router,
+// including the documentation routes, so it can only be built after they're registered.
+func (h *DocsHandler) SetDocument(doc *openapi.Document) error {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	h.spec = spec
+	return nil
+}
+
+// GetOpenAPI handles GET /openapi.json
+func (h *DocsHandler) GetOpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// GetDocs handles GET /docs, a Redoc page rendering the OpenAPI document
+func (h *DocsHandler) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Rows written between flushes, the write deadline is extended on each flush
+const exportFlushInterval = 100
+
+// Columns of CSV exports, scope and links are flattened into their own columns
+var exportCSVHeader = []string{
+	"id", "title", "description", "severity", "issueType", "state", "detectedAt", "resolvedAt", "namespace", "assignee", "labels",
+	"scopeResourceType", "scopeResourceName", "scopeResourceNamespace", "linkTitles", "linkUrls",
+	"createdAt", "updatedAt",
+}
+
+type ExportHandler struct {
+	issueService *services.IssueService
+	writeTimeout time.Duration
+	logger       *logrus.Logger
+}
+
+// NewExportHandler returns a new handler for exporting issues.
+// writeTimeout is how long each chunk of the export has to be written in.
+func NewExportHandler(issueService *services.IssueService, writeTimeout time.Duration, logger *logrus.Logger) *ExportHandler {
+	return &ExportHandler{
+		issueService: issueService,
+		writeTimeout: writeTimeout,
+		logger:       logger,
+	}
+}
+
+// issueWriter writes issues in an export format
+type issueWriter interface {
+	Write(issue *models.Issue) error
+	Flush() error
+}
+
+// ExportIssues handles GET /issues/export
+//
+// Streams every issue matching the same filters as GET /issues as CSV (the default) or NDJSON.
+// Exports aren't paginated, so the server's write timeout is extended while rows are still being sent.
+func (h *ExportHandler) ExportIssues(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "ndjson" {
+		c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "format must be csv or ndjson"))
+		return
+	}
+
+	filters, err := parseIssueQueryFilters(issueFilterValues(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var writer issueWriter
+	filename := fmt.Sprintf("issues-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer = newCSVIssueWriter(c.Writer)
+	} else {
+		c.Header("Content-Type", "application/x-ndjson")
+		writer = &ndjsonIssueWriter{encoder: json.NewEncoder(c.Writer), flusher: c.Writer}
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("X-Accel-Buffering", "no")
+
+	controller := http.NewResponseController(c.Writer)
+	h.extendWriteDeadline(controller)
+	c.Status(http.StatusOK)
+
+	count := 0
+	err = h.issueService.ExportIssues(c.Request.Context(), filters, func(issue *models.Issue) error {
+		if err := writer.Write(issue); err != nil {
+			return err
+		}
+		if count++; count%exportFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			h.extendWriteDeadline(controller)
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		// Headers are already sent, so the export just ends early
+		h.logger.WithError(err).WithField("exported", count).Error("Failed to export issues")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{"format": format, "exported": count}).Info("Exported issues")
+}
+
+// Helper function to give the next chunk of the export the full write timeout
+func (h *ExportHandler) extendWriteDeadline(controller *http.ResponseController) {
+	if h.writeTimeout <= 0 {
+		return
+	}
+	if err := controller.SetWriteDeadline(time.Now().Add(h.writeTimeout)); err != nil {
+		h.logger.WithError(err).Debug("Failed to extend write deadline for export")
+	}
+}
+
+type csvIssueWriter struct {
+	writer      *csv.Writer
+	flusher     http.Flusher
+	wroteHeader bool
+}
+
+func newCSVIssueWriter(w gin.ResponseWriter) *csvIssueWriter {
+	return &csvIssueWriter{writer: csv.NewWriter(w), flusher: w}
+}
+
+func (w *csvIssueWriter) Write(issue *models.Issue) error {
+	if !w.wroteHeader {
+		if err := w.writer.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+
+	titles := make([]string, len(issue.Links))
+	urls := make([]string, len(issue.Links))
+	for idx, link := range issue.Links {
+		titles[idx] = link.Title
+		urls[idx] = link.URL
+	}
+	resolvedAt := ""
+	if issue.ResolvedAt != nil {
+		resolvedAt = issue.ResolvedAt.UTC().Format(time.RFC3339)
+	}
+	assignee := ""
+	if issue.Assignee != nil {
+		assignee = *issue.Assignee
+	}
+
+	record := []string{
+		issue.ID, issue.Title, issue.Description, string(issue.Severity), string(issue.IssueType), string(issue.State),
+		issue.DetectedAt.UTC().Format(time.RFC3339), resolvedAt, issue.Namespace, assignee, strings.Join(issue.Labels, "\n"),
+		issue.Scope.ResourceType, issue.Scope.ResourceName, issue.Scope.ResourceNamespace,
+		strings.Join(titles, "\n"), strings.Join(urls, "\n"),
+		issue.CreatedAt.UTC().Format(time.RFC3339), issue.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	for idx, value := range record {
+		record[idx] = escapeCSVFormula(value)
+	}
+	return w.writer.Write(record)
+}
+
+// Flush writes buffered rows, an empty export still gets a header
+func (w *csvIssueWriter) Flush() error {
+	if !w.wroteHeader {
+		if err := w.writer.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return err
+	}
+	w.flusher.Flush()
+	return nil
+}
+
+type ndjsonIssueWriter struct {
+	encoder *json.Encoder
+	flusher http.Flusher
+}
+
+func (w *ndjsonIssueWriter) Write(issue *models.Issue) error {
+	return w.encoder.Encode(issue)
+}
+
+func (w *ndjsonIssueWriter) Flush() error {
+	w.flusher.Flush()
+	return nil
+}
+
+// Helper function to stop spreadsheets from running cell values as formulas
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type GraphQLHandler struct {
+	server http.Handler
+	logger *logrus.Logger
+}
+
+// NewGraphQLHandler returns a new handler serving the GraphQL server passed
+func NewGraphQLHandler(server http.Handler, logger *logrus.Logger) *GraphQLHandler {
+	return &GraphQLHandler{
+		server: server,
+		logger: logger,
+	}
+}
+
+// ServeGraphQL handles GET and POST /graphql
+//
+// Queries are sent as GET query params or POST JSON bodies. Subscriptions are served over websockets,
+// or as Server-Sent Events to POST requests accepting text/event-stream.
+func (h *GraphQLHandler) ServeGraphQL(c *gin.Context) {
+	// Subscriptions outlive the server's write timeout
+	if c.IsWebsocket() || strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			h.logger.WithError(err).Debug("Failed to clear write deadline for subscription")
+		}
+	}
+	h.server.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/sirupsen/logrus"
+)
+
+// Largest import body accepted
+const maxImportBytes = 64 << 20
+
+type ImportHandler struct {
+	issueService *services.IssueService
+	logger       *logrus.Logger
+}
+
+func NewImportHandler(issueService *services.IssueService, logger *logrus.Logger) *ImportHandler {
+	return &ImportHandler{
+		issueService: issueService,
+		logger:       logger,
+	}
+}
+
+// ImportIssues handles POST /admin/import
+//
+// Creates issues from an NDJSON or CSV body, such as one from GET /issues/export, in the namespace given.
+// With dryRun=true rows are only validated and checked for duplicates. Otherwise nothing is imported
+// unless every row is valid, and the report of each row is returned either way.
+func (h *ImportHandler) ImportIssues(c *gin.Context) {
+	namespace := c.Query("namespace")
+	if namespace == "" {
+		c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "missing namespace"))
+		return
+	}
+
+	format, ok := importFormat(c)
+	if !ok {
+		c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "format must be csv or ndjson"))
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dryRun"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "dryRun must be true or false"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	report, err := h.issueService.ImportIssues(c.Request.Context(), body, services.ImportOptions{
+		Format:    format,
+		Namespace: namespace,
+		DryRun:    dryRun,
+	})
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr), errors.Is(err, services.ErrImportTooLarge):
+		c.Error(apperrors.NewProblem(dto.CodePayloadTooLarge, err.Error()))
+		return
+	case errors.Is(err, services.ErrMalformedImport):
+		c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, err.Error()))
+		return
+	case errors.Is(err, services.ErrInvalidImport):
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	case err != nil:
+		c.Error(fmt.Errorf("failed to import issues into %s: %w", namespace, err))
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, report)
+}
+
+// Helper function to get the import format from the format parameter, falling back to the content type
+func importFormat(c *gin.Context) (services.ImportFormat, bool) {
+	switch format := c.Query("format"); format {
+	case "csv":
+		return services.ImportFormatCSV, true
+	case "ndjson":
+		return services.ImportFormatNDJSON, true
+	case "":
+	default:
+		return "", false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return services.ImportFormatCSV, true
+	case "application/x-ndjson", "application/jsonl", "application/json":
+		return services.ImportFormatNDJSON, true
+	}
+	return "", false
+}
@@ -1,21 +1,40 @@
 package http
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
-	"slices"
-
 	"github.com/gin-gonic/gin"
-	"github.com/konflux-ci/kite/internal/handlers/dto"
-	"github.com/konflux-ci/kite/internal/models"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/query"
 	"github.com/konflux-ci/kite/internal/repository"
 	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
 	"github.com/sirupsen/logrus"
 )
 
+// Patch types accepted by PATCH /issues/:id, for the Accept-Patch header
+const acceptPatch = string(services.PatchTypeMerge) + ", " + string(services.PatchTypeJSON)
+
+// Largest patch body accepted
+const maxPatchBytes = 1 << 20
+
+var (
+	// errNamespaceDenied is returned for issues outside the namespace of the request
+	errNamespaceDenied = apperrors.New(apperrors.ErrForbidden, "access denied to this namespace")
+	// errMissingNamespace is returned on routes without a namespace checked by the namespace middleware
+	errMissingNamespace = apperrors.NewProblem(dto.CodeInvalidRequest, "missing namespace")
+)
+
 type IssueHandler struct {
 	issueService *services.IssueService
 	logger       *logrus.Logger
@@ -30,75 +49,72 @@ func NewIssueHandler(issueService *services.IssueService, logger *logrus.Logger)
 
 // GetIssues handles GET /issues
 func (h *IssueHandler) GetIssues(c *gin.Context) {
-	// Esxtract query params
-	filters := repository.IssueQueryFilters{
-		Namespace:    c.Query("namespace"),
-		ResourceType: c.Query("resourceType"),
-		ResourceName: c.Query("resourceName"),
-		Search:       c.Query("search"),
+	filters, err := parseIssueQueryFilters(issueFilterValues(c))
+	if err == nil {
+		err = parsePagination(c, &filters)
 	}
-
-	// Parse optional enum params
-	if severity := c.Query("severity"); severity != "" {
-		// Convert to custom type, then assign
-		sev := models.Severity(severity)
-		filters.Severity = &sev
+	if err != nil {
+		c.Error(err)
+		return
 	}
-	if issueType := c.Query("issueType"); issueType != "" {
-		it := models.IssueType(issueType)
-		filters.IssueType = &it
+
+	result, err := h.issueService.FindIssues(c.Request.Context(), filters)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch issues: %w", err))
+		return
 	}
-	if state := c.Query("state"); state != "" {
-		st := models.IssueState(state)
-		filters.State = &st
+
+	respondIssues(c, result, filters)
+}
+
+// GetIssueStats handles GET /issues/stats
+//
+// Issues matching the same filters as GET /issues are counted, grouped by the
+// comma separated fields in groupBy. groupBy=day buckets issues by the UTC day of
+// dateField, which is detectedAt by default.
+func (h *IssueHandler) GetIssueStats(c *gin.Context) {
+	filters, err := parseIssueQueryFilters(issueFilterValues(c))
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	// Parse pagination parameters
-	if limit := c.Query("limit"); limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
-			filters.Limit = l
+	var options repository.IssueStatsOptions
+	if groupBy := c.Query("groupBy"); groupBy != "" {
+		if options.GroupBy, err = repository.ParseGroupBy(groupBy); err != nil {
+			c.Error(invalidFilter("invalid groupBy", err))
+			return
 		}
 	}
-	if offset := c.Query("offset"); offset != "" {
-		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
-			filters.Offset = o
+	if dateField := c.Query("dateField"); dateField != "" {
+		if options.DateField, err = repository.ParseDateField(dateField); err != nil {
+			c.Error(invalidFilter("invalid dateField", err))
+			return
 		}
 	}
 
-	// Default limit
-	if filters.Limit == 0 {
-		filters.Limit = 50
-	}
-
-	result, err := h.issueService.FindIssues(c.Request.Context(), filters)
+	stats, err := h.issueService.GetIssueStats(c.Request.Context(), filters, options)
 	if err != nil {
-		h.logger.WithError(err).Error("failed to fetch issues")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch issues"})
+		c.Error(fmt.Errorf("failed to fetch issue stats: %w", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, stats)
 }
 
 // GetIssue handles GET /issues/:id
 func (h *IssueHandler) GetIssue(c *gin.Context) {
-	id := c.Param("id")
-	namespace := c.Query("namespace")
-
-	issue, err := h.issueService.FindIssueByID(c.Request.Context(), id)
+	issue, err := findIssue(c, h.issueService)
 	if err != nil {
-		h.logger.WithError(err).WithField("issue_id", id).Error("Failed to fetch issue")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch issue"})
-		return
-	}
-
-	if issue == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		c.Error(err)
 		return
 	}
 
-	if namespace != "" && issue.Namespace != namespace {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to this namespace"})
+	// Clients with the current version don't need it sent again
+	etag := issueETag(issue)
+	c.Header("ETag", etag)
+	if header := c.GetHeader("If-None-Match"); header != "" && matchesETag(header, etag, true) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
@@ -106,118 +122,149 @@ func (h *IssueHandler) GetIssue(c *gin.Context) {
 }
 
 // CreateIssue handles POST /issues
+//
+// On v2 routes the namespace in the body can be left out. When it's set it has to match the namespace
+// the caller's access was checked for, which is the one in the path or query when they have one.
 func (h *IssueHandler) CreateIssue(c *gin.Context) {
-	var req dto.CreateIssueRequest
+	req := dto.CreateIssueRequest{Namespace: c.Param("namespace")}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		c.Error(invalidBody(err))
+		return
+	}
+	if err := checkBodyNamespace(c, req.Namespace); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.validateCreateIssueRequest(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+	if err := services.ValidateCreateIssueRequest(req); err != nil {
+		c.Error(err)
 		return
 	}
 
 	issue, err := h.issueService.CreateIssue(c.Request.Context(), req)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to create issue")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create issue"})
+		c.Error(fmt.Errorf("failed to create issue: %w", err))
 		return
 	}
 
+	c.Header("ETag", issueETag(issue))
 	c.JSON(http.StatusCreated, issue)
 }
 
 // UpdateIssue handles PUT /issues/:id
+//
+// Replaces every editable field of the issue, fields left out are cleared.
 func (h *IssueHandler) UpdateIssue(c *gin.Context) {
-	id := c.Param("id")
-	namespace := c.Query("namespace")
-
-	var req dto.UpdateIssueRequest
+	var req dto.ReplaceIssueRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		c.Error(invalidBody(err))
 		return
 	}
 
-	// Check if issue exists and verify namespace exists
-	existingIssue, err := h.issueService.FindIssueByID(c.Request.Context(), id)
+	// Check the issue exists and is in the namespace
+	existingIssue, err := findIssue(c, h.issueService)
 	if err != nil {
-		h.logger.WithError(err).WithField("issue_id", id).Error("Failed to find issue for update")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update issue"})
-		return
-	}
-	if existingIssue == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+		c.Error(err)
 		return
 	}
 
-	// Verify namespace access
-	if namespace != "" && existingIssue.Namespace != namespace {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to this namespace"})
+	version, err := checkIfMatch(c, existingIssue)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	updatedIssue, err := h.issueService.UpdateIssue(c.Request.Context(), id, req)
+	updatedIssue, err := h.issueService.ReplaceIssue(c.Request.Context(), existingIssue, req, version)
 	if err != nil {
-		h.logger.WithError(err).WithField("issue_id", id).Error("Failed to update issue")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update issue"})
+		c.Error(fmt.Errorf("failed to update issue: %w", err))
 		return
 	}
 
+	c.Header("ETag", issueETag(updatedIssue))
 	c.JSON(http.StatusOK, updatedIssue)
 }
 
-// DeleteIssue handles DELETE /issues/:id
-func (h *IssueHandler) DeleteIssue(c *gin.Context) {
-	id := c.Param("id")
-	namespace := c.Query("namespace")
+// PatchIssue handles PATCH /issues/:id
+//
+// Accepts a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json)
+// of the fields PUT replaces. JSON Patch paths such as /labels/- or /links/0 change single labels and links.
+func (h *IssueHandler) PatchIssue(c *gin.Context) {
+	patchType := services.PatchType(c.ContentType())
+	if patchType != services.PatchTypeMerge && patchType != services.PatchTypeJSON {
+		c.Header("Accept-Patch", acceptPatch)
+		c.Error(apperrors.NewProblem(dto.CodeUnsupportedMediaType, "expected "+acceptPatch))
+		return
+	}
 
-	existingIssue, err := h.issueService.FindIssueByID(c.Request.Context(), id)
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchBytes))
 	if err != nil {
-		h.logger.WithError(err).WithField("issue_id", id).Error("Failed to find issue for deletion")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete issue"})
+		c.Error(invalidBody(err))
 		return
 	}
-	if existingIssue == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+
+	existingIssue, err := findIssue(c, h.issueService)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// Namespace access check
-	if namespace != "" && existingIssue.Namespace != namespace {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to this namespace"})
+	if _, err := checkIfMatch(c, existingIssue); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.issueService.DeleteIssue(c.Request.Context(), id); err != nil {
-		h.logger.WithError(err).WithField("issue_id", id).Error("Failed to delete issue")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete issue"})
+	// Patches always apply to the version they were loaded at
+	updatedIssue, err := h.issueService.PatchIssue(c.Request.Context(), existingIssue, patchType, patch)
+	switch {
+	case errors.Is(err, repository.ErrVersionMismatch) && c.GetHeader("If-Match") == "":
+		// Without If-Match the client didn't ask for a version, so the patch can be retried as is
+		c.Error(apperrors.NewProblem(dto.CodeConflict, "issue was changed while being patched, retry the patch"))
+		return
+	case errors.Is(err, services.ErrInvalidPatch):
+		c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, err.Error()))
+		return
+	case err != nil:
+		c.Error(fmt.Errorf("failed to patch issue: %w", err))
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.Header("ETag", issueETag(updatedIssue))
+	c.JSON(http.StatusOK, updatedIssue)
 }
 
-// ResolveIssue handles POST /issues/:id/resolve
-func (h *IssueHandler) ResolveIssue(c *gin.Context) {
-	id := c.Param("id")
-	namespace := c.Query("namespace")
+// DeleteIssue handles DELETE /issues/:id
+func (h *IssueHandler) DeleteIssue(c *gin.Context) {
+	existingIssue, err := findIssue(c, h.issueService)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	existingIssue, err := h.issueService.FindIssueByID(c.Request.Context(), id)
+	version, err := checkIfMatch(c, existingIssue)
 	if err != nil {
-		h.logger.WithError(err).WithField("issue_id", id).Error("failed to find issue for resolution")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve issue"})
+		c.Error(err)
+		return
+	}
+
+	if err := h.issueService.DeleteIssue(c.Request.Context(), existingIssue.ID, version); err != nil {
+		c.Error(fmt.Errorf("failed to delete issue: %w", err))
 		return
 	}
 
-	if existingIssue == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Issue not found"})
+	c.Status(http.StatusNoContent)
+}
+
+// ResolveIssue handles POST /issues/:id/resolve
+func (h *IssueHandler) ResolveIssue(c *gin.Context) {
+	existingIssue, err := findIssue(c, h.issueService)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// Namespace access check
-	if namespace != "" && existingIssue.Namespace != namespace {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to this namespace"})
+	version, err := checkIfMatch(c, existingIssue)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -228,13 +275,13 @@ func (h *IssueHandler) ResolveIssue(c *gin.Context) {
 		ResolvedAt: &now,
 	}
 
-	updatedIssue, err := h.issueService.UpdateIssue(c.Request.Context(), id, req)
+	updatedIssue, err := h.issueService.UpdateIssue(c.Request.Context(), existingIssue.ID, req, version)
 	if err != nil {
-		h.logger.WithError(err).WithField("issue_id", id).Error("Failed to mark issue resolved")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve issue"})
+		c.Error(fmt.Errorf("failed to resolve issue: %w", err))
 		return
 	}
 
+	c.Header("ETag", issueETag(updatedIssue))
 	c.JSON(http.StatusOK, updatedIssue)
 }
 
@@ -242,25 +289,19 @@ func (h *IssueHandler) ResolveIssue(c *gin.Context) {
 func (h *IssueHandler) AddRelatedIssue(c *gin.Context) {
 	id := c.Param("id")
 
-	var req struct {
-		RelatedID string `json:"relatedId" binding:"required"`
-	}
+	var req dto.RelatedIssueRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing relatdId field"})
+		c.Error(invalidBody(err))
+		return
+	}
+
+	if err := checkRelatedIssues(c, h.issueService, id, req.RelatedID); err != nil {
+		c.Error(err)
 		return
 	}
 
 	if err := h.issueService.AddRelatedIssue(c.Request.Context(), id, req.RelatedID); err != nil {
-		if err.Error() == "one or both issues not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		if err.Error() == "relationship already exists" {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		h.logger.WithError(err).Error("Failed to add related issue")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create issue relationship"})
+		c.Error(fmt.Errorf("failed to create issue relationship: %w", err))
 		return
 	}
 
@@ -272,48 +313,285 @@ func (h *IssueHandler) RemoveRelatedIssue(c *gin.Context) {
 	id := c.Param("id")
 	relatedID := c.Param("relatedId")
 
+	if err := checkRelatedIssues(c, h.issueService, id, relatedID); err != nil {
+		c.Error(err)
+		return
+	}
+
 	if err := h.issueService.RemoveRelatedIssue(c.Request.Context(), id, relatedID); err != nil {
-		if err.Error() == "relationship not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		h.logger.WithError(err).Error("Failed to remove related issue")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete issue relationship"})
+		c.Error(fmt.Errorf("failed to delete issue relationship: %w", err))
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
-// Helper function for validation issue creation
-func (h *IssueHandler) validateCreateIssueRequest(req dto.CreateIssueRequest) error {
-	// Validate severity
-	validSeverities := []models.Severity{
-		models.SeverityInfo, models.SeverityMinor,
-		models.SeverityMajor, models.SeverityCritical,
+// Helper function to find the issue in the path, which has to be in the namespace the caller's access was checked for.
+// Issues in other namespaces don't exist under v2 paths, while v1 denies access to them.
+func findIssue(c *gin.Context, issueService *services.IssueService) (*models.Issue, error) {
+	namespace := middleware.GetNamespace(c)
+	if namespace == "" {
+		return nil, errMissingNamespace
+	}
+	issue, err := issueService.FindIssueByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue: %w", err)
+	}
+	if issue == nil || (c.Param("namespace") != "" && issue.Namespace != c.Param("namespace")) {
+		return nil, repository.ErrIssueNotFound
+	}
+	if issue.Namespace != namespace {
+		return nil, errNamespaceDenied
+	}
+	return issue, nil
+}
+
+// Helper function to check both issues of a relationship are in the namespace the caller's access was checked for
+func checkRelatedIssues(c *gin.Context, issueService *services.IssueService, ids ...string) error {
+	namespace := middleware.GetNamespace(c)
+	if namespace == "" {
+		return errMissingNamespace
+	}
+	for _, id := range ids {
+		issue, err := issueService.FindIssueByID(c.Request.Context(), id)
+		if err != nil {
+			return fmt.Errorf("failed to fetch issue: %w", err)
+		}
+		if issue == nil || issue.Namespace != namespace {
+			return repository.ErrRelatedIssueNotFound
+		}
+	}
+	return nil
+}
+
+// Helper function to check the namespace of a request body is the one the caller's access was checked for.
+// The namespace middleware only reads the body when the path and query have no namespace.
+func checkBodyNamespace(c *gin.Context, namespace string) error {
+	checked := middleware.GetNamespace(c)
+	if checked == "" {
+		return errMissingNamespace
+	}
+	if namespace != checked {
+		return &apperrors.ValidationError{Fields: []dto.FieldError{{Field: "namespace", Message: "doesn't match the namespace of the request"}}}
+	}
+	return nil
+}
+
+// Helper function to get the query params of a request for parseIssueQueryFilters, with the namespace of v2 paths
+func issueFilterValues(c *gin.Context) url.Values {
+	values := c.Request.URL.Query()
+	if namespace := c.Param("namespace"); namespace != "" {
+		values.Set("namespace", namespace)
+	}
+	return values
+}
+
+// Helper function to convert an error binding a request body, invalid fields are reported one by one
+func invalidBody(err error) error {
+	if converted := services.NewValidationError(err); errors.Is(converted, apperrors.ErrValidation) {
+		return converted
+	}
+	return apperrors.NewProblem(dto.CodeInvalidRequest, "invalid request body: "+err.Error())
+}
+
+// Helper function to extract issue filters from query params, in the format of GET /issues
+func parseIssueQueryFilters(values url.Values) (repository.IssueQueryFilters, error) {
+	// Esxtract query params
+	filters := repository.IssueQueryFilters{
+		Namespace:    values.Get("namespace"),
+		ResourceType: values.Get("resourceType"),
+		ResourceName: values.Get("resourceName"),
+		Search:       values.Get("search"),
+	}
+
+	// Parse optional enum params
+	if severity := values.Get("severity"); severity != "" {
+		// Convert to custom type, then assign
+		sev := models.Severity(severity)
+		filters.Severity = &sev
+	}
+	if issueType := values.Get("issueType"); issueType != "" {
+		it := models.IssueType(issueType)
+		filters.IssueType = &it
+	}
+	if state := values.Get("state"); state != "" {
+		st := models.IssueState(state)
+		filters.State = &st
+	}
+
+	// Parse the structured filter, e.g. severity>=major AND detectedAt>now-7d
+	if q := values.Get("q"); q != "" {
+		parsed, err := repository.ParseIssueQuery(q)
+		if err != nil {
+			return filters, invalidFilter("invalid query", err)
+		}
+		filters.Query = parsed
 	}
 
-	if !slices.Contains(validSeverities, req.Severity) {
-		return errors.New("invalid severity value")
+	// Parse sorting and projection parameters
+	if sort := values.Get("sort"); sort != "" {
+		fields, err := repository.ParseSort(sort)
+		if err != nil {
+			return filters, invalidFilter("invalid sort", err)
+		}
+		filters.Sort = fields
+	}
+	if fields := values.Get("fields"); fields != "" {
+		parsed, err := repository.ParseFields(fields)
+		if err != nil {
+			return filters, invalidFilter("invalid fields", err)
+		}
+		filters.Fields = parsed
 	}
+	if include, ok := values["include"]; ok && len(include) > 0 {
+		includes, err := repository.ParseIncludes(include[0])
+		if err != nil {
+			return filters, invalidFilter("invalid include", err)
+		}
+		filters.Include = &includes
+	}
+
+	return filters, nil
+}
 
-	// Validate issue type
-	validTypes := []models.IssueType{
-		models.IssueTypeBuild, models.IssueTypeTest,
-		models.IssueTypeRelease, models.IssueTypeDependency,
-		models.IssueTypePipeline,
+// Helper function to apply the pagination query params to the filters
+func parsePagination(c *gin.Context, filters *repository.IssueQueryFilters) error {
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			filters.Limit = l
+		}
 	}
-	if !slices.Contains(validTypes, req.IssueType) {
-		return errors.New("invalid issueType value")
+	if offset := c.Query("offset"); offset != "" {
+		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
+			filters.Offset = o
+		}
 	}
 
-	// validate state if provided
-	if req.State != "" {
-		validStates := []models.IssueState{models.IssueStateActive, models.IssueStateResolved}
-		if !slices.Contains(validStates, req.State) {
-			return errors.New("invalid state value")
+	if cursor := c.Query("cursor"); cursor != "" {
+		decoded, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return invalidFilter("invalid cursor", nil)
 		}
+		filters.Cursor = decoded
+		filters.Offset = 0
 	}
 
+	if filters.Cursor != nil && len(filters.Sort) > 0 {
+		return invalidFilter("cursor pagination is only supported with the default sort", nil)
+	}
+
+	// Default limit
+	if filters.Limit == 0 {
+		filters.Limit = 50
+	}
 	return nil
 }
+
+// Helper function to reject a query param that couldn't be parsed, q filter errors point at the position of the problem
+func invalidFilter(message string, err error) error {
+	invalidErr := apperrors.NewProblem(dto.CodeInvalidRequest, message)
+	var queryErr *query.Error
+	switch {
+	case errors.As(err, &queryErr):
+		invalidErr.Detail = message + ": " + queryErr.Message
+		invalidErr.Position = &queryErr.Position
+	case err != nil:
+		invalidErr.Detail = message + ": " + err.Error()
+	}
+	return invalidErr
+}
+
+// Helper function to respond with a page of issues, only returning what was asked for when the response shape was customized
+func respondIssues(c *gin.Context, result *dto.IssueResponse, filters repository.IssueQueryFilters) {
+	if len(filters.Fields) > 0 || filters.Include != nil {
+		c.JSON(http.StatusOK, projectIssueResponse(result, filters.Fields, filters.Includes()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Helper function to limit each issue in a response to the fields and associations requested
+func projectIssueResponse(result *dto.IssueResponse, fields []string, includes repository.IssueIncludes) *dto.ProjectedIssueResponse {
+	keys := fields
+	if len(keys) == 0 {
+		keys = []string{"id", "title", "description", "severity", "issueType", "state", "detectedAt",
+			"resolvedAt", "namespace", "assignee", "labels", "scopeId", "version", "createdAt", "updatedAt"}
+	}
+	if includes.Scope {
+		keys = append(keys, "scope")
+	}
+	if includes.Links {
+		keys = append(keys, "links")
+	}
+	if includes.Related {
+		keys = append(keys, "relatedFrom", "relatedTo")
+	}
+
+	projected := &dto.ProjectedIssueResponse{
+		Data:       make([]map[string]any, 0, len(result.Data)),
+		Total:      result.Total,
+		Limit:      result.Limit,
+		Offset:     result.Offset,
+		NextCursor: result.NextCursor,
+		PrevCursor: result.PrevCursor,
+	}
+
+	for _, issue := range result.Data {
+		// Round trip through JSON so the keys match the regular response
+		var full map[string]any
+		data, _ := json.Marshal(issue)
+		_ = json.Unmarshal(data, &full)
+
+		item := make(map[string]any, len(keys))
+		for _, key := range keys {
+			item[key] = full[key]
+		}
+		if issue.Highlight != nil {
+			item["highlight"] = full["highlight"]
+		}
+		projected.Data = append(projected.Data, item)
+	}
+	return projected
+}
+
+// Helper function to get the entity tag of an issue, it changes whenever the issue does
+func issueETag(issue *models.Issue) string {
+	return `"` + strconv.FormatInt(issue.Version, 10) + `"`
+}
+
+// Helper function to compare an If-Match or If-None-Match header with an entity tag.
+// If-Match uses strong comparison, so weak tags never match, while If-None-Match uses weak comparison.
+func matchesETag(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to check an If-Match header against the current version of an issue.
+// It returns the version a write has to be made against, 0 when the header isn't set or is *,
+// and repository.ErrVersionMismatch when the precondition failed.
+func checkIfMatch(c *gin.Context, issue *models.Issue) (int64, error) {
+	header := c.GetHeader("If-Match")
+	if header == "" || strings.TrimSpace(header) == "*" {
+		return 0, nil
+	}
+	if !matchesETag(header, issueETag(issue), false) {
+		c.Header("ETag", issueETag(issue))
+		return 0, repository.ErrVersionMismatch
+	}
+	return issue.Version, nil
+}
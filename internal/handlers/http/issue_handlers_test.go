@@ -0,0 +1,256 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const testIssueID = "00000000-0000-0000-0000-000000000001"
+
+// versionedIssues holds a single issue at version 3, recording the versions writes are made against
+type versionedIssues struct {
+	repository.IssueRepository
+	writes []int64
+}
+
+func (r *versionedIssues) FindByID(_ context.Context, id string) (*models.Issue, error) {
+	if id != testIssueID {
+		return nil, nil
+	}
+	return &models.Issue{
+		ID: testIssueID, Title: "Build failed", Description: "The build failed", Namespace: "team-a",
+		Severity: models.SeverityMajor, IssueType: models.IssueTypeBuild, State: models.IssueStateActive,
+		DetectedAt: time.Now().Add(-time.Hour), Version: 3,
+	}, nil
+}
+
+func (r *versionedIssues) Update(ctx context.Context, id string, _ dto.UpdateIssueRequest, version int64) (*models.Issue, error) {
+	return r.written(ctx, id, version)
+}
+
+func (r *versionedIssues) Replace(ctx context.Context, id string, _ dto.ReplaceIssueRequest, version int64) (*models.Issue, error) {
+	return r.written(ctx, id, version)
+}
+
+func (r *versionedIssues) Delete(_ context.Context, _ string, version int64) error {
+	r.writes = append(r.writes, version)
+	return nil
+}
+
+func (r *versionedIssues) written(ctx context.Context, id string, version int64) (*models.Issue, error) {
+	r.writes = append(r.writes, version)
+	issue, _ := r.FindByID(ctx, id)
+	issue.Version++
+	return issue, nil
+}
+
+// Helper function to route the issue handlers, authorized for team-a
+func issueRouter(repo repository.IssueRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	handler := NewIssueHandler(services.NewIssueService(repo, logger), logger)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(logger), func(c *gin.Context) {
+		c.Request = c.Request.WithContext(middleware.WithPrincipal(c.Request.Context(), &middleware.Principal{Name: "alice"}))
+	})
+	group := router.Group("/issues")
+	group.Use((*middleware.NamespaceChecker)(nil).CheckNamespacessAccess())
+	group.GET("/:id", middleware.ValidateID(), handler.GetIssue)
+	group.PUT("/:id", middleware.ValidateID(), handler.UpdateIssue)
+	group.PATCH("/:id", middleware.ValidateID(), handler.PatchIssue)
+	group.DELETE("/:id", middleware.ValidateID(), handler.DeleteIssue)
+	group.POST("/:id/resolve", middleware.ValidateID(), handler.ResolveIssue)
+	group.POST("/:id/related", middleware.ValidateID(), handler.AddRelatedIssue)
+	return router
+}
+
+func TestMatchesETag(t *testing.T) {
+	tests := []struct {
+		header string
+		weak   bool
+		want   bool
+	}{
+		{header: `"3"`, want: true},
+		{header: `"2"`, want: false},
+		{header: `3`, want: false},
+		{header: `"1", "3"`, want: true},
+		{header: `"1","2"`, want: false},
+		{header: `*`, want: true},
+		// Weak tags only match with weak comparison
+		{header: `W/"3"`, want: false},
+		{header: `W/"3"`, weak: true, want: true},
+		{header: `W/"2", "3"`, want: true},
+		{header: `W/"2"`, weak: true, want: false},
+		{header: `w/"3"`, weak: true, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesETag(tt.header, `"3"`, tt.weak); got != tt.want {
+			t.Errorf("matchesETag(%q, weak %v) = %v, want %v", tt.header, tt.weak, got, tt.want)
+		}
+	}
+}
+
+func TestGetIssueETag(t *testing.T) {
+	router := issueRouter(&versionedIssues{})
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "no precondition", wantStatus: http.StatusOK},
+		{name: "current version", ifNoneMatch: `"3"`, wantStatus: http.StatusNotModified},
+		{name: "weak current version", ifNoneMatch: `W/"3"`, wantStatus: http.StatusNotModified},
+		{name: "list with current version", ifNoneMatch: `"1", "3"`, wantStatus: http.StatusNotModified},
+		{name: "any version", ifNoneMatch: `*`, wantStatus: http.StatusNotModified},
+		{name: "old version", ifNoneMatch: `"2"`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/issues/"+testIssueID+"?namespace=team-a", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if etag := rec.Header().Get("ETag"); etag != `"3"` {
+				t.Errorf("ETag = %q, want the issue's version", etag)
+			}
+			if tt.wantStatus == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("not modified response has a body: %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	const replacement = `{"title":"Build failed","description":"The build failed","severity":"major","issueType":"build","state":"ACTIVE"}`
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodPut, path: "/issues/" + testIssueID, body: replacement},
+		{method: http.MethodDelete, path: "/issues/" + testIssueID},
+		{method: http.MethodPost, path: "/issues/" + testIssueID + "/resolve"},
+	}
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantStatus  int
+		wantVersion int64
+	}{
+		{name: "no precondition", wantVersion: 0},
+		{name: "any version", ifMatch: `*`, wantVersion: 0},
+		{name: "current version", ifMatch: `"3"`, wantVersion: 3},
+		{name: "list with current version", ifMatch: `"2", "3"`, wantVersion: 3},
+		{name: "old version", ifMatch: `"2"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "weak current version", ifMatch: `W/"3"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "unquoted version", ifMatch: `3`, wantStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, r := range requests {
+		for _, tt := range tests {
+			t.Run(r.method+" "+tt.name, func(t *testing.T) {
+				repo := &versionedIssues{}
+				router := issueRouter(repo)
+
+				req := httptest.NewRequest(r.method, r.path+"?namespace=team-a", strings.NewReader(r.body))
+				req.Header.Set("Content-Type", "application/json")
+				if tt.ifMatch != "" {
+					req.Header.Set("If-Match", tt.ifMatch)
+				}
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if tt.wantStatus == http.StatusPreconditionFailed {
+					if rec.Code != http.StatusPreconditionFailed || !strings.Contains(rec.Body.String(), `"code":"precondition_failed"`) {
+						t.Errorf("status = %d, want 412: %s", rec.Code, rec.Body)
+					}
+					// The current tag is sent back so the client can refetch or retry
+					if etag := rec.Header().Get("ETag"); etag != `"3"` {
+						t.Errorf("ETag = %q, want the current version", etag)
+					}
+					if len(repo.writes) != 0 {
+						t.Errorf("wrote the issue after a failed precondition")
+					}
+					return
+				}
+
+				if rec.Code >= 300 {
+					t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+				}
+				if len(repo.writes) != 1 || repo.writes[0] != tt.wantVersion {
+					t.Errorf("wrote against versions %v, want %d", repo.writes, tt.wantVersion)
+				}
+				if r.method != http.MethodDelete && rec.Header().Get("ETag") != `"4"` {
+					t.Errorf("ETag = %q, want the new version", rec.Header().Get("ETag"))
+				}
+			})
+		}
+	}
+}
+
+func TestPatchIssueIfMatch(t *testing.T) {
+	tests := []struct {
+		ifMatch    string
+		wantStatus int
+	}{
+		{wantStatus: http.StatusOK},
+		{ifMatch: `"3"`, wantStatus: http.StatusOK},
+		{ifMatch: `"2"`, wantStatus: http.StatusPreconditionFailed},
+		{ifMatch: `W/"3"`, wantStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		repo := &versionedIssues{}
+		req := httptest.NewRequest(http.MethodPatch, "/issues/"+testIssueID+"?namespace=team-a", strings.NewReader(`{"title":"Build failed again"}`))
+		req.Header.Set("Content-Type", string(services.PatchTypeMerge))
+		if tt.ifMatch != "" {
+			req.Header.Set("If-Match", tt.ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		issueRouter(repo).ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("If-Match %q: status = %d, want %d: %s", tt.ifMatch, rec.Code, tt.wantStatus, rec.Body)
+		}
+		// Patches are always saved against the version they were applied to
+		if tt.wantStatus == http.StatusOK && (len(repo.writes) != 1 || repo.writes[0] != 3) {
+			t.Errorf("If-Match %q: wrote against versions %v, want 3", tt.ifMatch, repo.writes)
+		}
+	}
+}
+
+func TestAddRelatedIssueRejectsItself(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/issues/"+testIssueID+"/related?namespace=team-a", strings.NewReader(`{"relatedId":"`+testIssueID+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	// The repository would panic if the relationship reached it
+	issueRouter(&versionedIssues{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"field":"relatedId"`) {
+		t.Errorf("status = %d, want 422 for relatedId: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/sirupsen/logrus"
+)
+
+type MetricsHandler struct {
+	metricsService *services.MetricsService
+	defaultWindow  time.Duration
+	logger         *logrus.Logger
+}
+
+// NewMetricsHandler returns a new handler for metrics routes
+func NewMetricsHandler(metricsService *services.MetricsService, defaultWindow time.Duration, logger *logrus.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		metricsService: metricsService,
+		defaultWindow:  defaultWindow,
+		logger:         logger,
+	}
+}
+
+// GetReliability handles GET /metrics/reliability
+//
+// Returns time-to-resolve, failure counts, time spent failing and the current streak for the
+// namespace and each scope in it, optionally limited with resourceType and resourceName.
+// The window is set with since and until, as RFC 3339 times, dates or relative times like now-7d.
+func (h *MetricsHandler) GetReliability(c *gin.Context) {
+	now := time.Now()
+	q := repository.ReliabilityQuery{
+		Namespace:    c.Query("namespace"),
+		ResourceType: c.Query("resourceType"),
+		ResourceName: c.Query("resourceName"),
+		Until:        now,
+	}
+
+	if until := c.Query("until"); until != "" {
+		parsed, err := repository.ParseTime(until, now)
+		if err != nil {
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "invalid until: "+err.Error()))
+			return
+		}
+		q.Until = parsed
+	}
+	q.Since = q.Until.Add(-h.defaultWindow)
+	if since := c.Query("since"); since != "" {
+		parsed, err := repository.ParseTime(since, now)
+		if err != nil {
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "invalid since: "+err.Error()))
+			return
+		}
+		q.Since = parsed
+	}
+	if !q.Since.Before(q.Until) {
+		c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "since must be before until"))
+		return
+	}
+
+	metrics, err := h.metricsService.GetReliability(c.Request.Context(), q)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch reliability metrics of %s: %w", q.Namespace, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
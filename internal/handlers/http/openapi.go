@@ -0,0 +1,608 @@
+package http
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/openapi"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// buildOpenAPI documents the routes registered on the router, returning the routes without an entry in apiOperations.
+// Those are left out of the document, TestOpenAPIDocumentsEveryRoute keeps them from being added.
+func buildOpenAPI(routes gin.RoutesInfo) (*openapi.Document, []string) {
+	g := openapi.NewGenerator()
+	openapi.Enum(g, models.Severities)
+	openapi.Enum(g, models.IssueTypes)
+	openapi.Enum(g, models.IssueStates)
+	openapi.Enum(g, []models.ViewVisibility{models.ViewVisibilityPrivate, models.ViewVisibilityShared})
+
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "Konflux Issues API",
+			Version:     "1.0.0",
+			Description: "API for managing issues in Konflux",
+		},
+		Tags: []openapi.Tag{
+			{Name: "issues", Description: "Issues detected in namespaces"},
+			{Name: "views", Description: "Saved issue filters"},
+			{Name: "metrics", Description: "Reliability metrics computed from issues"},
+			{Name: "admin", Description: "Administrative operations"},
+			{Name: "webhooks", Description: "Webhooks that create and resolve issues"},
+			{Name: "service", Description: "Service health and documentation"},
+			{Name: "graphql", Description: "Issues and their relationships as a GraphQL graph"},
+		},
+		Webhooks: issueEventWebhooks(g),
+		Components: openapi.Components{
+			Responses: map[string]*openapi.Response{
+				"Error": {
+					Description: "The request failed",
+					Content:     openapi.ProblemJSON(g.Schema(dto.Problem{})),
+				},
+			},
+		},
+	}
+
+	operations := apiOperations(g)
+	maps.Copy(operations, v2IssueOperations(operations))
+	for _, operation := range operations {
+		// Errors all have the same shape, so only statuses with another body are listed on each operation
+		for _, status := range []string{"4XX", "5XX"} {
+			if operation.Responses[status] == nil {
+				operation.Responses[status] = openapi.Ref("Error")
+			}
+		}
+	}
+
+	paths := make([]openapi.Route, len(routes))
+	for i, route := range routes {
+		paths[i] = openapi.Route{Method: route.Method, Path: route.Path}
+	}
+	undocumented := doc.AddRoutes(paths, operations)
+
+	doc.Components.Schemas = g.Schemas()
+	return doc, undocumented
+}
+
+// Helper function to describe every route, keyed by method and path as registered on the router
+func apiOperations(g *openapi.Generator) map[string]*openapi.Operation {
+	issue := g.Schema(models.Issue{})
+	issueID := openapi.PathParam("id", "Issue ID")
+	viewID := openapi.PathParam("id", "Saved view ID")
+	etag := map[string]openapi.Header{
+		"ETag": {Description: "Version of the issue, for If-Match and If-None-Match", Schema: openapi.String("")},
+	}
+	ifMatch := openapi.HeaderParam("If-Match", "Only change the issue if its ETag matches")
+	problem := func(description string) *openapi.Response {
+		return &openapi.Response{Description: description, Content: openapi.ProblemJSON(g.Schema(dto.Problem{}))}
+	}
+	preconditionFailed := problem("If-Match didn't match the current version of the issue")
+	issueResponse := func(description string) *openapi.Response {
+		return &openapi.Response{Description: description, Headers: etag, Content: openapi.JSON(issue)}
+	}
+
+	filters := issueFilterParams(g)
+	listParams := slices.Concat(filters, issueListParams())
+	statusResponse := func(properties map[string]*openapi.Schema) *openapi.Schema {
+		properties["status"] = openapi.String("")
+		return openapi.Object(properties, "status")
+	}
+	graphQLRequest := openapi.Object(map[string]*openapi.Schema{
+		"query":         openapi.String("The GraphQL query"),
+		"operationName": openapi.String("Operation to run when the query has several"),
+		"variables":     {Type: "object", Description: "Values of the query's variables"},
+	}, "query")
+	graphQLResponse := openapi.Object(map[string]*openapi.Schema{
+		"data":   {Description: "The result, shaped like the query"},
+		"errors": openapi.Array(&openapi.Schema{Type: "object"}),
+	})
+
+	return map[string]*openapi.Operation{
+		"GET /health": {
+			OperationID: "getHealth",
+			Tags:        []string{"service"},
+			Summary:     "Check the service is healthy",
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The service is healthy", Content: openapi.JSON(openapi.Object(map[string]*openapi.Schema{
+					"status":    openapi.String(""),
+					"message":   openapi.String(""),
+					"timestamp": {Type: "string", Format: "date-time"},
+				}))},
+			},
+		},
+		"GET /version": {
+			OperationID: "getVersion",
+			Tags:        []string{"service"},
+			Summary:     "Get the API version",
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The API version", Content: openapi.JSON(openapi.Object(map[string]*openapi.Schema{
+					"version":     openapi.String(""),
+					"name":        openapi.String(""),
+					"description": openapi.String(""),
+				}))},
+			},
+		},
+		"GET /api/v1/openapi.json": {
+			OperationID: "getOpenAPI",
+			Tags:        []string{"service"},
+			Summary:     "Get this OpenAPI document",
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The OpenAPI document", Content: openapi.JSON(&openapi.Schema{Type: "object"})},
+			},
+		},
+		"GET /api/v1/docs": {
+			OperationID: "getDocs",
+			Tags:        []string{"service"},
+			Summary:     "Browse the API documentation",
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "A page rendering the OpenAPI document", Content: map[string]openapi.MediaType{
+					"text/html": {Schema: openapi.String("")},
+				}},
+			},
+		},
+
+		"GET /api/v1/graphql": {
+			OperationID: "queryGraphQL",
+			Tags:        []string{"graphql"},
+			Summary:     "Run a GraphQL query",
+			Description: "Runs a query of the schema in internal/graph/schema.graphqls, which can be introspected. " +
+				"Subscriptions are served over websockets, using the graphql-transport-ws or graphql-ws protocol.",
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("query", "The GraphQL query", openapi.String("")),
+				openapi.QueryParam("operationName", "Operation to run when the query has several", openapi.String("")),
+				openapi.QueryParam("variables", "Variables as a JSON object", openapi.String("")),
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The GraphQL response, with data and any errors", Content: openapi.JSON(graphQLResponse)},
+			},
+		},
+		"POST /api/v1/graphql": {
+			OperationID: "postGraphQL",
+			Tags:        []string{"graphql"},
+			Summary:     "Run a GraphQL query or subscription",
+			Description: "Subscriptions are sent as Server-Sent Events named next and complete when the request accepts text/event-stream.",
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(graphQLRequest)},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The GraphQL response, or a stream of them for subscriptions", Content: map[string]openapi.MediaType{
+					"application/json":  {Schema: graphQLResponse},
+					"text/event-stream": {Schema: openapi.String("")},
+				}},
+			},
+		},
+
+		"GET /api/v1/issues/": {
+			OperationID: "listIssues",
+			Tags:        []string{"issues"},
+			Summary:     "List issues",
+			Description: "Issues are paginated with limit and offset, or with the cursors in the response when using the default sort.",
+			Parameters:  listParams,
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "A page of issues, with only the requested fields when fields is set", Content: openapi.JSON(&openapi.Schema{
+					OneOf: []*openapi.Schema{g.Schema(dto.IssueResponse{}), g.Schema(dto.ProjectedIssueResponse{})},
+				})},
+			},
+		},
+		"POST /api/v1/issues/": {
+			OperationID: "createIssue",
+			Tags:        []string{"issues"},
+			Summary:     "Create an issue",
+			Description: "An active issue with the same scope is updated instead of creating a duplicate.",
+			Parameters:  []openapi.Parameter{namespaceParam()},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(dto.CreateIssueRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"201": issueResponse("The created or updated issue"),
+			},
+		},
+		"GET /api/v1/issues/stats": {
+			OperationID: "getIssueStats",
+			Tags:        []string{"issues"},
+			Summary:     "Count issues",
+			Description: "Issues matching the filters are counted, grouped by the comma separated fields in groupBy. " +
+				"groupBy=day buckets issues by the UTC day of dateField.",
+			Parameters: append(slices.Clone(filters),
+				openapi.QueryParam("groupBy", "Comma separated fields to group by, such as severity,day", openapi.String("")),
+				openapi.QueryParam("dateField", "Date bucketed by groupBy=day", &openapi.Schema{
+					Type: "string", Enum: []any{"detectedAt", "resolvedAt"}, Default: "detectedAt",
+				}),
+			),
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "Issue counts", Content: openapi.JSON(g.Schema(dto.IssueStatsResponse{}))},
+			},
+		},
+		"GET /api/v1/issues/export": {
+			OperationID: "exportIssues",
+			Tags:        []string{"issues"},
+			Summary:     "Export issues",
+			Description: "Streams every issue matching the filters, without pagination.",
+			Parameters: append(slices.Clone(filters),
+				openapi.QueryParam("format", "", &openapi.Schema{Type: "string", Enum: []any{"csv", "ndjson"}, Default: "csv"}),
+			),
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The issues as a file attachment", Content: map[string]openapi.MediaType{
+					"text/csv":             {Schema: openapi.String("One issue per row, after a header row")},
+					"application/x-ndjson": {Schema: issue},
+				}},
+			},
+		},
+		"GET /api/v1/issues/stream": {
+			OperationID: "streamIssues",
+			Tags:        []string{"issues"},
+			Summary:     "Stream issue changes",
+			Description: "Changes to issues matching the filters are sent as Server-Sent Events named created, updated, resolved or deleted, " +
+				"with the issue as JSON in the data. Clients resume after a disconnect by sending the ID of the last event they received. " +
+				"If that event is no longer in the event log a reset event is sent first, and the issues should be fetched again.",
+			Parameters: append(slices.Clone(filters),
+				openapi.HeaderParam("Last-Event-ID", "ID of the last event received, to replay the events after it"),
+			),
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "A stream of events", Content: map[string]openapi.MediaType{
+					"text/event-stream": {Schema: openapi.String("")},
+				}},
+			},
+		},
+		"GET /api/v1/issues/:id": {
+			OperationID: "getIssue",
+			Tags:        []string{"issues"},
+			Summary:     "Get an issue",
+			Parameters: []openapi.Parameter{issueID, namespaceParam(),
+				openapi.HeaderParam("If-None-Match", "Respond with 304 if the issue still has this ETag"),
+			},
+			Responses: map[string]*openapi.Response{
+				"200": issueResponse("The issue"),
+				"304": {Description: "The issue hasn't changed"},
+			},
+		},
+		"PUT /api/v1/issues/:id": {
+			OperationID: "replaceIssue",
+			Tags:        []string{"issues"},
+			Summary:     "Replace an issue",
+			Description: "Every editable field is replaced, fields left out are cleared. Links without an ID are created, and links not listed are removed.",
+			Parameters:  []openapi.Parameter{issueID, namespaceParam(), ifMatch},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(dto.ReplaceIssueRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"200": issueResponse("The updated issue"),
+				"412": preconditionFailed,
+			},
+		},
+		"PATCH /api/v1/issues/:id": {
+			OperationID: "patchIssue",
+			Tags:        []string{"issues"},
+			Summary:     "Patch an issue",
+			Description: "Applies a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) to the editable fields of the issue.",
+			Parameters:  []openapi.Parameter{issueID, namespaceParam(), ifMatch},
+			RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+				string(services.PatchTypeMerge): {Schema: &openapi.Schema{Type: "object"}},
+				string(services.PatchTypeJSON): {Schema: openapi.Array(openapi.Object(map[string]*openapi.Schema{
+					"op":    openapi.StringEnum("", "add", "remove", "replace", "move", "copy", "test"),
+					"path":  openapi.String("JSON Pointer to the field"),
+					"from":  openapi.String("JSON Pointer to the source, for move and copy"),
+					"value": {},
+				}, "op", "path"))},
+			}},
+			Responses: map[string]*openapi.Response{
+				"200": issueResponse("The updated issue"),
+				"409": problem("A test operation failed, or the issue changed while the patch was applied"),
+				"412": preconditionFailed,
+				"415": {
+					Description: "The patch format isn't supported",
+					Headers: map[string]openapi.Header{
+						"Accept-Patch": {Description: "The supported patch formats", Schema: openapi.String("")},
+					},
+					Content: openapi.ProblemJSON(g.Schema(dto.Problem{})),
+				},
+				"422": problem("The patched issue is invalid"),
+			},
+		},
+		"DELETE /api/v1/issues/:id": {
+			OperationID: "deleteIssue",
+			Tags:        []string{"issues"},
+			Summary:     "Delete an issue",
+			Parameters:  []openapi.Parameter{issueID, namespaceParam(), ifMatch},
+			Responses: map[string]*openapi.Response{
+				"204": {Description: "The issue was deleted"},
+				"412": preconditionFailed,
+			},
+		},
+		"POST /api/v1/issues/:id/resolve": {
+			OperationID: "resolveIssue",
+			Tags:        []string{"issues"},
+			Summary:     "Resolve an issue",
+			Parameters:  []openapi.Parameter{issueID, namespaceParam(), ifMatch},
+			Responses: map[string]*openapi.Response{
+				"200": issueResponse("The resolved issue"),
+				"412": preconditionFailed,
+			},
+		},
+		"POST /api/v1/issues/:id/related": {
+			OperationID: "addRelatedIssue",
+			Tags:        []string{"issues"},
+			Summary:     "Relate two issues",
+			Parameters:  []openapi.Parameter{issueID, namespaceParam()},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(dto.RelatedIssueRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"201": {Description: "The relationship was created", Content: openapi.JSON(openapi.Object(map[string]*openapi.Schema{
+					"message": openapi.String(""),
+				}))},
+				"422": problem("The issue was related to itself"),
+			},
+		},
+		"DELETE /api/v1/issues/:id/related/:relatedId": {
+			OperationID: "removeRelatedIssue",
+			Tags:        []string{"issues"},
+			Summary:     "Remove a relationship between two issues",
+			Parameters:  []openapi.Parameter{issueID, openapi.PathParam("relatedId", "ID of the related issue"), namespaceParam()},
+			Responses: map[string]*openapi.Response{
+				"204": {Description: "The relationship was removed"},
+			},
+		},
+		"POST /api/v1/issues/:id/export/:tracker": {
+			OperationID: "exportIssueToTracker",
+			Tags:        []string{"issues"},
+			Summary:     "Export an issue to an issue tracker",
+			Parameters:  []openapi.Parameter{issueID, openapi.PathParam("tracker", "Name of the tracker, such as jira"), namespaceParam()},
+			Responses: map[string]*openapi.Response{
+				"201": {Description: "Link to the ticket created in the tracker", Content: openapi.JSON(g.Schema(models.Link{}))},
+				"409": {Description: "The issue was already exported", Content: openapi.ProblemJSON(g.Schema(AlreadyExportedProblem{}))},
+			},
+		},
+		"POST /api/v1/issues/bulk": {
+			OperationID: "bulkUpdateIssues",
+			Tags:        []string{"issues"},
+			Summary:     "Change many issues at once",
+			Description: "Resolves, changes the severity of, assigns, labels or deletes the issues listed by ID, " +
+				"or those matching a q filter in a namespace. Issues in namespaces the caller can't access are skipped.",
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("preview", "Only count and list the matching issues", openapi.Boolean("")),
+			},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(dto.BulkIssueRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The outcome for each issue", Content: openapi.JSON(g.Schema(dto.BulkIssueResponse{}))},
+			},
+		},
+
+		"GET /api/v1/views/": {
+			OperationID: "listViews",
+			Tags:        []string{"views"},
+			Summary:     "List saved views",
+			Description: "Lists shared views in the namespace and the caller's private ones.",
+			Parameters:  []openapi.Parameter{namespaceParam()},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The saved views", Content: openapi.JSON(g.Schema(dto.SavedViewListResponse{}))},
+			},
+		},
+		"POST /api/v1/views/": {
+			OperationID: "createView",
+			Tags:        []string{"views"},
+			Summary:     "Save a view",
+			Parameters:  []openapi.Parameter{namespaceParam()},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(dto.SavedViewRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"201": {Description: "The saved view", Content: openapi.JSON(g.Schema(models.SavedView{}))},
+			},
+		},
+		"GET /api/v1/views/:id": {
+			OperationID: "getView",
+			Tags:        []string{"views"},
+			Summary:     "Get a saved view",
+			Parameters:  []openapi.Parameter{viewID, namespaceParam()},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The saved view", Content: openapi.JSON(g.Schema(models.SavedView{}))},
+			},
+		},
+		"PUT /api/v1/views/:id": {
+			OperationID: "updateView",
+			Tags:        []string{"views"},
+			Summary:     "Update a saved view",
+			Parameters:  []openapi.Parameter{viewID, namespaceParam()},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(dto.SavedViewRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The updated view", Content: openapi.JSON(g.Schema(models.SavedView{}))},
+			},
+		},
+		"DELETE /api/v1/views/:id": {
+			OperationID: "deleteView",
+			Tags:        []string{"views"},
+			Summary:     "Delete a saved view",
+			Parameters:  []openapi.Parameter{viewID, namespaceParam()},
+			Responses: map[string]*openapi.Response{
+				"204": {Description: "The view was deleted"},
+			},
+		},
+		"GET /api/v1/views/:id/issues": {
+			OperationID: "listViewIssues",
+			Tags:        []string{"views"},
+			Summary:     "List the issues matching a saved view",
+			Description: "Pagination params override the view's limit.",
+			Parameters: []openapi.Parameter{viewID, namespaceParam(),
+				openapi.QueryParam("limit", "", openapi.Integer("")),
+				openapi.QueryParam("offset", "", openapi.Integer("")),
+				openapi.QueryParam("cursor", "Cursor from a previous page", openapi.String("")),
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "A page of issues", Content: openapi.JSON(&openapi.Schema{
+					OneOf: []*openapi.Schema{g.Schema(dto.IssueResponse{}), g.Schema(dto.ProjectedIssueResponse{})},
+				})},
+			},
+		},
+
+		"GET /api/v1/metrics/reliability": {
+			OperationID: "getReliability",
+			Tags:        []string{"metrics"},
+			Summary:     "Get reliability metrics",
+			Description: "Returns time-to-resolve, failure counts, time spent failing and the current streak for the namespace and each scope in it.",
+			Parameters: []openapi.Parameter{namespaceParam(),
+				openapi.QueryParam("resourceType", "", openapi.String("")),
+				openapi.QueryParam("resourceName", "", openapi.String("")),
+				openapi.QueryParam("since", "Start of the window, as an RFC 3339 time, a date or a relative time like now-7d", openapi.String("")),
+				openapi.QueryParam("until", "End of the window, in the same formats as since", openapi.String("")),
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "Reliability metrics", Content: openapi.JSON(g.Schema(dto.ReliabilityResponse{}))},
+			},
+		},
+
+		"POST /api/v1/admin/import": {
+			OperationID: "importIssues",
+			Tags:        []string{"admin"},
+			Summary:     "Import issues",
+			Description: "Creates issues from an NDJSON or CSV body, such as one from GET /issues/export. Nothing is imported unless every row is valid.",
+			Parameters: []openapi.Parameter{namespaceParam(),
+				openapi.QueryParam("format", "Format of the body, taken from the Content-Type when not set", openapi.StringEnum("", "csv", "ndjson")),
+				openapi.QueryParam("dryRun", "Only validate rows and check for duplicates", openapi.Boolean("")),
+			},
+			RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+				"text/csv":             {Schema: openapi.String("Issues in the export format, after a header row")},
+				"application/x-ndjson": {Schema: g.Schema(dto.ImportIssueRequest{})},
+			}},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The report of a dry run, or of an import that created nothing", Content: openapi.JSON(g.Schema(dto.ImportReport{}))},
+				"201": {Description: "The report of the import", Content: openapi.JSON(g.Schema(dto.ImportReport{}))},
+				"422": {Description: "Some rows are invalid, so nothing was imported", Content: openapi.JSON(g.Schema(dto.ImportReport{}))},
+			},
+		},
+
+		"POST /api/v1/webhooks/pipeline-failure": {
+			OperationID: "pipelineFailure",
+			Tags:        []string{"webhooks"},
+			Summary:     "Record a failed pipeline run",
+			Parameters:  []openapi.Parameter{namespaceParam()},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(dto.PipelineFailureRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"201": {Description: "The issue for the pipeline", Content: openapi.JSON(statusResponse(map[string]*openapi.Schema{
+					"issue": issue,
+				}))},
+			},
+		},
+		"POST /api/v1/webhooks/pipeline-success": {
+			OperationID: "pipelineSuccess",
+			Tags:        []string{"webhooks"},
+			Summary:     "Resolve the issues of a pipeline that succeeded",
+			Parameters:  []openapi.Parameter{namespaceParam()},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(dto.PipelineSuccessRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "How many issues were resolved", Content: openapi.JSON(statusResponse(map[string]*openapi.Schema{
+					"message": openapi.String(""),
+				}))},
+			},
+		},
+		"POST /api/v1/webhooks/jira": {
+			OperationID: "jiraWebhook",
+			Tags:        []string{"webhooks"},
+			Summary:     "Resolve the issue exported to a Jira ticket that was closed",
+			Description: "Only registered when the Jira integration is enabled. " +
+				"The shared webhook secret is given in the X-Kite-Webhook-Secret header, or in the secret query param when the header can't be set.",
+			Parameters: []openapi.Parameter{
+				openapi.HeaderParam(webhookSecretHeader, "The shared webhook secret"),
+				openapi.QueryParam("secret", "The shared webhook secret, when it isn't given in the header", openapi.String("")),
+			},
+			RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(JiraWebhookRequest{}))},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The resolved issue, or a status of ignored", Content: openapi.JSON(statusResponse(map[string]*openapi.Schema{
+					"issue": issue,
+				}))},
+			},
+		},
+	}
+}
+
+// Helper function to describe the v2 issue routes, which are the v1 ones with the namespace in the path
+func v2IssueOperations(operations map[string]*openapi.Operation) map[string]*openapi.Operation {
+	v2Operations := make(map[string]*openapi.Operation)
+	for key, operation := range operations {
+		method, path, _ := strings.Cut(key, " ")
+		rest, ok := strings.CutPrefix(path, "/api/v1/issues/")
+		// Bulk actions span namespaces, so they're only in v1
+		if !ok || rest == "bulk" {
+			continue
+		}
+
+		v2Operation := *operation
+		v2Operation.OperationID += "V2"
+		v2Operation.Parameters = []openapi.Parameter{openapi.PathParam("namespace", "Namespace of the issues, which the caller must have access to")}
+		for _, param := range operation.Parameters {
+			if param.In != "query" || param.Name != "namespace" {
+				v2Operation.Parameters = append(v2Operation.Parameters, param)
+			}
+		}
+		v2Operations[method+" /api/v2/namespaces/:namespace/issues/"+rest] = &v2Operation
+	}
+	return v2Operations
+}
+
+// Helper function to describe the namespace param checked by the namespace middleware
+func namespaceParam() openapi.Parameter {
+	param := openapi.QueryParam("namespace", "Namespace the caller must have access to", openapi.String(""))
+	param.Required = true
+	return param
+}
+
+// Helper function to describe the filters read by parseIssueQueryFilters
+func issueFilterParams(g *openapi.Generator) []openapi.Parameter {
+	return []openapi.Parameter{
+		namespaceParam(),
+		openapi.QueryParam("severity", "", g.Schema(models.SeverityInfo)),
+		openapi.QueryParam("issueType", "", g.Schema(models.IssueTypeBuild)),
+		openapi.QueryParam("state", "", g.Schema(models.IssueStateActive)),
+		openapi.QueryParam("resourceType", "", openapi.String("")),
+		openapi.QueryParam("resourceName", "", openapi.String("")),
+		openapi.QueryParam("search", "Full-text search of titles, descriptions and scope names", openapi.String("")),
+		openapi.QueryParam("q", "Structured filter, e.g. severity>=major AND detectedAt>now-7d", openapi.String("")),
+	}
+}
+
+// Helper function to describe the sorting, projection and pagination params of issue lists
+func issueListParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.QueryParam("sort", "Comma separated fields to sort by, prefixed with - for descending order", openapi.String("")),
+		openapi.QueryParam("fields", "Comma separated fields to return for each issue", openapi.String("")),
+		openapi.QueryParam("include", "Comma separated associations to load, empty for none", openapi.String("")),
+		openapi.QueryParam("limit", "", &openapi.Schema{Type: "integer", Default: 50}),
+		openapi.QueryParam("offset", "", openapi.Integer("")),
+		openapi.QueryParam("cursor", "Cursor from a previous page, only with the default sort", openapi.String("")),
+	}
+}
+
+// Helper function to describe the CloudEvents sent for issue changes when an event sink is configured
+func issueEventWebhooks(g *openapi.Generator) map[string]openapi.PathItem {
+	webhooks := make(map[string]openapi.PathItem)
+	for _, eventType := range []string{
+		models.EventTypeIssueCreated, models.EventTypeIssueUpdated, models.EventTypeIssueResolved, models.EventTypeIssueDeleted,
+	} {
+		data := "the issue after the change"
+		if eventType == models.EventTypeIssueDeleted {
+			data = "the issue before it was deleted"
+		}
+		webhooks[eventType] = openapi.PathItem{
+			"post": {
+				OperationID: eventType,
+				Summary:     "CloudEvent sent to the event sink",
+				Description: "Sent in binary content mode, so the event attributes are ce-* headers and the body is " + data + ". " +
+					"ce-id is stable across retries, so sinks can deduplicate on it.",
+				Parameters: []openapi.Parameter{
+					cloudEventHeader("ce-specversion", "1.0"),
+					cloudEventHeader("ce-id", "ID of the event"),
+					cloudEventHeader("ce-type", eventType),
+					cloudEventHeader("ce-source", "The configured source, followed by /namespaces/ and the issue namespace"),
+					cloudEventHeader("ce-subject", "ID of the issue"),
+					cloudEventHeader("ce-time", "When the change was made"),
+				},
+				RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(g.Schema(models.Issue{}))},
+				Responses: map[string]*openapi.Response{
+					"2XX": {Description: "The event was accepted, other statuses are retried"},
+				},
+			},
+		}
+	}
+	return webhooks
+}
+
+func cloudEventHeader(name, description string) openapi.Parameter {
+	param := openapi.HeaderParam(name, description)
+	param.Required = true
+	return param
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/internal/openapi"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// The OpenAPI document checked in for API clients, regenerated with go test ./internal/handlers/http -run OpenAPI -update
+var (
+	checkedInOpenAPI = filepath.Join("..", "..", "..", "docs", "openapi.json")
+	updateOpenAPI    = flag.Bool("update", false, "regenerate the checked-in OpenAPI document")
+)
+
+// Helper function to set up the router with every optional route registered.
+// Nothing listens on the database, none of the requests made reach it.
+func testRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("FEATURE_NAMESPACE_CHECKING", "false")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Jira.Enabled = true
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 user=x dbname=x"}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	shared, err := NewServices(db, cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := SetupRouter(db, cfg, shared, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	routes := testRouter(t).Routes()
+
+	if _, undocumented := buildOpenAPI(routes); len(undocumented) > 0 {
+		t.Errorf("routes without an operation in apiOperations: %v", undocumented)
+	}
+
+	// Operations of routes that were removed or renamed
+	registered := make([]string, len(routes))
+	for i, route := range routes {
+		registered[i] = openapi.Route{Method: route.Method, Path: route.Path}.Key()
+	}
+	operations := apiOperations(openapi.NewGenerator())
+	maps.Copy(operations, v2IssueOperations(operations))
+	for key := range operations {
+		if !slices.Contains(registered, key) {
+			t.Errorf("operation %s documents a route that isn't registered", key)
+		}
+	}
+}
+
+func TestOpenAPIMatchesCheckedInDocument(t *testing.T) {
+	rec := httptest.NewRecorder()
+	testRouter(t).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var served bytes.Buffer
+	if err := json.Indent(&served, rec.Body.Bytes(), "", "  "); err != nil {
+		t.Fatal(err)
+	}
+	served.WriteString("\n")
+
+	if *updateOpenAPI {
+		if err := os.WriteFile(checkedInOpenAPI, served.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	checkedIn, err := os.ReadFile(checkedInOpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(served.Bytes(), checkedIn) {
+		t.Errorf("%s is out of date, regenerate it with go test ./internal/handlers/http -run OpenAPI -update", checkedInOpenAPI)
+	}
+}
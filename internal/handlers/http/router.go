@@ -1,15 +1,56 @@
 package http
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/internal/events"
+	"github.com/konflux-ci/kite/internal/graph"
 	"github.com/konflux-ci/kite/internal/middleware"
 	"github.com/konflux-ci/kite/internal/repository"
 	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/internal/tracker"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-func SetupRouter(db *gorm.DB, logger *logrus.Logger) (*gin.Engine, error) {
+// Services are shared by the REST API, the gRPC server and the background workers,
+// so every way in checks access and changes issues the same way
+type Services struct {
+	Issues   *services.IssueService
+	Trackers *services.TrackerService
+	// Nil when namespace checking is disabled
+	NamespaceChecker *middleware.NamespaceChecker
+}
+
+// NewServices initializes the shared services, failing when callers can't be authenticated
+func NewServices(db *gorm.DB, cfg *config.Config, logger *logrus.Logger) (*Services, error) {
+	issueRepo := repository.NewIssueRepository(db, logger)
+	trackerService := services.NewTrackerService(issueRepo, repository.NewLinkRepository(db, logger), logger)
+	if cfg.Jira.Enabled {
+		trackerService.Register(tracker.NewJiraClient(cfg.Jira), cfg.Jira.AutoExportSeverity)
+	}
+
+	// Every caller can access every namespace without a namespace checker
+	var namespaceChecker *middleware.NamespaceChecker
+	if cfg.Features.EnableNamespaceChecking {
+		var err error
+		if namespaceChecker, err = middleware.NewNamespaceChecker(cfg.Auth, logger); err != nil {
+			return nil, fmt.Errorf("failed to initialize namespace checker: %w", err)
+		}
+	} else {
+		logger.Warn("Namespace checking disabled by FEATURE_NAMESPACE_CHECKING, every caller can access every namespace")
+	}
+
+	return &Services{
+		Issues:           services.NewIssueService(issueRepo, logger),
+		Trackers:         trackerService,
+		NamespaceChecker: namespaceChecker,
+	}, nil
+}
+
+func SetupRouter(db *gorm.DB, cfg *config.Config, shared *Services, logger *logrus.Logger) (*gin.Engine, error) {
 	// Set Gin mode based on environmetn
 	if gin.Mode() == gin.DebugMode {
 		gin.SetMode(gin.DebugMode)
@@ -23,21 +64,35 @@ func SetupRouter(db *gorm.DB, logger *logrus.Logger) (*gin.Engine, error) {
 	router.Use(middleware.Logger(logger))
 	router.Use(middleware.ErrorHandler(logger))
 	router.Use(middleware.CORS())
+	router.Use(middleware.ForwardedUser(cfg.Security.TrustedProxies))
 	router.Use(gin.Recovery())
+	router.NoRoute(middleware.NoRoute())
 
 	// Initialize repository
 	issueRepo := repository.NewIssueRepository(db, logger)
+	viewRepo := repository.NewSavedViewRepository(db, logger)
 	// Initialize services
-	issueService := services.NewIssueService(issueRepo, logger)
+	issueService := shared.Issues
+	trackerService := shared.Trackers
+	namespaceChecker := shared.NamespaceChecker
+	viewService := services.NewSavedViewService(viewRepo, logger)
+	metricsService := services.NewMetricsService(issueRepo, cfg.Metrics.CacheTTL, cfg.Metrics.CacheSize, logger)
 
 	// Initialize handlers
 	issueHandler := NewIssueHandler(issueService, logger)
 	webhookHandler := NewWebhookHandler(issueService, logger)
+	trackerHandler := NewTrackerHandler(issueService, trackerService, cfg.Jira.WebhookSecret, logger)
+	viewHandler := NewViewHandler(viewService, issueService, logger)
+	metricsHandler := NewMetricsHandler(metricsService, cfg.Metrics.DefaultWindow, logger)
+	exportHandler := NewExportHandler(issueService, cfg.Server.WriteTimeout, logger)
+	importHandler := NewImportHandler(issueService, logger)
+
+	// Live issue changes are fed by database notifications, so they work across replicas
+	eventBroker := events.NewBroker(config.GetDatabaseConfig().ConnectionString(), repository.NewOutboxRepository(db, logger), logger)
+	streamHandler := NewStreamHandler(eventBroker, logger)
 
-	// Initialize namespace checker
-	namespaceChecker, err := middleware.NewNamespaceChecker(logger)
-	if err != nil {
-		logger.WithError(err).Warn("Failed to initialize namespace checker")
+	if namespaceChecker != nil {
+		router.Use(namespaceChecker.Authenticate())
 	}
 
 	// Health and version endpoints
@@ -53,31 +108,92 @@ func SetupRouter(db *gorm.DB, logger *logrus.Logger) (*gin.Engine, error) {
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 
-	// Issues routes with namespace checking
-	issuesGroup := v1.Group("/issues")
-	if namespaceChecker != nil {
+	// API documentation, generated from the routes below
+	docsHandler := NewDocsHandler()
+	v1.GET("/openapi.json", docsHandler.GetOpenAPI)
+	v1.GET("/docs", docsHandler.GetDocs)
+
+	// GraphQL takes namespaces as arguments, so access is checked by the resolvers instead
+	graphQLHandler := NewGraphQLHandler(graph.NewServer(graph.NewResolver(issueService, eventBroker, namespaceChecker, logger), cfg.GraphQL.ComplexityLimit), logger)
+	v1.GET("/graphql", graphQLHandler.ServeGraphQL)
+	v1.POST("/graphql", graphQLHandler.ServeGraphQL)
+
+	// Issue routes are the same in v1, with the namespace in a query param, and v2, with it in the path
+	issueRoutes := func(issuesGroup *gin.RouterGroup) {
 		issuesGroup.Use(namespaceChecker.CheckNamespacessAccess())
-	}
-	{
 		issuesGroup.GET("/", issueHandler.GetIssues)
 		issuesGroup.POST("/", issueHandler.CreateIssue)
+		issuesGroup.GET("/stats", issueHandler.GetIssueStats)
+		issuesGroup.GET("/export", exportHandler.ExportIssues)
+		issuesGroup.GET("/stream", streamHandler.StreamIssues)
 		issuesGroup.GET("/:id", middleware.ValidateID(), issueHandler.GetIssue)
 		issuesGroup.PUT("/:id", middleware.ValidateID(), issueHandler.UpdateIssue)
+		issuesGroup.PATCH("/:id", middleware.ValidateID(), issueHandler.PatchIssue)
 		issuesGroup.DELETE("/:id", middleware.ValidateID(), issueHandler.DeleteIssue)
 		issuesGroup.POST("/:id/resolve", middleware.ValidateID(), issueHandler.ResolveIssue)
 		issuesGroup.POST("/:id/related", middleware.ValidateID(), issueHandler.AddRelatedIssue)
 		issuesGroup.DELETE("/:id/related/:relatedId", middleware.ValidateID(), issueHandler.RemoveRelatedIssue)
+		issuesGroup.POST("/:id/export/:tracker", middleware.ValidateID(), trackerHandler.ExportIssue)
+	}
+
+	// Issues routes with namespace checking
+	issueRoutes(v1.Group("/issues"))
+
+	// Bulk actions can span namespaces, so access is checked for each issue instead
+	bulkHandler := NewBulkHandler(issueService, namespaceChecker, logger)
+	v1.POST("/issues/bulk", bulkHandler.BulkUpdateIssues)
+
+	// Saved view routes with namespace checking
+	viewsGroup := v1.Group("/views")
+	viewsGroup.Use(namespaceChecker.CheckNamespacessAccess())
+	{
+		viewsGroup.GET("/", viewHandler.GetViews)
+		viewsGroup.POST("/", viewHandler.CreateView)
+		viewsGroup.GET("/:id", middleware.ValidateID(), viewHandler.GetView)
+		viewsGroup.PUT("/:id", middleware.ValidateID(), viewHandler.UpdateView)
+		viewsGroup.DELETE("/:id", middleware.ValidateID(), viewHandler.DeleteView)
+		viewsGroup.GET("/:id/issues", middleware.ValidateID(), viewHandler.GetViewIssues)
+	}
+
+	// Metrics routes with namespace checking
+	metricsGroup := v1.Group("/metrics")
+	metricsGroup.Use(namespaceChecker.CheckNamespacessAccess())
+	{
+		metricsGroup.GET("/reliability", metricsHandler.GetReliability)
+	}
+
+	// Admin routes with namespace checking
+	adminGroup := v1.Group("/admin")
+	adminGroup.Use(namespaceChecker.CheckNamespacessAccess())
+	{
+		adminGroup.POST("/import", importHandler.ImportIssues)
 	}
 
 	// Webhook routes with namespace checking
 	webhooksGroup := v1.Group("/webhooks")
-	if namespaceChecker != nil {
-		webhooksGroup.Use(namespaceChecker.CheckNamespacessAccess())
-	}
+	webhooksGroup.Use(namespaceChecker.CheckNamespacessAccess())
 	{
 		webhooksGroup.POST("/pipeline-failure", webhookHandler.PipelineFailure)
 		webhooksGroup.POST("/pipeline-success", webhookHandler.PipelineSuccess)
 	}
 
+	// Jira webhooks carry no namespace, they're authenticated with a shared secret instead
+	if cfg.Jira.Enabled {
+		v1.POST("/webhooks/jira", trackerHandler.JiraWebhook)
+	}
+
+	// API v2 routes, where resources are scoped to the namespace in their path
+	v2 := router.Group("/api/v2")
+	issueRoutes(v2.Group("/namespaces/:namespace/issues"))
+
+	// Routes without documentation are caught by the tests, they're only left out of the document at runtime
+	doc, undocumented := buildOpenAPI(router.Routes())
+	if len(undocumented) > 0 {
+		logger.WithField("routes", undocumented).Warn("Routes without an OpenAPI operation")
+	}
+	if err := docsHandler.SetDocument(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
 	return router, nil
 }
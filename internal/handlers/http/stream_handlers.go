@@ -0,0 +1,163 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/events"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// How often a comment is sent to keep idle connections open through proxies
+const streamHeartbeatInterval = 15 * time.Second
+
+// Event sent instead of replaying missed events when the last event received has been pruned from the event log,
+// clients should fetch the issues again since changes were missed
+const streamResetEvent = "reset"
+
+type StreamHandler struct {
+	broker *events.Broker
+	logger *logrus.Logger
+}
+
+// NewStreamHandler returns a new handler for streaming issue changes
+func NewStreamHandler(broker *events.Broker, logger *logrus.Logger) *StreamHandler {
+	return &StreamHandler{
+		broker: broker,
+		logger: logger,
+	}
+}
+
+// StreamIssues handles GET /issues/stream
+//
+// Issue changes matching the same filters as GET /issues are sent as Server-Sent Events.
+// Clients resume after a disconnect by sending the ID of the last event they received
+// in the Last-Event-ID header. When that event is no longer in the event log a reset event
+// is sent first, since the changes in between can't be replayed.
+func (h *StreamHandler) StreamIssues(c *gin.Context) {
+	filters, err := parseIssueQueryFilters(issueFilterValues(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	var position repository.EventPosition
+	resume, reset := false, false
+	if lastEventID := c.GetHeader("Last-Event-ID"); lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "invalid Last-Event-ID header"))
+			return
+		}
+		position, err = h.broker.Position(ctx, id)
+		switch {
+		case errors.Is(err, repository.ErrEventNotFound):
+			reset = true
+		case err != nil:
+			c.Error(fmt.Errorf("failed to find last event: %w", err))
+			return
+		default:
+			resume = true
+		}
+	}
+
+	sub, err := h.broker.Subscribe(ctx)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to subscribe to issue events: %w", err))
+		return
+	}
+	defer h.broker.Unsubscribe(sub)
+
+	// Streams outlive the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.WithError(err).Debug("Failed to clear write deadline for stream")
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	if reset {
+		c.Render(-1, sse.Event{
+			Event: streamResetEvent,
+			Data:  gin.H{"detail": "the last event received is no longer in the event log, fetch the issues again"},
+		})
+		c.Writer.Flush()
+	}
+
+	// Send anything the client missed while disconnected
+	if resume {
+		for {
+			missed, err := h.broker.Replay(ctx, position)
+			if err != nil {
+				h.logger.WithError(err).Error("Failed to replay issue events")
+				return
+			}
+			for _, event := range missed {
+				h.sendEvent(c, event, filters)
+				position = repository.PositionOf(event)
+			}
+			if len(missed) == 0 {
+				break
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind, the client will reconnect with Last-Event-ID
+				return
+			}
+			// Replayed events can also arrive live
+			if !repository.PositionOf(event).After(position) {
+				continue
+			}
+			h.sendEvent(c, event, filters)
+			position = repository.PositionOf(event)
+		}
+	}
+}
+
+// Helper function to write an event to the stream if its issue matches the filters
+func (h *StreamHandler) sendEvent(c *gin.Context, event models.OutboxEvent, filters repository.IssueQueryFilters) {
+	var issue models.Issue
+	if err := json.Unmarshal(event.Payload, &issue); err != nil {
+		h.logger.WithError(err).WithField("event_id", event.ID).Warn("Failed to decode issue event")
+		return
+	}
+	if !filters.Matches(&issue) {
+		return
+	}
+
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: strings.TrimPrefix(event.Type, "dev.konflux.kite.issue."),
+		Data:  event.Payload,
+	})
+	c.Writer.Flush()
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/events"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryOutbox is an event log whose events have all been committed, in the order of their positions
+type memoryOutbox struct {
+	repository.OutboxRepository
+	events []models.OutboxEvent
+}
+
+func (m *memoryOutbox) FindAfter(_ context.Context, after repository.EventPosition, limit int) ([]models.OutboxEvent, error) {
+	var found []models.OutboxEvent
+	for _, event := range m.events {
+		if repository.PositionOf(event).After(after) && len(found) < limit {
+			found = append(found, event)
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryOutbox) LatestPosition(context.Context) (repository.EventPosition, error) {
+	if len(m.events) == 0 {
+		return repository.EventPosition{}, nil
+	}
+	return repository.PositionOf(m.events[len(m.events)-1]), nil
+}
+
+func (m *memoryOutbox) FindPosition(_ context.Context, id int64) (repository.EventPosition, error) {
+	idx := slices.IndexFunc(m.events, func(event models.OutboxEvent) bool { return event.ID == id })
+	if idx < 0 {
+		return repository.EventPosition{}, repository.ErrEventNotFound
+	}
+	return repository.PositionOf(m.events[idx]), nil
+}
+
+func outboxEvent(t *testing.T, txID, id int64, namespace string) models.OutboxEvent {
+	payload, err := json.Marshal(models.Issue{ID: "issue", Namespace: namespace})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.OutboxEvent{TxID: txID, ID: id, Type: models.EventTypeIssueUpdated, Namespace: namespace, Payload: payload}
+}
+
+// Helper function to read what a stream sends before the request is cancelled
+func streamBody(t *testing.T, outbox *memoryOutbox, lastEventID string) string {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	// Nothing listens on the DSN, so only replayed events are sent
+	broker := events.NewBroker("host=127.0.0.1 port=1 user=x dbname=x connect_timeout=1", outbox, logger)
+	handler := NewStreamHandler(broker, logger)
+
+	router := gin.New()
+	router.GET("/issues/stream", handler.StreamIssues)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/issues/stream?namespace=team-a", nil).WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func TestStreamIssuesReplaysInCommitOrder(t *testing.T) {
+	// Event 6 was inserted after event 7, but by a transaction that started first
+	outbox := &memoryOutbox{events: []models.OutboxEvent{
+		outboxEvent(t, 100, 5, "team-a"),
+		outboxEvent(t, 101, 8, "team-a"),
+		outboxEvent(t, 102, 6, "team-a"),
+		outboxEvent(t, 103, 7, "team-b"),
+		outboxEvent(t, 104, 9, "team-a"),
+	}}
+
+	body := streamBody(t, outbox, "8")
+
+	var ids []string
+	for _, line := range strings.Split(body, "\n") {
+		if id, ok := strings.CutPrefix(line, "id:"); ok {
+			ids = append(ids, id)
+		}
+	}
+	if want := []string{"6", "9"}; !slices.Equal(ids, want) {
+		t.Errorf("replayed events %v, want %v in:\n%s", ids, want, body)
+	}
+}
+
+func TestStreamIssuesResetsWhenLastEventIsPruned(t *testing.T) {
+	outbox := &memoryOutbox{events: []models.OutboxEvent{outboxEvent(t, 100, 50, "team-a")}}
+
+	body := streamBody(t, outbox, "3")
+
+	if !strings.HasPrefix(body, "event:reset\n") {
+		t.Errorf("stream doesn't start with a reset event:\n%s", body)
+	}
+	if strings.Contains(body, "id:50") {
+		t.Errorf("events after a pruned event were replayed:\n%s", body)
+	}
+}
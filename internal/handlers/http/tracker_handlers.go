@@ -0,0 +1,133 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Header carrying the shared secret of tracker webhooks
+const webhookSecretHeader = "X-Kite-Webhook-Secret"
+
+type TrackerHandler struct {
+	issueService   *services.IssueService
+	trackerService *services.TrackerService
+	webhookSecret  string
+	logger         *logrus.Logger
+}
+
+// NewTrackerHandler returns a new handler for external tracker routes
+func NewTrackerHandler(issueService *services.IssueService, trackerService *services.TrackerService, webhookSecret string, logger *logrus.Logger) *TrackerHandler {
+	return &TrackerHandler{
+		issueService:   issueService,
+		trackerService: trackerService,
+		webhookSecret:  webhookSecret,
+		logger:         logger,
+	}
+}
+
+// AlreadyExportedProblem is the conflict responded with when an issue was already exported
+type AlreadyExportedProblem struct {
+	dto.Problem
+	// The ticket the issue was exported to
+	Link *models.Link `json:"link"`
+}
+
+// JiraWebhookRequest is the subset of the Jira issue webhook payload we use
+type JiraWebhookRequest struct {
+	WebhookEvent string `json:"webhookEvent"`
+	Issue        struct {
+		Key    string `json:"key" binding:"required"`
+		Fields struct {
+			Status struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	} `json:"issue" binding:"required"`
+}
+
+// ExportIssue handles POST /issues/:id/export/:tracker
+func (h *TrackerHandler) ExportIssue(c *gin.Context) {
+	trackerName := c.Param("tracker")
+
+	if _, err := h.trackerService.Tracker(trackerName); err != nil {
+		c.Error(err)
+		return
+	}
+
+	issue, err := findIssue(c, h.issueService)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	link, err := h.trackerService.ExportIssue(c.Request.Context(), issue, trackerName)
+	if err != nil {
+		if errors.Is(err, services.ErrAlreadyExported) {
+			// The problem is extended with the link to the existing ticket
+			c.Header("Content-Type", dto.ProblemContentType)
+			c.JSON(http.StatusConflict, AlreadyExportedProblem{Problem: middleware.ProblemFor(c, err), Link: link})
+			return
+		}
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"issue_id": issue.ID,
+			"tracker":  trackerName,
+		}).Error("Failed to export issue")
+		c.Error(apperrors.NewProblem(dto.CodeUpstreamFailed, "failed to export issue"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// JiraWebhook handles POST /webhooks/jira
+func (h *TrackerHandler) JiraWebhook(c *gin.Context) {
+	// Jira can't sign webhooks, so they carry a shared secret instead.
+	// The header keeps it out of access logs, the query param is for senders that can't set headers.
+	secret := c.GetHeader(webhookSecretHeader)
+	if secret == "" {
+		secret = c.Query("secret")
+	}
+	if h.webhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(h.webhookSecret)) != 1 {
+		c.Error(apperrors.NewProblem(dto.CodeUnauthorized, "invalid webhook secret"))
+		return
+	}
+
+	var req JiraWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	// We only care about tickets that were closed
+	if req.Issue.Fields.Status.StatusCategory.Key != "done" {
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
+	}
+
+	issue, err := h.trackerService.ResolveFromExternal(c.Request.Context(), "jira", req.Issue.Key)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to resolve issue from jira webhook for %s: %w", req.Issue.Key, err))
+		return
+	}
+	if issue == nil {
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"issue":  issue,
+	})
+}
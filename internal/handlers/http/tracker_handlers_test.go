@@ -0,0 +1,75 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+func TestJiraWebhookSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		query      string
+		wantStatus int
+	}{
+		{name: "secret in header", header: "s3cret", wantStatus: http.StatusOK},
+		{name: "secret in query", query: "s3cret", wantStatus: http.StatusOK},
+		{name: "wrong secret in header", header: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "wrong secret in query", query: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "header takes precedence", header: "guess", query: "s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "no secret", wantStatus: http.StatusUnauthorized},
+	}
+
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(logger))
+	// Tickets that weren't closed are ignored without looking up their issue
+	router.POST("/webhooks/jira", NewTrackerHandler(nil, nil, "s3cret", logger).JiraWebhook)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/webhooks/jira"
+			if tt.query != "" {
+				target += "?secret=" + tt.query
+			}
+			body := `{"webhookEvent":"jira:issue_updated","issue":{"key":"KITE-1","fields":{"status":{"statusCategory":{"key":"indeterminate"}}}}}`
+			req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.header != "" {
+				req.Header.Set(webhookSecretHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
+
+func TestJiraWebhookWithoutConfiguredSecret(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(logger))
+	router.POST("/webhooks/jira", NewTrackerHandler(nil, nil, "", logger).JiraWebhook)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/jira?secret=", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d when no secret is configured: %s", rec.Code, http.StatusUnauthorized, rec.Body)
+	}
+}
@@ -0,0 +1,218 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+type ViewHandler struct {
+	viewService  *services.SavedViewService
+	issueService *services.IssueService
+	logger       *logrus.Logger
+}
+
+// NewViewHandler returns a new handler for saved view routes
+func NewViewHandler(viewService *services.SavedViewService, issueService *services.IssueService, logger *logrus.Logger) *ViewHandler {
+	return &ViewHandler{
+		viewService:  viewService,
+		issueService: issueService,
+		logger:       logger,
+	}
+}
+
+// GetViews handles GET /views, listing the shared views in the namespace and the user's private views
+func (h *ViewHandler) GetViews(c *gin.Context) {
+	namespace, ok := viewNamespace(c)
+	if !ok {
+		return
+	}
+
+	views, err := h.viewService.ListViews(c.Request.Context(), namespace, middleware.GetUser(c))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch views: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, dto.SavedViewListResponse{Data: views})
+}
+
+// GetView handles GET /views/:id
+func (h *ViewHandler) GetView(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// CreateView handles POST /views, the view is owned by the user making the request
+func (h *ViewHandler) CreateView(c *gin.Context) {
+	namespace, ok := viewNamespace(c)
+	if !ok {
+		return
+	}
+	user := middleware.GetUser(c)
+	if user == "" {
+		c.Error(apperrors.NewProblem(dto.CodeUnauthorized, "views can only be created by an identified user"))
+		return
+	}
+
+	var req dto.SavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	if !h.validateViewRequest(c, req) {
+		return
+	}
+
+	view, err := h.viewService.CreateView(c.Request.Context(), namespace, user, req)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to create view: %w", err))
+		return
+	}
+	c.JSON(http.StatusCreated, view)
+}
+
+// UpdateView handles PUT /views/:id
+func (h *ViewHandler) UpdateView(c *gin.Context) {
+	namespace, ok := viewNamespace(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	if !h.validateViewRequest(c, req) {
+		return
+	}
+
+	view, err := h.viewService.UpdateView(c.Request.Context(), c.Param("id"), namespace, middleware.GetUser(c), req)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to update view: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// DeleteView handles DELETE /views/:id
+func (h *ViewHandler) DeleteView(c *gin.Context) {
+	namespace, ok := viewNamespace(c)
+	if !ok {
+		return
+	}
+
+	if err := h.viewService.DeleteView(c.Request.Context(), c.Param("id"), namespace, middleware.GetUser(c)); err != nil {
+		c.Error(fmt.Errorf("failed to delete view: %w", err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetViewIssues handles GET /views/:id/issues, listing the issues matching the view.
+// The view's namespace always applies, pagination params in the request override the view's limit.
+func (h *ViewHandler) GetViewIssues(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+
+	filters, err := parseIssueQueryFilters(viewFilterValues(view.Filters))
+	if err != nil {
+		// Filters are checked when views are saved, so this only happens if the filter language changed
+		h.logger.WithError(err).WithField("view_id", view.ID).Warn("Saved view has invalid filters")
+		c.Error(err)
+		return
+	}
+	filters.Namespace = view.Namespace
+	filters.Limit = view.Filters.Limit
+	if err := parsePagination(c, &filters); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.issueService.FindIssues(c.Request.Context(), filters)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch issues of view %s: %w", view.ID, err))
+		return
+	}
+
+	respondIssues(c, result, filters)
+}
+
+// Helper function to find the view in the request, adds an error to the request if it isn't visible
+func (h *ViewHandler) findView(c *gin.Context) (*models.SavedView, bool) {
+	namespace, ok := viewNamespace(c)
+	if !ok {
+		return nil, false
+	}
+
+	view, err := h.viewService.GetView(c.Request.Context(), c.Param("id"), namespace, middleware.GetUser(c))
+	if err != nil {
+		c.Error(fmt.Errorf("failed to fetch view: %w", err))
+		return nil, false
+	}
+	return view, true
+}
+
+// Helper function to get the namespace the middleware authorized, adds an error to the request if there's none
+func viewNamespace(c *gin.Context) (string, bool) {
+	namespace := middleware.GetNamespace(c)
+	if namespace == "" {
+		c.Error(errMissingNamespace)
+		return "", false
+	}
+	return namespace, true
+}
+
+// Helper function to validate a view request, the filters have to be valid issue list params
+func (h *ViewHandler) validateViewRequest(c *gin.Context, req dto.SavedViewRequest) bool {
+	if req.Visibility != "" && req.Visibility != models.ViewVisibilityPrivate && req.Visibility != models.ViewVisibilityShared {
+		c.Error(&apperrors.ValidationError{Fields: []dto.FieldError{{Field: "visibility", Message: "invalid visibility value"}}})
+		return false
+	}
+	if req.Filters.Limit < 0 {
+		c.Error(&apperrors.ValidationError{Fields: []dto.FieldError{{Field: "filters.limit", Message: "invalid limit value"}}})
+		return false
+	}
+	if _, err := parseIssueQueryFilters(viewFilterValues(req.Filters)); err != nil {
+		c.Error(err)
+		return false
+	}
+	return true
+}
+
+// Helper function to turn saved filters back into GET /issues query params, the limit is applied separately
+func viewFilterValues(filters models.ViewFilters) url.Values {
+	values := url.Values{}
+	for key, value := range map[string]string{
+		"severity":     filters.Severity,
+		"issueType":    filters.IssueType,
+		"state":        filters.State,
+		"resourceType": filters.ResourceType,
+		"resourceName": filters.ResourceName,
+		"search":       filters.Search,
+		"q":            filters.Q,
+		"sort":         filters.Sort,
+		"fields":       filters.Fields,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if filters.Include != nil {
+		values.Set("include", *filters.Include)
+	}
+	return values
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryViews keeps saved views by ID
+type memoryViews struct {
+	repository.SavedViewRepository
+	views map[string]*models.SavedView
+}
+
+func (m *memoryViews) Create(_ context.Context, view *models.SavedView) error {
+	view.ID = "00000000-0000-0000-0000-000000000002"
+	m.views[view.ID] = view
+	return nil
+}
+
+func (m *memoryViews) FindByID(_ context.Context, id string) (*models.SavedView, error) {
+	return m.views[id], nil
+}
+
+func (m *memoryViews) Update(_ context.Context, view *models.SavedView) error {
+	m.views[view.ID] = view
+	return nil
+}
+
+func (m *memoryViews) Delete(_ context.Context, id string) error {
+	delete(m.views, id)
+	return nil
+}
+
+func TestViewHandlersUseAuthorizedNamespace(t *testing.T) {
+	const viewID = "00000000-0000-0000-0000-000000000001"
+
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	views := &memoryViews{views: map[string]*models.SavedView{
+		viewID: {ID: viewID, Name: "team-b", Owner: "alice", Namespace: "team-b", Visibility: models.ViewVisibilityShared},
+	}}
+	handler := NewViewHandler(services.NewSavedViewService(views, logger), nil, logger)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(logger), func(c *gin.Context) {
+		c.Request = c.Request.WithContext(middleware.WithPrincipal(c.Request.Context(), &middleware.Principal{Name: "alice"}))
+	})
+	group := router.Group("/views")
+	group.Use((*middleware.NamespaceChecker)(nil).CheckNamespacessAccess())
+	group.POST("/", handler.CreateView)
+	group.GET("/:id", middleware.ValidateID(), handler.GetView)
+	group.PUT("/:id", middleware.ValidateID(), handler.UpdateView)
+	group.DELETE("/:id", middleware.ValidateID(), handler.DeleteView)
+	// Without the middleware no namespace is authorized
+	router.GET("/unchecked/:id", handler.GetView)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{name: "get in the view's namespace", method: http.MethodGet, target: "/views/" + viewID + "?namespace=team-b", wantStatus: http.StatusOK},
+		{name: "get in another namespace", method: http.MethodGet, target: "/views/" + viewID + "?namespace=team-a", wantStatus: http.StatusNotFound},
+		{name: "update authorized from the body", method: http.MethodPut, target: "/views/" + viewID, body: `{"name":"renamed","namespace":"team-a"}`, wantStatus: http.StatusNotFound},
+		{name: "delete in another namespace", method: http.MethodDelete, target: "/views/" + viewID + "?namespace=team-a", wantStatus: http.StatusNotFound},
+		{name: "no authorized namespace", method: http.MethodGet, target: "/unchecked/" + viewID + "?namespace=team-b", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+	if view := views.views[viewID]; view.Name != "team-b" {
+		t.Errorf("view name = %q, want it unchanged", view.Name)
+	}
+
+	// Views created without the namespace param are in the namespace the body was authorized for
+	req := httptest.NewRequest(http.MethodPost, "/views/", strings.NewReader(`{"name":"mine","namespace":"team-a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var created models.SavedView
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Namespace != "team-a" {
+		t.Errorf("namespace = %q, want %q", created.Namespace, "team-a")
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/konflux-ci/kite/internal/handlers/dto"
-	"github.com/konflux-ci/kite/internal/models"
 	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,25 +24,16 @@ func NewWebhookHandler(issueService *services.IssueService, logger *logrus.Logge
 	}
 }
 
-type PipelineFailureRequest struct {
-	PipelineName  string `json:"pipelineName" binding:"required"`
-	Namespace     string `json:"namespace" binding:"required"`
-	FailureReason string `json:"failureReason" binding:"required"`
-	RunID         string `json:"runId"`
-	LogsURL       string `json:"logsUrl"`
-}
-
-type PipelineSuccessRequest struct {
-	PipelineName string `json:"pipelineName" binding:"required"`
-	Namespace    string `json:"namespace" binding:"required"`
-}
-
 // PipelineFailure handles pipeline failure webhooks
 func (h *WebhookHandler) PipelineFailure(c *gin.Context) {
-	var req PipelineFailureRequest
+	var req dto.PipelineFailureRequest
 	// Check if the request binds to proper JSON, in the format specified
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required fileds", "details": err.Error()})
+		c.Error(invalidBody(err))
+		return
+	}
+	if err := checkBodyNamespace(c, req.Namespace); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -75,8 +66,7 @@ func (h *WebhookHandler) PipelineFailure(c *gin.Context) {
 	// Check for duplicates
 	duplicateResult, err := h.issueService.CheckForDuplicateIssue(c.Request.Context(), issueData)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to check for duplicate pipeline issues")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook"})
+		c.Error(fmt.Errorf("failed to check for duplicate pipeline issues: %w", err))
 		return
 	}
 
@@ -91,10 +81,9 @@ func (h *WebhookHandler) PipelineFailure(c *gin.Context) {
 			IssueType:   &issue.IssueType,
 			Links:       issueData.Links,
 		}
-		issue, err = h.issueService.UpdateIssue(c.Request.Context(), duplicateResult.ExistingIssue.ID, updateReq)
+		issue, err = h.issueService.UpdateIssue(c.Request.Context(), duplicateResult.ExistingIssue.ID, updateReq, 0)
 		if err != nil {
-			h.logger.WithError(err).Error("Failed to update existing pipeline issue")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process webhook"})
+			c.Error(fmt.Errorf("failed to update existing pipeline issue: %w", err))
 			return
 		}
 		h.logger.WithField("issue_id", duplicateResult.ExistingIssue.ID).Info("Updated existing pipeline issue")
@@ -102,8 +91,7 @@ func (h *WebhookHandler) PipelineFailure(c *gin.Context) {
 		// Create new issue
 		issue, err = h.issueService.CreateIssue(c.Request.Context(), issueData)
 		if err != nil {
-			h.logger.WithError(err).Error("Failed to create pipeline issue: %w", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process webhook"})
+			c.Error(fmt.Errorf("failed to create pipeline issue: %w", err))
 			return
 		}
 		h.logger.WithField("issue_id", issue.ID).Info("Created new pipeline issue")
@@ -117,16 +105,20 @@ func (h *WebhookHandler) PipelineFailure(c *gin.Context) {
 
 // PipelineSuccess handles pipeline success webhooks
 func (h *WebhookHandler) PipelineSuccess(c *gin.Context) {
-	var req PipelineSuccessRequest
+	var req dto.PipelineSuccessRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required fields", "details": err.Error()})
+		c.Error(invalidBody(err))
+		return
+	}
+	if err := checkBodyNamespace(c, req.Namespace); err != nil {
+		c.Error(err)
 		return
 	}
 
 	// Resolve any active issues for this pipeline
 	resolved, err := h.issueService.ResolveIssuesByScope(c.Request.Context(), "pipelinerun", req.PipelineName, req.Namespace)
 	if err != nil {
-		h.logger.WithError(err).Errorf("failed to resolve issues for pipeline run %s : %v", req.PipelineName, err)
+		c.Error(fmt.Errorf("failed to resolve issues for pipeline run %s: %w", req.PipelineName, err))
 		return
 	}
 
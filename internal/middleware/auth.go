@@ -1,29 +1,93 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/pkg/dto"
 	"github.com/sirupsen/logrus"
-	authv1 "k8s.io/api/authorization/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-// Kubernetes namespaces access checker
+// Operation is the kind of access to a namespace a request needs
+type Operation string
+
+const (
+	OperationRead  Operation = "read"
+	OperationWrite Operation = "write"
+)
+
+var (
+	// ErrUnauthenticated is returned when checking access for a request without a valid bearer token
+	ErrUnauthenticated = apperrors.New(apperrors.ErrUnauthenticated, "a valid bearer token is required")
+	// ErrAccessDenied is returned when the caller doesn't have access to the namespace
+	ErrAccessDenied = apperrors.New(apperrors.ErrForbidden, "access denied to this namespace")
+	// ErrUnknownToken is returned by authenticators for tokens they don't handle, which the next one can try
+	ErrUnknownToken = errors.New("unknown kind of token")
+)
+
+// Context key holding the namespace CheckNamespacessAccess checked the caller's access to
+const namespaceContextKey = "namespace"
+
+// Context key holding the principal authenticated by Authenticate
+type principalContextKey struct{}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Name   string
+	Groups []string
+	// How the principal was authenticated, such as kubernetes or oidc
+	AuthMethod string
+	// Issuer of the principal's token, for OIDC principals
+	Issuer string
+	// Checks the principal's access to namespaces
+	Authorizer Authorizer
+}
+
+// Authenticator identifies callers from their bearer tokens
+type Authenticator interface {
+	// AuthenticateToken returns the principal a token belongs to. Tokens the authenticator doesn't handle return
+	// ErrUnknownToken, and tokens that aren't valid return an error wrapping ErrUnauthenticated.
+	AuthenticateToken(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authorizer decides what a principal can access
+type Authorizer interface {
+	// Allowed returns whether the principal can perform the operation in the namespace
+	Allowed(ctx context.Context, namespace string, operation Operation) (bool, error)
+}
+
+// Namespaces access checker.
+// Callers are identified from their bearer token by each authenticator in turn, such as a Kubernetes TokenReview
+// or an OIDC issuer, and their access to a namespace is checked by the authorizer of the principal they're identified as.
 type NamespaceChecker struct {
-	client kubernetes.Interface
-	logger *logrus.Logger
+	authenticators []Authenticator
+	logger         *logrus.Logger
 }
 
-func NewNamespaceChecker(logger *logrus.Logger) (*NamespaceChecker, error) {
+// NewNamespaceChecker returns a checker accepting the tokens of the OIDC issuers configured and, when a Kubernetes
+// config is found, Kubernetes tokens. An error is returned when neither is available, since no caller could be
+// authenticated. Namespace checking can only be disabled explicitly, by not creating a checker.
+func NewNamespaceChecker(cfg config.AuthConfig, logger *logrus.Logger) (*NamespaceChecker, error) {
+	var authenticators []Authenticator
+	for _, issuer := range cfg.OIDC {
+		authenticators = append(authenticators, NewOIDCAuthenticator(issuer, logger))
+		logger.WithField("issuer", issuer.Issuer).Info("Accepting OIDC tokens")
+	}
+
 	// Try to create Kubernetes client
 
 	// Attempt to get project local kubeconfig
@@ -39,74 +103,154 @@ func NewNamespaceChecker(logger *logrus.Logger) (*NamespaceChecker, error) {
 	}
 
 	// Build config: prefer in-cluster -> local file -> default home
-	config, err := rest.InClusterConfig()
+	restConfig, err := rest.InClusterConfig()
 	if err != nil {
 		var cfgErr error
 		if kubeconfigPath != "" {
 			logger.Infof("Using project local kubeconfig: %s", kubeconfigPath)
-			config, cfgErr = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+			restConfig, cfgErr = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
 		} else {
 			logger.Info("No project local kubeconfig, falling back to ~/.kube/config")
-			config, cfgErr = clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+			restConfig, cfgErr = clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
 		}
 		if cfgErr != nil {
-			logger.WithError(cfgErr).Warn("Failed to create a Kubernetes client, namespace check disabled")
+			logger.WithError(cfgErr).Warn("Failed to create a Kubernetes client, Kubernetes token authentication disabled")
 		}
 	}
 
 	// Only create a clientset if we have a valid config
-	if config == nil {
-		logger.Warn("No valid kubernetes configuration found, namespace checking disabled")
-		return &NamespaceChecker{client: nil, logger: logger}, nil
+	if restConfig == nil {
+		logger.Warn("No valid kubernetes configuration found, Kubernetes token authentication disabled")
+	} else if clientset, k8sCsErr := kubernetes.NewForConfig(restConfig); k8sCsErr != nil {
+		logger.WithError(k8sCsErr).Warn("Failed to create Kubernetes clientset, Kubernetes token authentication disabled")
+	} else {
+		authenticators = append(authenticators, NewKubernetesAuthenticator(clientset, cfg, logger))
 	}
 
-	// Create clientset using config retrieved
-	clientset, k8sCsErr := kubernetes.NewForConfig(config)
-	if k8sCsErr != nil {
-		logger.WithError(k8sCsErr).Warn("Failed to create Kubernetes clientset, namespace checking disabled")
-		return &NamespaceChecker{client: nil, logger: logger}, nil
+	if len(authenticators) == 0 {
+		return nil, errors.New("no way to authenticate callers: no Kubernetes config was found and no OIDC issuers are configured " +
+			"(set FEATURE_NAMESPACE_CHECKING=false to let every caller access every namespace)")
 	}
+	return NewNamespaceCheckerWith(logger, authenticators...), nil
+}
 
-	return &NamespaceChecker{client: clientset, logger: logger}, nil
+// NewNamespaceCheckerWith returns a checker trying the authenticators passed in order.
+// Without authenticators namespace checking is disabled, as it is with a nil checker.
+func NewNamespaceCheckerWith(logger *logrus.Logger, authenticators ...Authenticator) *NamespaceChecker {
+	if len(authenticators) == 0 {
+		logger.Warn("No authenticators configured, namespace checking disabled")
+	}
+	return &NamespaceChecker{authenticators: authenticators, logger: logger}
+}
+
+// Helper function to check if namespace checking is disabled, it always is on a nil checker
+func (nc *NamespaceChecker) disabled() bool {
+	return nc == nil || len(nc.authenticators) == 0
 }
 
+// Authenticate identifies the caller from the bearer token in the Authorization header.
+// The principal takes precedence over the user forwarded by a trusted proxy. Requests without a token are anonymous,
+// and are denied access to namespaces unless namespace checking is disabled.
+func (nc *NamespaceChecker) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := BearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			c.Next()
+			return
+		}
+
+		principal, err := nc.AuthenticateToken(c.Request.Context(), token)
+		if err != nil {
+			nc.logger.WithError(err).Warn("Authentication failed")
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if principal != nil {
+			c.Request = c.Request.WithContext(WithPrincipal(c.Request.Context(), principal))
+		}
+		c.Next()
+	}
+}
+
+// BearerToken returns the token of an Authorization header value using the Bearer scheme
+func BearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// WithPrincipal returns a context holding the authenticated principal, for CheckAccess
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFrom returns the principal authenticated for a request, or nil if it's anonymous
+func PrincipalFrom(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
+
+// GetPrincipal returns the principal making the request, or nil if it's anonymous
+func GetPrincipal(c *gin.Context) *Principal {
+	return PrincipalFrom(c.Request.Context())
+}
+
+// AuthenticateToken returns the principal of the first authenticator that handles the token.
+// Returns an error wrapping ErrUnauthenticated if the token isn't valid, and a nil principal when namespace checking is disabled.
+func (nc *NamespaceChecker) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	if nc.disabled() {
+		return nil, nil
+	}
+
+	for _, authenticator := range nc.authenticators {
+		principal, err := authenticator.AuthenticateToken(ctx, token)
+		if errors.Is(err, ErrUnknownToken) {
+			continue
+		}
+		return principal, err
+	}
+	return nil, ErrUnauthenticated
+}
+
+// CheckNamespacessAccess checks the caller has access to the namespace of the request, and keeps it for handlers
+// to get with GetNamespace. Requests with safe methods need read access, others need write access.
+// The namespace is required even when namespace checking is disabled, including on a nil checker.
 func (nc *NamespaceChecker) CheckNamespacessAccess() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get namespaces from params, body or query
+		// Get namespaces from the path, query or body
 		namespace := c.Param("namespace")
 		if namespace == "" {
 			namespace = c.Query("namespace")
 		}
-		if namespace == "" {
-			// Try to get from request body
-			if c.Request.Method == "POST" || c.Request.Method == "PUT" {
-				if body, exists := c.Get("requestBody"); exists {
-					if bodyMap, ok := body.(map[string]interface{}); ok {
-						if ns, ok := bodyMap["namespace"].(string); ok {
-							namespace = ns
-						}
-					}
-				}
-			}
+		if namespace == "" && (c.Request.Method == http.MethodPost || c.Request.Method == http.MethodPut) {
+			namespace = bodyNamespace(c)
 		}
 
 		if namespace == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing namespace"})
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "missing namespace"))
 			c.Abort()
 			return
 		}
 
-		// If K8s client is not available, skip check
-		if nc.client == nil {
-			nc.logger.Debug("Kubernetes client not available, skipping namespace access check")
+		c.Set(namespaceContextKey, namespace)
+		if nc.disabled() {
 			c.Next()
 			return
 		}
 
-		// Check if user has access to the namespace by checking if they can get pods
-		if err := nc.checkPodAccess(namespace); err != nil {
+		operation := OperationWrite
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			operation = OperationRead
+		}
+
+		if err := nc.CheckAccess(c.Request.Context(), namespace, operation); err != nil {
 			nc.logger.WithError(err).WithField("namespace", namespace).Warn("Access Denied")
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to this namespace"})
+			c.Error(err)
 			c.Abort()
 			return
 		}
@@ -116,36 +260,50 @@ func (nc *NamespaceChecker) CheckNamespacessAccess() gin.HandlerFunc {
 	}
 }
 
-func (nc *NamespaceChecker) checkPodAccess(namespace string) error {
-	if nc.client == nil {
-		return nil // Skip check if client is not available
+// GetNamespace returns the namespace CheckNamespacessAccess checked the caller's access to,
+// or an empty string on routes it doesn't check
+func GetNamespace(c *gin.Context) string {
+	return c.GetString(namespaceContextKey)
+}
+
+// Helper function to get the namespace field of a JSON request body, leaving the body for the handler to read
+func bodyNamespace(c *gin.Context) string {
+	if c.ContentType() != "application/json" || c.Request.Body == nil {
+		return ""
 	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
 
-	// Create a SelfSubjectAccessReview to check if the user can get pods in the namespace
-	accessReview := &authv1.SelfSubjectAccessReview{
-		Spec: authv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authv1.ResourceAttributes{
-				Namespace: namespace,
-				Verb:      "get",
-				Resource:  "pods",
-			},
-		},
+	var req struct {
+		Namespace string `json:"namespace"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
 	}
+	return req.Namespace
+}
 
-	// Run the access review for max 10 seconds
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// CheckAccess returns an error if the principal authenticated for the context can't perform the operation in the namespace.
+// Access is always allowed when namespace checking is disabled, including on a nil checker.
+func (nc *NamespaceChecker) CheckAccess(ctx context.Context, namespace string, operation Operation) error {
+	if nc.disabled() {
+		return nil
+	}
 
-	result, err := nc.client.AuthorizationV1().SelfSubjectAccessReviews().Create(
-		ctx, accessReview, metav1.CreateOptions{})
+	principal := PrincipalFrom(ctx)
+	if principal == nil {
+		return ErrUnauthenticated
+	}
 
+	allowed, err := principal.Authorizer.Allowed(ctx, namespace, operation)
 	if err != nil {
 		return fmt.Errorf("failed to check namespace access: %w", err)
 	}
-
-	if !result.Status.Allowed {
-		return fmt.Errorf("access denied to namespace %s", namespace)
+	if !allowed {
+		return ErrAccessDenied
 	}
-
 	return nil
 }
@@ -1,24 +1,121 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
 	"github.com/sirupsen/logrus"
 )
 
-// ErrorHandler middleware for handling panics and errors
+// Status and title of the problems with each code, titles are the same for every problem with a code
+var problemKinds = map[string]struct {
+	status int
+	title  string
+}{
+	dto.CodeInvalidRequest:       {http.StatusBadRequest, "Invalid request"},
+	dto.CodeValidationFailed:     {http.StatusUnprocessableEntity, "Validation failed"},
+	dto.CodeUnauthorized:         {http.StatusUnauthorized, "Unauthorized"},
+	dto.CodeForbidden:            {http.StatusForbidden, "Forbidden"},
+	dto.CodeNotFound:             {http.StatusNotFound, "Not found"},
+	dto.CodeConflict:             {http.StatusConflict, "Conflict"},
+	dto.CodePreconditionFailed:   {http.StatusPreconditionFailed, "Precondition failed"},
+	dto.CodePayloadTooLarge:      {http.StatusRequestEntityTooLarge, "Payload too large"},
+	dto.CodeUnsupportedMediaType: {http.StatusUnsupportedMediaType, "Unsupported media type"},
+	dto.CodeUpstreamFailed:       {http.StatusBadGateway, "Upstream service failed"},
+	dto.CodeInternal:             {http.StatusInternalServerError, "Internal server error"},
+}
+
+// ErrorHandler responds to panics, and to the errors handlers add with c.Error, with RFC 7807 problem details.
+// Errors wrapping the kinds of errors in apperrors get the status of their kind,
+// anything else is logged and responded to with a generic 500.
 func ErrorHandler(logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
 				logger.WithField("error", err).Error("Panic recovered")
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": "Internal server error",
-				})
+				respondProblem(c, newProblem(c, dto.CodeInternal, ""))
 				c.Abort()
 			}
 		}()
 		c.Next()
+
+		// Handlers that already responded, such as streams that fail midway, are left as they are
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		problem := ProblemFor(c, err)
+		if problem.Status == http.StatusInternalServerError {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+			}).Error("Request failed")
+		}
+		respondProblem(c, problem)
 	}
 }
+
+// NoRoute responds to requests for routes that don't exist with a problem
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Error(apperrors.NewProblem(dto.CodeNotFound, "no route for "+c.Request.Method+" "+c.Request.URL.Path))
+	}
+}
+
+// ProblemFor returns the problem details ErrorHandler responds to an error with
+func ProblemFor(c *gin.Context, err error) dto.Problem {
+	// Errors of a kind describe the problem themselves, without what handlers wrapped them in
+	detail := err.Error()
+	var kindErr *apperrors.Error
+	if errors.As(err, &kindErr) {
+		detail = kindErr.Error()
+	}
+
+	var requestErr *apperrors.Problem
+	var validationErr *apperrors.ValidationError
+	switch {
+	case errors.As(err, &requestErr):
+		problem := newProblem(c, requestErr.Code, requestErr.Detail)
+		problem.Position = requestErr.Position
+		return problem
+	case errors.As(err, &validationErr):
+		problem := newProblem(c, dto.CodeValidationFailed, validationErr.Error())
+		problem.Errors = validationErr.Fields
+		return problem
+	case errors.Is(err, apperrors.ErrPreconditionFailed):
+		return newProblem(c, dto.CodePreconditionFailed, detail)
+	case errors.Is(err, apperrors.ErrNotFound):
+		return newProblem(c, dto.CodeNotFound, detail)
+	case errors.Is(err, apperrors.ErrConflict):
+		return newProblem(c, dto.CodeConflict, detail)
+	case errors.Is(err, apperrors.ErrUnauthenticated):
+		return newProblem(c, dto.CodeUnauthorized, err.Error())
+	case errors.Is(err, apperrors.ErrForbidden):
+		return newProblem(c, dto.CodeForbidden, detail)
+	}
+	// Internal errors can reveal details of the database, so they're only logged
+	return newProblem(c, dto.CodeInternal, "")
+}
+
+// Helper function to create the problem with a code for the request
+func newProblem(c *gin.Context, code, detail string) dto.Problem {
+	kind := problemKinds[code]
+	return dto.Problem{
+		Type:     dto.ProblemType(code),
+		Title:    kind.title,
+		Status:   kind.status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+	}
+}
+
+// Helper function to respond with a problem
+func respondProblem(c *gin.Context, problem dto.Problem) {
+	// Set before rendering, which keeps content types that are already set
+	c.Header("Content-Type", dto.ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
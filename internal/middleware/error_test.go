@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/sirupsen/logrus"
+)
+
+// Helper function to respond to a request with ErrorHandler, returning the response and what was logged
+func serveError(t *testing.T, handler gin.HandlerFunc, body string) (*httptest.ResponseRecorder, dto.Problem, string) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+
+	router := gin.New()
+	router.Use(ErrorHandler(logger))
+	router.POST("/issues", handler)
+	router.NoRoute(NoRoute())
+
+	target := "/issues"
+	if handler == nil {
+		target = "/missing"
+	}
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var problem dto.Problem
+	if strings.HasPrefix(rec.Header().Get("Content-Type"), dto.ProblemContentType) {
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("response isn't a problem: %v\n%s", err, rec.Body)
+		}
+	}
+	return rec, problem, logs.String()
+}
+
+func TestErrorHandlerKinds(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{
+			name:       "not found, without what it was wrapped in",
+			err:        fmt.Errorf("failed to fetch issue: %w", apperrors.New(apperrors.ErrNotFound, "issue not found")),
+			wantStatus: http.StatusNotFound, wantCode: dto.CodeNotFound, wantDetail: "issue not found",
+		},
+		{
+			name:       "conflict",
+			err:        apperrors.New(apperrors.ErrConflict, "relationship already exists"),
+			wantStatus: http.StatusConflict, wantCode: dto.CodeConflict, wantDetail: "relationship already exists",
+		},
+		{
+			name:       "precondition failed",
+			err:        fmt.Errorf("failed to update issue: %w", apperrors.New(apperrors.ErrPreconditionFailed, "the issue has been changed")),
+			wantStatus: http.StatusPreconditionFailed, wantCode: dto.CodePreconditionFailed, wantDetail: "the issue has been changed",
+		},
+		{
+			name:       "forbidden",
+			err:        apperrors.New(apperrors.ErrForbidden, "access denied to this namespace"),
+			wantStatus: http.StatusForbidden, wantCode: dto.CodeForbidden, wantDetail: "access denied to this namespace",
+		},
+		{
+			name:       "unauthenticated",
+			err:        ErrUnauthenticated,
+			wantStatus: http.StatusUnauthorized, wantCode: dto.CodeUnauthorized, wantDetail: ErrUnauthenticated.Error(),
+		},
+		{
+			name:       "problem",
+			err:        apperrors.NewProblem(dto.CodeUnsupportedMediaType, "expected application/merge-patch+json"),
+			wantStatus: http.StatusUnsupportedMediaType, wantCode: dto.CodeUnsupportedMediaType, wantDetail: "expected application/merge-patch+json",
+		},
+		{
+			name:       "validation",
+			err:        fmt.Errorf("failed to create issue: %w", apperrors.InvalidField("resolvedAt", "is before detectedAt")),
+			wantStatus: http.StatusUnprocessableEntity, wantCode: dto.CodeValidationFailed, wantDetail: "resolvedAt: is before detectedAt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, problem, logs := serveError(t, func(c *gin.Context) { c.Error(tt.err) }, "")
+
+			if rec.Code != tt.wantStatus || problem.Status != tt.wantStatus {
+				t.Errorf("status = %d, problem status %d, want %d", rec.Code, problem.Status, tt.wantStatus)
+			}
+			if problem.Code != tt.wantCode || problem.Type != dto.ProblemType(tt.wantCode) || problem.Detail != tt.wantDetail {
+				t.Errorf("problem = %+v, want code %s and detail %q", problem, tt.wantCode, tt.wantDetail)
+			}
+			if problem.Title == "" || problem.Instance != "/issues" {
+				t.Errorf("problem = %+v, want a title and the request path", problem)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, dto.ProblemContentType) {
+				t.Errorf("Content-Type = %q, want %s", got, dto.ProblemContentType)
+			}
+			if logs != "" {
+				t.Errorf("logged a client error: %s", logs)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerProblemFields(t *testing.T) {
+	position := 7
+	_, problem, _ := serveError(t, func(c *gin.Context) {
+		c.Error(&apperrors.Problem{Code: dto.CodeInvalidRequest, Detail: "unexpected )", Position: &position})
+	}, "")
+	if problem.Position == nil || *problem.Position != 7 {
+		t.Errorf("position = %v, want 7", problem.Position)
+	}
+
+	_, problem, _ = serveError(t, func(c *gin.Context) {
+		c.Error(&apperrors.ValidationError{Fields: []dto.FieldError{{Field: "title", Message: "is required"}, {Message: "too many links"}}})
+	}, "")
+	if len(problem.Errors) != 2 || problem.Errors[0].Field != "title" || problem.Errors[1].Message != "too many links" {
+		t.Errorf("errors = %+v, want each field's problem", problem.Errors)
+	}
+}
+
+func TestErrorHandlerBindingErrors(t *testing.T) {
+	type request struct {
+		Title    string `json:"title" binding:"required"`
+		Severity string `json:"severity" binding:"oneof=info minor major critical"`
+	}
+	// Handlers report binding errors like this, values that fail validation apart from bodies that can't be read
+	handler := func(c *gin.Context) {
+		var req request
+		if err := c.ShouldBindJSON(&req); err != nil {
+			if converted := services.NewValidationError(err); errors.Is(converted, apperrors.ErrValidation) {
+				c.Error(converted)
+				return
+			}
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "invalid request body: "+err.Error()))
+		}
+	}
+
+	rec, problem, _ := serveError(t, handler, `{"severity":"blocker"}`)
+	if rec.Code != http.StatusUnprocessableEntity || problem.Code != dto.CodeValidationFailed {
+		t.Fatalf("status = %d, problem %+v, want a validation failure", rec.Code, problem)
+	}
+	// Fields are named the way the client sent them
+	var fields []string
+	for _, fieldErr := range problem.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	if strings.Join(fields, ",") != "title,severity" {
+		t.Errorf("errors = %+v, want title and severity", problem.Errors)
+	}
+
+	rec, problem, _ = serveError(t, handler, `{"title":`)
+	if rec.Code != http.StatusBadRequest || problem.Code != dto.CodeInvalidRequest || problem.Detail != "invalid request body: unexpected EOF" {
+		t.Errorf("status = %d, problem %+v, want an invalid body", rec.Code, problem)
+	}
+}
+
+func TestErrorHandlerHidesInternalErrors(t *testing.T) {
+	const internal = `failed to count issues: pq: relation "issue_scopes" does not exist`
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+	}{
+		{name: "error", handler: func(c *gin.Context) { c.Error(fmt.Errorf("failed to list issues: %w", errors.New(internal))) }},
+		{name: "panic", handler: func(c *gin.Context) { panic(internal) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, problem, logs := serveError(t, tt.handler, "")
+
+			if rec.Code != http.StatusInternalServerError || problem.Code != dto.CodeInternal || problem.Detail != "" {
+				t.Errorf("status = %d, problem %+v, want a 500 without details", rec.Code, problem)
+			}
+			if strings.Contains(rec.Body.String(), "issue_scopes") {
+				t.Errorf("response leaked the internal error: %s", rec.Body)
+			}
+			// The error is only logged
+			if !strings.Contains(logs, "issue_scopes") {
+				t.Errorf("internal error wasn't logged: %s", logs)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerKeepsWrittenResponses(t *testing.T) {
+	rec, _, _ := serveError(t, func(c *gin.Context) {
+		c.String(http.StatusOK, "data: partial\n\n")
+		c.Error(errors.New("stream broke"))
+	}, "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "data: partial\n\n" {
+		t.Errorf("response = %d %q, want what the handler wrote", rec.Code, rec.Body)
+	}
+}
+
+func TestNoRoute(t *testing.T) {
+	rec, problem, _ := serveError(t, nil, "")
+	if rec.Code != http.StatusNotFound || problem.Detail != "no route for POST /missing" {
+		t.Errorf("status = %d, problem %+v, want not found", rec.Code, problem)
+	}
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/cache"
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/sirupsen/logrus"
+	authnv1 "k8s.io/api/authentication/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Key of the access reviews cache, one per user, namespace and operation
+type accessKey struct {
+	user      string
+	namespace string
+	operation Operation
+}
+
+// KubernetesAuthenticator authenticates Kubernetes tokens with a TokenReview, and checks the access of their users to
+// namespaces with a SubjectAccessReview of the resource and verb configured for the operation.
+// It handles every token, so it goes last when other authenticators are used.
+type KubernetesAuthenticator struct {
+	client kubernetes.Interface
+	access map[Operation]config.ResourceAccess
+	// Users of the tokens reviewed, keyed by the token hash, nil for tokens that aren't valid
+	tokens *cache.TTL[string, *authnv1.UserInfo]
+	// Whether access reviews allowed the operation
+	reviews *cache.TTL[accessKey, bool]
+	logger  *logrus.Logger
+}
+
+// NewKubernetesAuthenticator returns an authenticator making reviews with the client passed, such as a fake clientset
+func NewKubernetesAuthenticator(client kubernetes.Interface, cfg config.AuthConfig, logger *logrus.Logger) *KubernetesAuthenticator {
+	return &KubernetesAuthenticator{
+		client:  client,
+		access:  map[Operation]config.ResourceAccess{OperationRead: cfg.Read, OperationWrite: cfg.Write},
+		tokens:  cache.NewTTL[string, *authnv1.UserInfo](cfg.CacheTTL, cfg.CacheSize),
+		reviews: cache.NewTTL[accessKey, bool](cfg.CacheTTL, cfg.CacheSize),
+		logger:  logger,
+	}
+}
+
+// AuthenticateToken returns the user a token belongs to, from a TokenReview
+func (k *KubernetesAuthenticator) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	// Tokens are only kept hashed
+	hash := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(hash[:])
+	user, ok := k.tokens.Get(key)
+	if !ok {
+		var err error
+		if user, err = k.reviewToken(ctx, token); err != nil {
+			return nil, err
+		}
+		k.tokens.Set(key, user)
+	}
+
+	if user == nil {
+		return nil, ErrUnauthenticated
+	}
+	return &Principal{
+		Name:       user.Username,
+		Groups:     user.Groups,
+		AuthMethod: "kubernetes",
+		Authorizer: &kubernetesAuthorizer{authenticator: k, user: user},
+	}, nil
+}
+
+// Helper function to get the user of a token with a TokenReview, nil if the token isn't valid
+func (k *KubernetesAuthenticator) reviewToken(ctx context.Context, token string) (*authnv1.UserInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	review := &authnv1.TokenReview{Spec: authnv1.TokenReviewSpec{Token: token}}
+	result, err := k.client.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review token: %w", err)
+	}
+
+	if !result.Status.Authenticated {
+		return nil, nil
+	}
+	return &result.Status.User, nil
+}
+
+// kubernetesAuthorizer checks the access of a Kubernetes user with SubjectAccessReviews
+type kubernetesAuthorizer struct {
+	authenticator *KubernetesAuthenticator
+	user          *authnv1.UserInfo
+}
+
+func (a *kubernetesAuthorizer) Allowed(ctx context.Context, namespace string, operation Operation) (bool, error) {
+	// Extra holds things like the scopes of the token, which limit what the user can do with it
+	key := accessKey{
+		user:      fmt.Sprintf("%q %q %q %v", a.user.Username, a.user.UID, a.user.Groups, a.user.Extra),
+		namespace: namespace,
+		operation: operation,
+	}
+	if allowed, ok := a.authenticator.reviews.Get(key); ok {
+		return allowed, nil
+	}
+
+	allowed, err := a.reviewAccess(ctx, namespace, operation)
+	if err != nil {
+		return false, err
+	}
+	a.authenticator.reviews.Set(key, allowed)
+	return allowed, nil
+}
+
+// Helper function to check if the user can perform an operation in a namespace with a SubjectAccessReview
+func (a *kubernetesAuthorizer) reviewAccess(ctx context.Context, namespace string, operation Operation) (bool, error) {
+	access := a.authenticator.access[operation]
+	extra := make(map[string]authv1.ExtraValue, len(a.user.Extra))
+	for name, values := range a.user.Extra {
+		extra[name] = authv1.ExtraValue(values)
+	}
+
+	accessReview := &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      access.Verb,
+				Group:     access.Group,
+				Resource:  access.Resource,
+			},
+			User:   a.user.Username,
+			UID:    a.user.UID,
+			Groups: a.user.Groups,
+			Extra:  extra,
+		},
+	}
+
+	// Run the access review for max 10 seconds
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := a.authenticator.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, accessReview, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to review access: %w", err)
+	}
+	return result.Status.Allowed, nil
+}
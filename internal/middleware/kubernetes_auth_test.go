@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/sirupsen/logrus"
+	authnv1 "k8s.io/api/authentication/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeCluster answers token and access reviews, counting how many it's asked for
+type fakeCluster struct {
+	// Users of the valid tokens
+	users map[string]string
+	// Namespaces each user can read and write
+	readers, writers map[string][]string
+	// Returned instead of an answer when set
+	tokenReviewErr, accessReviewErr error
+
+	tokenReviews, accessReviews int
+}
+
+func (f *fakeCluster) clientset() *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		f.tokenReviews++
+		if f.tokenReviewErr != nil {
+			return true, nil, f.tokenReviewErr
+		}
+		review := action.(k8stesting.CreateAction).GetObject().(*authnv1.TokenReview)
+		if user, ok := f.users[review.Spec.Token]; ok {
+			review.Status = authnv1.TokenReviewStatus{Authenticated: true, User: authnv1.UserInfo{Username: user}}
+		}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		f.accessReviews++
+		if f.accessReviewErr != nil {
+			return true, nil, f.accessReviewErr
+		}
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		namespaces := f.readers[review.Spec.User]
+		if attributes.Verb == "create" && attributes.Resource == "pipelineruns" {
+			namespaces = f.writers[review.Spec.User]
+		}
+		for _, namespace := range namespaces {
+			review.Status.Allowed = review.Status.Allowed || namespace == attributes.Namespace
+		}
+		return true, review, nil
+	})
+	return clientset
+}
+
+func testAuthConfig() config.AuthConfig {
+	return config.AuthConfig{
+		Read:      config.ResourceAccess{Resource: "pods", Verb: "get"},
+		Write:     config.ResourceAccess{Group: "tekton.dev", Resource: "pipelineruns", Verb: "create"},
+		CacheTTL:  time.Minute,
+		CacheSize: 100,
+	}
+}
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// Helper function to make a request through a checker to a route in a namespace
+func checkedRequest(checker *NamespaceChecker, method, token string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(testLogger()), checker.Authenticate())
+	router.Handle(method, "/namespaces/:namespace/issues", checker.CheckNamespacessAccess(), func(c *gin.Context) {
+		c.String(http.StatusOK, GetUser(c))
+	})
+
+	req := httptest.NewRequest(method, "/namespaces/team-a/issues", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestKubernetesAuthenticatorAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		token      string
+		cluster    fakeCluster
+		wantStatus int
+	}{
+		{
+			name:       "reader can read",
+			method:     http.MethodGet,
+			token:      "reader-token",
+			cluster:    fakeCluster{readers: map[string][]string{"reader": {"team-a"}}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "reader can't write",
+			method:     http.MethodPost,
+			token:      "reader-token",
+			cluster:    fakeCluster{readers: map[string][]string{"reader": {"team-a"}}},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "writer can write",
+			method:     http.MethodPost,
+			token:      "reader-token",
+			cluster:    fakeCluster{writers: map[string][]string{"reader": {"team-a"}}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "other namespace is denied",
+			method:     http.MethodGet,
+			token:      "reader-token",
+			cluster:    fakeCluster{readers: map[string][]string{"reader": {"team-b"}}},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "invalid token",
+			method:     http.MethodGet,
+			token:      "forged-token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no token",
+			method:     http.MethodGet,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "token review fails",
+			method:     http.MethodGet,
+			token:      "reader-token",
+			cluster:    fakeCluster{tokenReviewErr: errors.New("apiserver unavailable")},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "access review fails",
+			method:     http.MethodGet,
+			token:      "reader-token",
+			cluster:    fakeCluster{accessReviewErr: errors.New("apiserver unavailable")},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cluster.users = map[string]string{"reader-token": "reader"}
+			authenticator := NewKubernetesAuthenticator(tt.cluster.clientset(), testAuthConfig(), testLogger())
+			checker := NewNamespaceCheckerWith(testLogger(), authenticator)
+
+			rec := checkedRequest(checker, tt.method, tt.token)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code == http.StatusOK && rec.Body.String() != "reader" {
+				t.Errorf("user = %q, want reader", rec.Body)
+			}
+			// Failures of the cluster aren't reported to callers
+			if rec.Code == http.StatusInternalServerError && strings.Contains(rec.Body.String(), "apiserver") {
+				t.Errorf("review error leaked to the caller: %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestKubernetesAuthenticatorCachesReviews(t *testing.T) {
+	cluster := &fakeCluster{
+		users:   map[string]string{"reader-token": "reader"},
+		readers: map[string][]string{"reader": {"team-a"}},
+	}
+	authenticator := NewKubernetesAuthenticator(cluster.clientset(), testAuthConfig(), testLogger())
+	checker := NewNamespaceCheckerWith(testLogger(), authenticator)
+
+	for range 3 {
+		if rec := checkedRequest(checker, http.MethodGet, "reader-token"); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		if rec := checkedRequest(checker, http.MethodGet, "forged-token"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want 401: %s", rec.Code, rec.Body)
+		}
+	}
+	if cluster.tokenReviews != 2 || cluster.accessReviews != 1 {
+		t.Errorf("made %d token reviews and %d access reviews, want 2 and 1", cluster.tokenReviews, cluster.accessReviews)
+	}
+}
+
+func TestKubernetesAuthenticatorDoesntCacheFailures(t *testing.T) {
+	cluster := &fakeCluster{
+		users:          map[string]string{"reader-token": "reader"},
+		readers:        map[string][]string{"reader": {"team-a"}},
+		tokenReviewErr: errors.New("apiserver unavailable"),
+	}
+	authenticator := NewKubernetesAuthenticator(cluster.clientset(), testAuthConfig(), testLogger())
+	checker := NewNamespaceCheckerWith(testLogger(), authenticator)
+
+	if rec := checkedRequest(checker, http.MethodGet, "reader-token"); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500: %s", rec.Code, rec.Body)
+	}
+	cluster.tokenReviewErr = nil
+	if rec := checkedRequest(checker, http.MethodGet, "reader-token"); rec.Code != http.StatusOK {
+		t.Errorf("status = %d after the apiserver recovered, want 200: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestNewNamespaceCheckerFailsWithoutAuthenticators(t *testing.T) {
+	// Neither a project local kubeconfig nor one in the home directory exists
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	if _, err := NewNamespaceChecker(testAuthConfig(), testLogger()); err == nil {
+		t.Error("NewNamespaceChecker() succeeded without a way to authenticate callers")
+	}
+}
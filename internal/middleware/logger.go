@@ -30,6 +30,14 @@ func Logger(logger *logrus.Logger) gin.HandlerFunc {
 			"user_agent": c.Request.UserAgent(),
 		})
 
+		// Audit who made the request, the principal is set by Authenticate further down the chain
+		if principal := GetPrincipal(c); principal != nil {
+			logEntry = logEntry.WithFields(logrus.Fields{
+				"principal":   principal.Name,
+				"auth_method": principal.AuthMethod,
+			})
+		}
+
 		if statusCode >= 400 {
 			logEntry.Warn("HTTP Request")
 		} else {
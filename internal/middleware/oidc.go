@@ -0,0 +1,293 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// How long keys are used before the key set is fetched again
+	jwksMaxAge = time.Hour
+	// Least time between fetches of the key set, so tokens signed with unknown keys can't cause a fetch each
+	jwksMinRefresh = time.Minute
+	// Allowed clock skew between the provider and us
+	oidcLeeway = 30 * time.Second
+)
+
+// Signing algorithms accepted for ID tokens, unsigned and HMAC tokens never are
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// OIDCAuthenticator authenticates the ID tokens of an OpenID Connect issuer, verifying them against the issuer's JWKS.
+// Principals get the access to namespaces the issuer's config maps their groups to.
+type OIDCAuthenticator struct {
+	config config.OIDCIssuerConfig
+	keys   *keySet
+	logger *logrus.Logger
+}
+
+// NewOIDCAuthenticator returns an authenticator for the tokens of an issuer
+func NewOIDCAuthenticator(cfg config.OIDCIssuerConfig, logger *logrus.Logger) *OIDCAuthenticator {
+	return &OIDCAuthenticator{
+		config: cfg,
+		keys: &keySet{
+			issuer: cfg.Issuer,
+			url:    cfg.JWKSURL,
+			client: &http.Client{Timeout: 10 * time.Second},
+		},
+		logger: logger,
+	}
+}
+
+// AuthenticateToken returns the principal of an ID token. Tokens that aren't JWTs from the issuer return ErrUnknownToken.
+func (o *OIDCAuthenticator) AuthenticateToken(ctx context.Context, token string) (*Principal, error) {
+	// The issuer is only read to find the authenticator for the token, it's checked again once verified
+	var unverified jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &unverified); err != nil || unverified.Issuer != o.config.Issuer {
+		return nil, ErrUnknownToken
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return o.keys.key(ctx, kid)
+	},
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(o.config.Issuer),
+		jwt.WithAudience(o.config.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(oidcLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	username, _ := claims[o.config.UsernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("%w: the %s claim is missing", ErrUnauthenticated, o.config.UsernameClaim)
+	}
+	groups := stringsClaim(claims[o.config.GroupsClaim])
+
+	return &Principal{
+		Name:       o.config.UsernamePrefix + username,
+		Groups:     groups,
+		AuthMethod: "oidc",
+		Issuer:     o.config.Issuer,
+		Authorizer: groupAuthorizer(o.groupAccess(groups)),
+	}, nil
+}
+
+// Helper function to get the access to each namespace the groups are mapped to, write access includes read access
+func (o *OIDCAuthenticator) groupAccess(groups []string) map[string]Operation {
+	access := make(map[string]Operation)
+	for _, group := range groups {
+		groupAccess, ok := o.config.Groups[group]
+		if !ok {
+			continue
+		}
+		for _, namespace := range groupAccess.Namespaces {
+			if access[namespace] != OperationWrite {
+				access[namespace] = Operation(groupAccess.Access)
+			}
+		}
+	}
+	return access
+}
+
+// Helper function to read a claim holding a list of strings, or a single string
+func stringsClaim(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []any:
+		values := make([]string, 0, len(claim))
+		for _, value := range claim {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// groupAuthorizer allows the operations mapped from a principal's groups to each namespace
+type groupAuthorizer map[string]Operation
+
+func (a groupAuthorizer) Allowed(_ context.Context, namespace string, operation Operation) (bool, error) {
+	access, ok := a[namespace]
+	return ok && (operation == OperationRead || access == OperationWrite), nil
+}
+
+// keySet is the JSON Web Key Set of an issuer, fetched when it's first needed and again when keys are rotated
+type keySet struct {
+	issuer string
+	// Found with OIDC discovery when it's not configured
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+// Helper function to get the key with an ID, fetching the key set if it's unknown or old.
+// Tokens without a key ID can only be verified when the set has a single key.
+func (s *keySet) key(ctx context.Context, kid string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[kid]
+	if (!ok || time.Since(s.fetchedAt) > jwksMaxAge) && time.Since(s.fetchedAt) > jwksMinRefresh {
+		if err := s.fetch(ctx); err != nil {
+			// Keys still in use don't stop working when the provider can't be reached
+			if ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		key, ok = s.keys[kid]
+	}
+	if !ok && kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, nil
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// Helper function to fetch the keys of the set, replacing the ones we have
+func (s *keySet) fetch(ctx context.Context) error {
+	s.fetchedAt = time.Now()
+	if s.url == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := s.get(ctx, strings.TrimSuffix(s.issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return fmt.Errorf("failed to discover the JWKS of %s: %w", s.issuer, err)
+		}
+		if discovery.JWKSURI == "" {
+			return fmt.Errorf("no jwks_uri in the OIDC discovery document of %s", s.issuer)
+		}
+		s.url = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := s.get(ctx, s.url, &set); err != nil {
+		return fmt.Errorf("failed to fetch the JWKS of %s: %w", s.issuer, err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		// Keys for encryption, and of types we don't know, are skipped
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	s.keys = keys
+	return nil
+}
+
+// Helper function to get a JSON document
+func (s *keySet) get(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jsonWebKey is a public key in a JWKS (RFC 7517)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC and OKP keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Helper function to convert a JWK to the public key type the jwt package verifies signatures with
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("EC point isn't on the curve")
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
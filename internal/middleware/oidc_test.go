@@ -0,0 +1,286 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/konflux-ci/kite/internal/config"
+)
+
+// fakeIssuer is an OIDC provider serving discovery and a JWKS of the keys it signs with
+type fakeIssuer struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PrivateKey
+	jwksFetches int
+}
+
+func newFakeIssuer(t *testing.T, kids ...string) *fakeIssuer {
+	issuer := &fakeIssuer{keys: map[string]*rsa.PrivateKey{}}
+	for _, kid := range kids {
+		issuer.addKey(t, kid)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.url(), "jwks_uri": issuer.url() + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		issuer.jwksFetches++
+		var keys []map[string]string
+		for kid, key := range issuer.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (f *fakeIssuer) url() string {
+	return f.server.URL
+}
+
+func (f *fakeIssuer) addKey(t *testing.T, kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[kid] = key
+	return key
+}
+
+func (f *fakeIssuer) fetches() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jwksFetches
+}
+
+// Helper function to sign a token with a key of the issuer, claims override the defaults of a valid token
+func (f *fakeIssuer) token(t *testing.T, kid string, claims jwt.MapClaims) string {
+	f.mu.Lock()
+	key := f.keys[kid]
+	f.mu.Unlock()
+	return signToken(t, key, kid, f.url(), claims)
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid, issuer string, claims jwt.MapClaims) string {
+	all := jwt.MapClaims{
+		"iss":    issuer,
+		"aud":    "kite",
+		"sub":    "alice",
+		"groups": []string{"team-a-admins"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"iat":    time.Now().Unix(),
+	}
+	for name, value := range claims {
+		all[name] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, all)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func issuerConfig(issuer string) config.OIDCIssuerConfig {
+	return config.OIDCIssuerConfig{
+		Issuer:         issuer,
+		Audience:       "kite",
+		UsernameClaim:  "sub",
+		UsernamePrefix: "oidc:",
+		GroupsClaim:    "groups",
+		Groups: map[string]config.GroupAccess{
+			"team-a-admins":  {Namespaces: []string{"team-a"}, Access: "write"},
+			"team-b-viewers": {Namespaces: []string{"team-b"}, Access: "read"},
+		},
+	}
+}
+
+func TestOIDCAuthenticatorTokens(t *testing.T) {
+	issuer := newFakeIssuer(t, "k1")
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid token", token: issuer.token(t, "k1", nil)},
+		{name: "signed by another key", token: signToken(t, otherKey, "k1", issuer.url(), nil), wantErr: ErrUnauthenticated},
+		{name: "wrong audience", token: issuer.token(t, "k1", jwt.MapClaims{"aud": "other-app"}), wantErr: ErrUnauthenticated},
+		{name: "expired", token: issuer.token(t, "k1", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}), wantErr: ErrUnauthenticated},
+		{name: "without expiry", token: issuer.token(t, "k1", jwt.MapClaims{"exp": nil}), wantErr: ErrUnauthenticated},
+		{name: "without username", token: issuer.token(t, "k1", jwt.MapClaims{"sub": nil}), wantErr: ErrUnauthenticated},
+		{name: "unknown key", token: signToken(t, otherKey, "k9", issuer.url(), nil), wantErr: ErrUnauthenticated},
+		{name: "other issuer", token: signToken(t, otherKey, "k1", "https://other.example.com", nil), wantErr: ErrUnknownToken},
+		{name: "not a JWT", token: "sha256~kubernetes-token", wantErr: ErrUnknownToken},
+	}
+
+	authenticator := NewOIDCAuthenticator(issuerConfig(issuer.url()), testLogger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.AuthenticateToken(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("AuthenticateToken() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AuthenticateToken() error = %v", err)
+			}
+			if principal.Name != "oidc:alice" || principal.AuthMethod != "oidc" || principal.Issuer != issuer.url() {
+				t.Errorf("principal = %+v", principal)
+			}
+		})
+	}
+}
+
+func TestOIDCAuthenticatorUnsignedToken(t *testing.T) {
+	issuer := newFakeIssuer(t, "k1")
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"iss": issuer.url(), "aud": "kite", "sub": "alice", "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	unsigned, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator := NewOIDCAuthenticator(issuerConfig(issuer.url()), testLogger())
+	if _, err := authenticator.AuthenticateToken(context.Background(), unsigned); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("AuthenticateToken() error = %v, want %v", err, ErrUnauthenticated)
+	}
+}
+
+func TestOIDCAuthenticatorGroupAccess(t *testing.T) {
+	issuer := newFakeIssuer(t, "k1")
+	authenticator := NewOIDCAuthenticator(issuerConfig(issuer.url()), testLogger())
+	token := issuer.token(t, "k1", jwt.MapClaims{"groups": []string{"team-a-admins", "team-b-viewers", "unmapped"}})
+
+	principal, err := authenticator.AuthenticateToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("AuthenticateToken() error = %v", err)
+	}
+
+	tests := []struct {
+		namespace string
+		operation Operation
+		want      bool
+	}{
+		{"team-a", OperationRead, true},
+		{"team-a", OperationWrite, true},
+		{"team-b", OperationRead, true},
+		{"team-b", OperationWrite, false},
+		{"team-c", OperationRead, false},
+	}
+	for _, tt := range tests {
+		allowed, err := principal.Authorizer.Allowed(context.Background(), tt.namespace, tt.operation)
+		if err != nil || allowed != tt.want {
+			t.Errorf("Allowed(%s, %s) = %v, %v, want %v", tt.namespace, tt.operation, allowed, err, tt.want)
+		}
+	}
+}
+
+func TestOIDCAuthenticatorKeyRotation(t *testing.T) {
+	issuer := newFakeIssuer(t, "k1")
+	authenticator := NewOIDCAuthenticator(issuerConfig(issuer.url()), testLogger())
+	ctx := context.Background()
+
+	if _, err := authenticator.AuthenticateToken(ctx, issuer.token(t, "k1", nil)); err != nil {
+		t.Fatalf("AuthenticateToken() error = %v", err)
+	}
+	if _, err := authenticator.AuthenticateToken(ctx, issuer.token(t, "k1", nil)); err != nil {
+		t.Fatalf("AuthenticateToken() error = %v", err)
+	}
+	if fetches := issuer.fetches(); fetches != 1 {
+		t.Errorf("fetched the JWKS %d times for known keys, want 1", fetches)
+	}
+
+	// A new key is picked up once the key set may be fetched again
+	issuer.addKey(t, "k2")
+	rotated := issuer.token(t, "k2", nil)
+	if _, err := authenticator.AuthenticateToken(ctx, rotated); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("AuthenticateToken() error = %v right after a fetch, want %v", err, ErrUnauthenticated)
+	}
+	authenticator.keys.mu.Lock()
+	authenticator.keys.fetchedAt = time.Now().Add(-2 * jwksMinRefresh)
+	authenticator.keys.mu.Unlock()
+	if _, err := authenticator.AuthenticateToken(ctx, rotated); err != nil {
+		t.Errorf("AuthenticateToken() error = %v with a rotated key", err)
+	}
+
+	// Tokens with unknown keys don't cause a fetch each
+	unknownKey := signToken(t, issuer.addKey(t, "k3"), "k9", issuer.url(), nil)
+	for range 5 {
+		if _, err := authenticator.AuthenticateToken(ctx, unknownKey); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("AuthenticateToken() error = %v with an unknown key, want %v", err, ErrUnauthenticated)
+		}
+	}
+	if fetches := issuer.fetches(); fetches != 2 {
+		t.Errorf("fetched the JWKS %d times, want 2", fetches)
+	}
+}
+
+// stubAuthenticator handles every token, like the Kubernetes authenticator
+type stubAuthenticator struct {
+	tokens []string
+}
+
+func (s *stubAuthenticator) AuthenticateToken(_ context.Context, token string) (*Principal, error) {
+	s.tokens = append(s.tokens, token)
+	return &Principal{Name: "system:serviceaccount:team-a:builder", AuthMethod: "kubernetes"}, nil
+}
+
+func TestNamespaceCheckerFallsBackForOtherTokens(t *testing.T) {
+	issuer := newFakeIssuer(t, "k1")
+	fallback := &stubAuthenticator{}
+	checker := NewNamespaceCheckerWith(testLogger(), NewOIDCAuthenticator(issuerConfig(issuer.url()), testLogger()), fallback)
+	ctx := context.Background()
+
+	principal, err := checker.AuthenticateToken(ctx, issuer.token(t, "k1", nil))
+	if err != nil || principal.AuthMethod != "oidc" {
+		t.Errorf("AuthenticateToken() = %+v, %v for an ID token, want an OIDC principal", principal, err)
+	}
+
+	principal, err = checker.AuthenticateToken(ctx, "sha256~kubernetes-token")
+	if err != nil || principal.AuthMethod != "kubernetes" {
+		t.Errorf("AuthenticateToken() = %+v, %v for a Kubernetes token, want a Kubernetes principal", principal, err)
+	}
+
+	// ID tokens of the issuer that aren't valid aren't passed on
+	if _, err := checker.AuthenticateToken(ctx, issuer.token(t, "k1", jwt.MapClaims{"aud": "other-app"})); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("AuthenticateToken() error = %v, want %v", err, ErrUnauthenticated)
+	}
+	if len(fallback.tokens) != 1 || fallback.tokens[0] != "sha256~kubernetes-token" {
+		t.Errorf("fallback authenticator got %v, want only the Kubernetes token", fallback.tokens)
+	}
+}
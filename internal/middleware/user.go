@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Header set by the authenticating proxy in front of the API
+	forwardedUserHeader = "X-Forwarded-User"
+	// Context key holding the name of the user forwarded by a trusted proxy
+	userContextKey = "user"
+)
+
+// ForwardedUser identifies the user making the request from the header set by the authenticating proxy.
+// The header is only honored on connections from the trusted proxies, given as IP addresses or CIDR ranges,
+// since anyone else could set it to any user. Requests without it are anonymous.
+func ForwardedUser(trustedProxies []string) gin.HandlerFunc {
+	trusted := parseTrustedProxies(trustedProxies)
+	return func(c *gin.Context) {
+		user := strings.TrimSpace(c.GetHeader(forwardedUserHeader))
+		if user != "" && isTrustedProxy(trusted, c.Request.RemoteAddr) {
+			c.Set(userContextKey, user)
+		}
+		c.Next()
+	}
+}
+
+// GetUser returns the name of the user making the request, or an empty string if it's anonymous.
+// Authenticated principals take precedence over the user forwarded by a trusted proxy.
+func GetUser(c *gin.Context) string {
+	if principal := GetPrincipal(c); principal != nil {
+		return principal.Name
+	}
+	return c.GetString(userContextKey)
+}
+
+// Helper function to parse the trusted proxies, single addresses are ranges of one address.
+// Entries that can't be parsed are skipped, the config validates them at startup.
+func parseTrustedProxies(proxies []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return prefixes
+}
+
+// Helper function to check if the peer of a connection is a trusted proxy.
+// The peer address is used rather than headers such as X-Forwarded-For, which clients can set.
+func isTrustedProxy(trusted []netip.Prefix, remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestForwardedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		remoteAddr string
+		principal  *Principal
+		want       string
+	}{
+		{name: "trusted proxy", remoteAddr: "10.0.0.7:41000", want: "alice"},
+		{name: "trusted proxy address", remoteAddr: "[::1]:41000", want: "alice"},
+		{name: "untrusted client", remoteAddr: "192.168.1.20:41000", want: ""},
+		{name: "principal takes precedence", remoteAddr: "10.0.0.7:41000", principal: &Principal{Name: "bob"}, want: "bob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(WithPrincipal(c.Request.Context(), tt.principal))
+				}
+			})
+			router.Use(ForwardedUser([]string{"10.0.0.0/24", "::1"}))
+			router.GET("/", func(c *gin.Context) { got = GetUser(c) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-User", "alice")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("GetUser() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
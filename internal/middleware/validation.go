@@ -1,9 +1,9 @@
 package middleware
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
 )
 
 // Validation middleware for request validation
@@ -11,7 +11,7 @@ func ValidateID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 		if id == "" || len(id) == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID parameter"})
+			c.Error(apperrors.NewProblem(dto.CodeInvalidRequest, "invalid ID parameter"))
 			c.Abort()
 			return
 		}
@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+)
+
+// Route is a method and path as registered on the router, with gin style :param and *param segments
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Key returns how the route is looked up in the operations passed to AddRoutes, e.g. "GET /api/v1/issues/:id"
+func (r Route) Key() string {
+	return r.Method + " " + r.Path
+}
+
+// AddRoutes adds the operation for each route to the document paths, returning the keys of the routes without one.
+// Operations are keyed by Route.Key. Path parameters an operation doesn't describe are added as plain strings.
+func (d *Document) AddRoutes(routes []Route, operations map[string]*Operation) (undocumented []string) {
+	for _, route := range routes {
+		operation, ok := operations[route.Key()]
+		if !ok {
+			undocumented = append(undocumented, route.Key())
+			continue
+		}
+
+		path, params := convertPath(route.Path)
+		for _, param := range params {
+			if !slices.ContainsFunc(operation.Parameters, func(p Parameter) bool { return p.In == "path" && p.Name == param }) {
+				operation.Parameters = append(operation.Parameters, PathParam(param, ""))
+			}
+		}
+
+		if d.Paths == nil {
+			d.Paths = make(map[string]PathItem)
+		}
+		if d.Paths[path] == nil {
+			d.Paths[path] = make(PathItem)
+		}
+		d.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+	return undocumented
+}
+
+// Helper function to convert a gin path to an OpenAPI path template, returning the names of its parameters
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Generator builds JSON Schemas from Go types, following their json tags the same way encoding/json does.
+// Named structs and enums are added to the components once and referenced wherever they're used.
+type Generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	enums   map[reflect.Type][]any
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+		enums:   make(map[reflect.Type][]any),
+	}
+}
+
+// Enum registers the values of a string type, so schemas for it only allow those values
+func Enum[T ~string](g *Generator, values []T) {
+	enum := make([]any, len(values))
+	for i, value := range values {
+		enum[i] = string(value)
+	}
+	g.enums[reflect.TypeFor[T]()] = enum
+}
+
+// Schema returns the schema for the type of v, which can be a value or a nil pointer of the type
+func (g *Generator) Schema(v any) *Schema {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return g.schemaFor(t)
+}
+
+// Schemas returns the component schemas for every named type used so far
+func (g *Generator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+// Helper function to get the schema for a type, referencing a component for named structs and enums
+func (g *Generator) schemaFor(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		// Any JSON value
+		return &Schema{}
+	}
+
+	if enum, ok := g.enums[t]; ok {
+		return g.component(t, func() *Schema {
+			return &Schema{Type: "string", Enum: enum}
+		})
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schemaFor(t.Elem()))
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return Array(g.schemaFor(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.component(t, func() *Schema { return g.structSchema(t) })
+	}
+
+	// Interfaces can hold any JSON value
+	return &Schema{}
+}
+
+// Helper function to add a component schema for a named type once, returning a reference to it.
+// The name is claimed before the schema is built so types referencing themselves don't recurse forever.
+func (g *Generator) component(t reflect.Type, build func() *Schema) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.componentName(t)
+		g.names[t] = name
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *build()
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Helper function to name a component after its type, prefixed with the package when the name is taken
+func (g *Generator) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		pkg := []rune(path.Base(t.PkgPath()))
+		pkg[0] = unicode.ToUpper(pkg[0])
+		name = string(pkg) + name
+	}
+	return name
+}
+
+// Helper function to build the object schema of a struct, with embedded structs flattened into it
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(schema, t)
+	return schema
+}
+
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = g.schemaFor(field.Type)
+		if isRequired(field.Tag.Get("binding")) && !slices.Contains(schema.Required, name) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// Helper function to check whether binding rules require the field itself, rules after dive apply to its elements
+func isRequired(binding string) bool {
+	for _, rule := range strings.Split(binding, ",") {
+		switch rule {
+		case "required":
+			return true
+		case "dive":
+			return false
+		}
+	}
+	return false
+}
+
+// Helper function to allow null as well as the values of a schema
+func nullable(schema *Schema) *Schema {
+	switch typ := schema.Type.(type) {
+	case string:
+		schema.Type = []string{typ, "null"}
+		return schema
+	case nil:
+		if schema.Ref == "" {
+			// Already allows anything
+			return schema
+		}
+	}
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
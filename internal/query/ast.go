@@ -0,0 +1,92 @@
+// Package query parses the filter language used to search issues, e.g.
+//
+//	severity>=major AND state=ACTIVE AND scope.resourceType IN (component, application) AND detectedAt>now-7d
+//
+// Conditions compare a field to a value and are combined with AND, OR, NOT and parentheses.
+// AND binds tighter than OR. Values are bare words or double quoted strings.
+// The parser only checks the syntax, callers decide which fields and values are valid.
+package query
+
+import "fmt"
+
+// Operator compares a field to its values
+type Operator string
+
+const (
+	OpEqual        Operator = "="
+	OpNotEqual     Operator = "!="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	// OpContains matches text containing the value, ignoring case
+	OpContains Operator = "~"
+	OpIn       Operator = "IN"
+	OpNotIn    Operator = "NOT IN"
+)
+
+// Expr is a node in the query syntax tree
+type Expr interface {
+	// Pos returns the position of the expression in the query, counting characters from 1
+	Pos() int
+}
+
+// And matches when both sides match
+type And struct {
+	Left, Right Expr
+}
+
+// Or matches when either side matches
+type Or struct {
+	Left, Right Expr
+}
+
+// Not matches when the expression doesn't
+type Not struct {
+	Expr     Expr
+	Position int
+}
+
+// Comparison compares a field to one or more values, IN and NOT IN take a list
+type Comparison struct {
+	Field    string
+	Op       Operator
+	Values   []Value
+	Position int
+	// Position of the operator
+	OpPosition int
+}
+
+// Value is a literal in the query
+type Value struct {
+	Text string
+	// Quoted values are never treated as keywords such as null
+	Quoted   bool
+	Position int
+}
+
+func (e *And) Pos() int        { return e.Left.Pos() }
+func (e *Or) Pos() int         { return e.Left.Pos() }
+func (e *Not) Pos() int        { return e.Position }
+func (e *Comparison) Pos() int { return e.Position }
+
+// IsNull reports whether the value is the null keyword
+func (v Value) IsNull() bool {
+	return !v.Quoted && (v.Text == "null" || v.Text == "NULL")
+}
+
+// Error is an invalid query, with the position of the problem
+type Error struct {
+	// Position counts characters from 1
+	Position int
+	Message  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+// Errorf returns an error for the position passed
+func Errorf(position int, format string, args ...any) *Error {
+	return &Error{Position: position, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,229 @@
+package query
+
+import (
+	"strings"
+)
+
+const (
+	// MaxLength is the longest query accepted, in characters
+	MaxLength = 2000
+	// Deepest nesting of parentheses and NOTs accepted
+	maxDepth = 32
+)
+
+type parser struct {
+	tokens []token
+	next   int
+	depth  int
+}
+
+// Parse parses a query into its syntax tree.
+// Errors are returned as *Error with the position of the problem.
+func Parse(input string) (Expr, error) {
+	if length := len([]rune(input)); length > MaxLength {
+		return nil, Errorf(MaxLength+1, "query is longer than %d characters", MaxLength)
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, Errorf(1, "empty query")
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok, "AND, OR or the end of the query")
+	}
+	return expr, nil
+}
+
+// or = and { "OR" and }
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// and = unary { "AND" unary }
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// unary = "NOT" unary | "(" or ")" | comparison
+func (p *parser) parseUnary() (Expr, error) {
+	tok := p.peek()
+	if p.keyword("NOT") || tok.kind == tokenLeftParen {
+		if p.depth++; p.depth > maxDepth {
+			return nil, Errorf(tok.pos, "query is nested too deeply")
+		}
+		defer func() { p.depth-- }()
+	}
+
+	if p.keyword("NOT") {
+		p.advance()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr, Position: tok.pos}, nil
+	}
+
+	if tok.kind == tokenLeftParen {
+		p.advance()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != tokenRightParen {
+			return nil, p.unexpected(closing, `")"`)
+		}
+		p.advance()
+		return expr, nil
+	}
+
+	return p.parseComparison()
+}
+
+// comparison = field operator value | field [ "NOT" ] "IN" "(" value { "," value } ")"
+func (p *parser) parseComparison() (Expr, error) {
+	field := p.peek()
+	if field.kind != tokenWord || isKeyword(field.text) {
+		return nil, p.unexpected(field, "a field name")
+	}
+	p.advance()
+
+	comparison := &Comparison{Field: field.text, Position: field.pos, OpPosition: p.peek().pos}
+
+	switch {
+	case p.peek().kind == tokenOperator:
+		comparison.Op = Operator(p.advance().text)
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = []Value{value}
+		return comparison, nil
+	case p.keyword("IN"):
+		comparison.Op = OpIn
+	case p.keyword("NOT"):
+		p.advance()
+		if !p.keyword("IN") {
+			return nil, p.unexpected(p.peek(), "IN")
+		}
+		comparison.Op = OpNotIn
+	default:
+		return nil, p.unexpected(p.peek(), "an operator")
+	}
+	p.advance()
+
+	values, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	comparison.Values = values
+	return comparison, nil
+}
+
+// list = "(" value { "," value } ")"
+func (p *parser) parseList() ([]Value, error) {
+	if tok := p.peek(); tok.kind != tokenLeftParen {
+		return nil, p.unexpected(tok, `"("`)
+	}
+	p.advance()
+
+	var values []Value
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		switch tok := p.advance(); tok.kind {
+		case tokenComma:
+		case tokenRightParen:
+			return values, nil
+		default:
+			return nil, p.unexpected(tok, `"," or ")"`)
+		}
+	}
+}
+
+// value = word | string
+func (p *parser) parseValue() (Value, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenString:
+		p.advance()
+		return Value{Text: tok.text, Quoted: true, Position: tok.pos}, nil
+	case tok.kind == tokenWord && !isKeyword(tok.text):
+		p.advance()
+		return Value{Text: tok.text, Position: tok.pos}, nil
+	default:
+		return Value{}, p.unexpected(tok, "a value")
+	}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+// Helper function to check if the next token is the keyword passed
+func (p *parser) keyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, keyword)
+}
+
+// Helper function to describe an unexpected token
+func (p *parser) unexpected(tok token, expected string) error {
+	if tok.kind == tokenEOF {
+		return Errorf(tok.pos, "expected %s but the query ended", expected)
+	}
+	return Errorf(tok.pos, "expected %s but found %q", expected, tok.text)
+}
+
+// Helper function to check if a word is reserved, keywords have to be quoted to be used as values
+func isKeyword(word string) bool {
+	switch strings.ToUpper(word) {
+	case "AND", "OR", "NOT", "IN":
+		return true
+	}
+	return false
+}
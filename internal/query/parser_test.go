@@ -0,0 +1,212 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Helper function to print a syntax tree with every AND and OR in parentheses, quoted values in double quotes
+func format(expr Expr) string {
+	switch expr := expr.(type) {
+	case *And:
+		return fmt.Sprintf("(%s AND %s)", format(expr.Left), format(expr.Right))
+	case *Or:
+		return fmt.Sprintf("(%s OR %s)", format(expr.Left), format(expr.Right))
+	case *Not:
+		return "NOT " + format(expr.Expr)
+	case *Comparison:
+		values := make([]string, len(expr.Values))
+		for i, value := range expr.Values {
+			values[i] = value.Text
+			if value.Quoted {
+				values[i] = fmt.Sprintf("%q", value.Text)
+			}
+		}
+		if expr.Op == OpIn || expr.Op == OpNotIn {
+			return fmt.Sprintf("%s %s (%s)", expr.Field, expr.Op, strings.Join(values, ", "))
+		}
+		return expr.Field + string(expr.Op) + values[0]
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "comparison", input: "severity>=major", want: "severity>=major"},
+		{name: "operators", input: "a=1 AND b!=2 AND c<3 AND d<=4 AND e>5 AND f>=6 AND g~7", want: "((((((a=1 AND b!=2) AND c<3) AND d<=4) AND e>5) AND f>=6) AND g~7)"},
+		{name: "AND binds tighter than OR", input: "a=1 OR b=2 AND c=3", want: "(a=1 OR (b=2 AND c=3))"},
+		{name: "AND before OR", input: "a=1 AND b=2 OR c=3", want: "((a=1 AND b=2) OR c=3)"},
+		{name: "parentheses", input: "(a=1 OR b=2) AND c=3", want: "((a=1 OR b=2) AND c=3)"},
+		{name: "OR is left associative", input: "a=1 OR b=2 OR c=3", want: "((a=1 OR b=2) OR c=3)"},
+		{name: "NOT binds tightest", input: "NOT a=1 AND b=2", want: "(NOT a=1 AND b=2)"},
+		{name: "NOT of a group", input: "NOT (a=1 OR b=2)", want: "NOT (a=1 OR b=2)"},
+		{name: "double NOT", input: "NOT NOT a=1", want: "NOT NOT a=1"},
+		{name: "keywords ignore case", input: "a=1 and not b=2 or c=3", want: "((a=1 AND NOT b=2) OR c=3)"},
+		{name: "no spaces around operators", input: "(a=1)AND(b=2)", want: "(a=1 AND b=2)"},
+		{name: "quoted value", input: `title~"disk full"`, want: `title~"disk full"`},
+		{name: "escaped quote", input: `title="say \"hi\""`, want: `title="say \"hi\""`},
+		{name: "escaped backslash", input: `title="a\\b"`, want: `title="a\\b"`},
+		{name: "quoted keyword", input: `title="AND"`, want: `title="AND"`},
+		{name: "quoted delimiters", input: `title="(a, b) = c"`, want: `title="(a, b) = c"`},
+		{name: "empty string", input: `assignee=""`, want: `assignee=""`},
+		{name: "in", input: "state IN (ACTIVE)", want: "state IN (ACTIVE)"},
+		{name: "in list", input: `scope.resourceType in (component, "application",pipeline)`, want: `scope.resourceType IN (component, "application", pipeline)`},
+		{name: "not in", input: "severity NOT IN (info, minor)", want: "severity NOT IN (info, minor)"},
+		{name: "relative time", input: "detectedAt>now-7d", want: "detectedAt>now-7d"},
+		{name: "relative time in the future", input: "resolvedAt<now+12h", want: "resolvedAt<now+12h"},
+		{name: "absolute time", input: "detectedAt>=2024-05-01T00:00:00Z", want: "detectedAt>=2024-05-01T00:00:00Z"},
+		{name: "null", input: "assignee=null", want: "assignee=null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+			}
+			if got := format(expr); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantPosition int
+		wantMessage  string
+	}{
+		{name: "empty", input: "", wantPosition: 1, wantMessage: "empty query"},
+		{name: "only spaces", input: "   ", wantPosition: 1, wantMessage: "empty query"},
+		{name: "no operator", input: "severity", wantPosition: 9, wantMessage: "expected an operator but the query ended"},
+		{name: "no value", input: "severity=", wantPosition: 10, wantMessage: "expected a value but the query ended"},
+		{name: "keyword value", input: "severity=AND", wantPosition: 10, wantMessage: `expected a value but found "AND"`},
+		{name: "keyword field", input: "AND=1", wantPosition: 1, wantMessage: `expected a field name but found "AND"`},
+		{name: "trailing AND", input: "severity=major AND", wantPosition: 19, wantMessage: "expected a field name but the query ended"},
+		{name: "missing AND", input: "severity=major state=ACTIVE", wantPosition: 16, wantMessage: `expected AND, OR or the end of the query but found "state"`},
+		{name: "unclosed parenthesis", input: "(severity=major", wantPosition: 16, wantMessage: `expected ")" but the query ended`},
+		{name: "extra parenthesis", input: "severity=major)", wantPosition: 15, wantMessage: `expected AND, OR or the end of the query but found ")"`},
+		{name: "empty parentheses", input: "()", wantPosition: 2, wantMessage: `expected a field name but found ")"`},
+		{name: "bang", input: "severity!major", wantPosition: 9, wantMessage: `expected "!="`},
+		{name: "unterminated string", input: `title="disk`, wantPosition: 7, wantMessage: "unterminated string"},
+		{name: "unterminated escape", input: `title="disk\`, wantPosition: 12, wantMessage: "unterminated escape"},
+		{name: "in without list", input: "state IN ACTIVE", wantPosition: 10, wantMessage: `expected "(" but found "ACTIVE"`},
+		{name: "in without comma", input: "state IN (a b)", wantPosition: 13, wantMessage: `expected "," or ")" but found "b"`},
+		{name: "empty in", input: "state IN ()", wantPosition: 11, wantMessage: `expected a value but found ")"`},
+		{name: "unclosed in", input: "state IN (a,", wantPosition: 13, wantMessage: "expected a value but the query ended"},
+		{name: "not without in", input: "state NOT ACTIVE", wantPosition: 11, wantMessage: `expected IN but found "ACTIVE"`},
+		{name: "positions count characters", input: `title="éé" state`, wantPosition: 12, wantMessage: `expected AND, OR or the end of the query but found "state"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			var queryErr *Error
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("Parse(%q) returned %v, want a query error", tt.input, err)
+			}
+			if queryErr.Position != tt.wantPosition || queryErr.Message != tt.wantMessage {
+				t.Errorf("Parse(%q) returned %q at position %d, want %q at position %d",
+					tt.input, queryErr.Message, queryErr.Position, tt.wantMessage, tt.wantPosition)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "a=1" + strings.Repeat(")", depth)
+	}
+	tests := []struct {
+		name         string
+		input        string
+		wantPosition int
+		wantMessage  string
+	}{
+		{name: "deepest parentheses", input: nested(maxDepth)},
+		{name: "parentheses too deep", input: nested(maxDepth + 1), wantPosition: maxDepth + 1, wantMessage: "query is nested too deeply"},
+		{name: "deepest NOT", input: strings.Repeat("NOT ", maxDepth) + "a=1"},
+		{name: "NOT too deep", input: strings.Repeat("NOT ", maxDepth+1) + "a=1", wantPosition: maxDepth*4 + 1, wantMessage: "query is nested too deeply"},
+		{name: "NOT and parentheses too deep", input: strings.Repeat("NOT (", maxDepth/2) + "NOT a=1" + strings.Repeat(")", maxDepth/2), wantPosition: maxDepth/2*5 + 1, wantMessage: "query is nested too deeply"},
+		{name: "nesting of siblings isn't added up", input: strings.Repeat(nested(maxDepth)+" AND ", 3) + "a=1"},
+		{name: "longest query", input: "title=" + strings.Repeat("x", MaxLength-6)},
+		{name: "length counts characters", input: "title=" + strings.Repeat("é", MaxLength-6)},
+		{name: "query too long", input: "title=" + strings.Repeat("x", MaxLength-5), wantPosition: MaxLength + 1, wantMessage: fmt.Sprintf("query is longer than %d characters", MaxLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if tt.wantMessage == "" {
+				if err != nil {
+					t.Errorf("Parse returned error: %v", err)
+				}
+				return
+			}
+			var queryErr *Error
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("Parse returned %v, want a query error", err)
+			}
+			if queryErr.Position != tt.wantPosition || queryErr.Message != tt.wantMessage {
+				t.Errorf("Parse returned %q at position %d, want %q at position %d",
+					queryErr.Message, queryErr.Position, tt.wantMessage, tt.wantPosition)
+			}
+		})
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	expr, err := Parse(`NOT state NOT IN (a, "b") OR title~x`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	or := expr.(*Or)
+	not := or.Left.(*Not)
+	in := not.Expr.(*Comparison)
+	contains := or.Right.(*Comparison)
+
+	for _, tt := range []struct {
+		name      string
+		got, want int
+	}{
+		{"OR", or.Pos(), 1},
+		{"NOT", not.Pos(), 1},
+		{"NOT IN field", in.Pos(), 5},
+		{"NOT IN operator", in.OpPosition, 11},
+		{"first value", in.Values[0].Position, 19},
+		{"quoted value", in.Values[1].Position, 22},
+		{"title field", contains.Pos(), 30},
+		{"~ operator", contains.OpPosition, 35},
+		{"x value", contains.Values[0].Position, 36},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("position of %s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestValueIsNull(t *testing.T) {
+	tests := []struct {
+		value Value
+		want  bool
+	}{
+		{Value{Text: "null"}, true},
+		{Value{Text: "NULL"}, true},
+		{Value{Text: "Null"}, false},
+		{Value{Text: "null", Quoted: true}, false},
+		{Value{Text: ""}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.value.IsNull(); got != tt.want {
+			t.Errorf("%+v.IsNull() = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+var (
+	ErrInvalidCursor  = errors.New("invalid cursor")
+	ErrCursorWithSort = errors.New("cursor pagination is only supported with the default sort")
+)
+
+// Cursor is a position in the issue list, ordered by (detected_at, id) descending
+type Cursor struct {
+	DetectedAt time.Time `json:"d"`
+	ID         string    `json:"i"`
+	// Backward cursors page towards newer issues
+	Backward bool `json:"b,omitempty"`
+}
+
+// IssuePage is a single page of issues
+type IssuePage struct {
+	Issues     []models.Issue
+	Total      int64
+	NextCursor string
+	PrevCursor string
+}
+
+// Encode returns the cursor as an opaque token
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor token, tokens that weren't returned by Encode are rejected with ErrInvalidCursor
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.DetectedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	// The ID is compared with a uuid column, anything else would fail the query
+	if _, err := uuid.Parse(cursor.ID); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Helper function to build the cursor pointing after an issue
+func nextCursor(issue models.Issue) string {
+	return Cursor{DetectedAt: issue.DetectedAt, ID: issue.ID}.Encode()
+}
+
+// Helper function to build the cursor pointing before an issue
+func prevCursor(issue models.Issue) string {
+	return Cursor{DetectedAt: issue.DetectedAt, ID: issue.ID, Backward: true}.Encode()
+}
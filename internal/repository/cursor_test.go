@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestCursorEncodeDecode(t *testing.T) {
+	detectedAt := time.Date(2024, 5, 10, 12, 30, 0, 123456789, time.UTC)
+	for _, cursor := range []Cursor{
+		{DetectedAt: detectedAt, ID: "0b7e1b4c-8d2e-4a57-9d4b-6a7f0f1c2d3e"},
+		{DetectedAt: detectedAt, ID: "0b7e1b4c-8d2e-4a57-9d4b-6a7f0f1c2d3e", Backward: true},
+	} {
+		token := cursor.Encode()
+		if strings.ContainsAny(token, "+/=") {
+			t.Errorf("token %q isn't URL safe", token)
+		}
+		decoded, err := DecodeCursor(token)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%q) returned error: %v", token, err)
+		}
+		if !decoded.DetectedAt.Equal(cursor.DetectedAt) || decoded.ID != cursor.ID || decoded.Backward != cursor.Backward {
+			t.Errorf("DecodeCursor(%q) = %+v, want %+v", token, *decoded, cursor)
+		}
+	}
+}
+
+func TestDecodeCursorRejectsTamperedTokens(t *testing.T) {
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+	valid := Cursor{DetectedAt: time.Now(), ID: "0b7e1b4c-8d2e-4a57-9d4b-6a7f0f1c2d3e"}.Encode()
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "not a cursor!"},
+		{name: "padded base64", token: valid + "=="},
+		{name: "truncated", token: valid[:len(valid)-4]},
+		{name: "flipped character", token: "x" + valid[1:]},
+		{name: "not JSON", token: encode("d=2024-05-10")},
+		{name: "wrong types", token: encode(`{"d":1715344200,"i":7}`)},
+		{name: "no ID", token: encode(`{"d":"2024-05-10T12:30:00Z"}`)},
+		{name: "no time", token: encode(`{"i":"0b7e1b4c-8d2e-4a57-9d4b-6a7f0f1c2d3e"}`)},
+		{name: "ID that isn't a UUID", token: encode(`{"d":"2024-05-10T12:30:00Z","i":"' OR 1=1 --"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(tt.token)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) = %+v, %v, want ErrInvalidCursor", tt.token, cursor, err)
+			}
+		})
+	}
+}
+
+// Helper function to open an in-memory database with the issue columns listing reads, when no associations are loaded
+func pagingDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(`CREATE TABLE issues (id TEXT PRIMARY KEY, title TEXT, namespace TEXT, detected_at DATETIME)`).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestFindAllCursorPagesWithTies(t *testing.T) {
+	db := pagingDB(t)
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	// Several issues share each detection time, so only the ID orders them
+	type row struct {
+		id         string
+		detectedAt time.Time
+	}
+	var rows []row
+	for i, offset := range []time.Duration{0, 0, 0, time.Hour, time.Hour, 2 * time.Hour, 2 * time.Hour, 2 * time.Hour, 3 * time.Hour} {
+		r := row{id: fmt.Sprintf("00000000-0000-0000-0000-%012d", (i*7)%10), detectedAt: base.Add(offset)}
+		if err := db.Exec(`INSERT INTO issues VALUES (?, ?, 'team-a', ?)`, r.id, "Issue", r.detectedAt).Error; err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, r)
+	}
+	// Newest first, ties broken by descending ID
+	slices.SortFunc(rows, func(a, b row) int {
+		if c := b.detectedAt.Compare(a.detectedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.id, a.id)
+	})
+	var want []string
+	for _, r := range rows {
+		want = append(want, r.id)
+	}
+
+	repo := NewIssueRepository(db, quietLogger())
+	ctx := context.Background()
+	filters := IssueQueryFilters{Namespace: "team-a", Limit: 2, Include: &IssueIncludes{}}
+
+	// Forward from the first offset page until there's no next cursor
+	page, err := repo.FindAll(ctx, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var forward []string
+	var pages []*IssuePage
+	for {
+		pages = append(pages, page)
+		for _, issue := range page.Issues {
+			forward = append(forward, issue.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if filters.Cursor, err = DecodeCursor(page.NextCursor); err != nil {
+			t.Fatal(err)
+		}
+		if page, err = repo.FindAll(ctx, filters); err != nil {
+			t.Fatal(err)
+		}
+		if len(pages) > len(want) {
+			t.Fatal("paging doesn't end")
+		}
+	}
+	if !slices.Equal(forward, want) {
+		t.Errorf("paged forward through %v, want %v", forward, want)
+	}
+	if last := pages[len(pages)-1]; last.Total != int64(len(want)) {
+		t.Errorf("total = %d, want %d", last.Total, len(want))
+	}
+
+	// Back from the last page, every earlier page is returned again unchanged
+	page = pages[len(pages)-1]
+	for i := len(pages) - 2; i >= 0; i-- {
+		if page.PrevCursor == "" {
+			t.Fatalf("page %d has no previous cursor", i+1)
+		}
+		if filters.Cursor, err = DecodeCursor(page.PrevCursor); err != nil {
+			t.Fatal(err)
+		}
+		if page, err = repo.FindAll(ctx, filters); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := issueIDs(page.Issues), issueIDs(pages[i].Issues); !slices.Equal(got, want) {
+			t.Errorf("page %d backwards = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestFindAllRejectsCursorWithSort(t *testing.T) {
+	repo := NewIssueRepository(pagingDB(t), quietLogger())
+	_, err := repo.FindAll(context.Background(), IssueQueryFilters{
+		Cursor: &Cursor{DetectedAt: time.Now(), ID: "00000000-0000-0000-0000-000000000001"},
+		Sort:   []SortField{{Field: "title"}},
+	})
+	if !errors.Is(err, ErrCursorWithSort) {
+		t.Errorf("error = %v, want ErrCursorWithSort", err)
+	}
+}
+
+func issueIDs(issues []models.Issue) []string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	return ids
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// ScopeFailureCount is the number of issues detected for a single scope
+type ScopeFailureCount struct {
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	Count        int64  `json:"count"`
+}
+
+// DigestSummary holds the aggregated issue data for a namespace over a time window
+type DigestSummary struct {
+	Namespace        string
+	Since            time.Time
+	Until            time.Time
+	NewCount         int64
+	ResolvedCount    int64
+	ActiveCount      int64
+	NewIssues        []models.Issue
+	ResolvedIssues   []models.Issue
+	LongestOpen      []models.Issue
+	TopFailingScopes []ScopeFailureCount
+}
+
+// GetDigestSummary aggregates issue activity for a namespace since the given time.
+// Each list in the summary is capped at limit entries, counts are not.
+func (i *issueRepository) GetDigestSummary(ctx context.Context, namespace string, since time.Time, limit int) (*DigestSummary, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	summary := &DigestSummary{
+		Namespace: namespace,
+		Since:     since,
+		Until:     time.Now(),
+	}
+
+	db := i.db.WithContext(ctx)
+
+	// Get all counts in a single pass over the namespace
+	var counts struct {
+		NewCount      int64
+		ResolvedCount int64
+		ActiveCount   int64
+	}
+	err := db.Model(&models.Issue{}).
+		Select(`COUNT(*) FILTER (WHERE detected_at >= ?) AS new_count,
+			COUNT(*) FILTER (WHERE state = ? AND resolved_at >= ?) AS resolved_count,
+			COUNT(*) FILTER (WHERE state = ?) AS active_count`,
+			since, models.IssueStateResolved, since, models.IssueStateActive).
+		Where("namespace = ?", namespace).
+		Scan(&counts).Error
+	if err != nil {
+		i.logger.WithError(err).WithField("namespace", namespace).Error("Failed to count digest issues")
+		return nil, fmt.Errorf("failed to count digest issues: %w", err)
+	}
+	summary.NewCount = counts.NewCount
+	summary.ResolvedCount = counts.ResolvedCount
+	summary.ActiveCount = counts.ActiveCount
+
+	// Issues detected in the window, newest first
+	if err := db.Preload("Scope").
+		Where("namespace = ? AND detected_at >= ?", namespace, since).
+		Order("detected_at DESC").
+		Limit(limit).
+		Find(&summary.NewIssues).Error; err != nil {
+		return nil, fmt.Errorf("failed to find new issues: %w", err)
+	}
+
+	// Issues resolved in the window, most recent first
+	if err := db.Preload("Scope").
+		Where("namespace = ? AND state = ? AND resolved_at >= ?", namespace, models.IssueStateResolved, since).
+		Order("resolved_at DESC").
+		Limit(limit).
+		Find(&summary.ResolvedIssues).Error; err != nil {
+		return nil, fmt.Errorf("failed to find resolved issues: %w", err)
+	}
+
+	// Active issues that have been open the longest
+	if err := db.Preload("Scope").
+		Where("namespace = ? AND state = ?", namespace, models.IssueStateActive).
+		Order("detected_at ASC").
+		Limit(limit).
+		Find(&summary.LongestOpen).Error; err != nil {
+		return nil, fmt.Errorf("failed to find longest open issues: %w", err)
+	}
+
+	// Scopes with the most issues detected in the window
+	if err := db.Model(&models.Issue{}).
+		Select("issue_scopes.resource_type, issue_scopes.resource_name, COUNT(*) AS count").
+		Joins("JOIN issue_scopes ON issues.scope_id = issue_scopes.id").
+		Where("issues.namespace = ? AND issues.detected_at >= ?", namespace, since).
+		Group("issue_scopes.resource_type, issue_scopes.resource_name").
+		Order("count DESC, issue_scopes.resource_name ASC").
+		Limit(limit).
+		Scan(&summary.TopFailingScopes).Error; err != nil {
+		return nil, fmt.Errorf("failed to find top failing scopes: %w", err)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Helper function to open an in-memory database with the columns of issues and scopes the digest reads.
+// The digest query only uses SQL that SQLite shares with Postgres, unlike the full schema.
+func digestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE issue_scopes (id TEXT PRIMARY KEY, resource_type TEXT, resource_name TEXT, resource_namespace TEXT)`,
+		`CREATE TABLE issues (id TEXT PRIMARY KEY, title TEXT, severity TEXT, state TEXT, namespace TEXT,
+			detected_at DATETIME, resolved_at DATETIME, scope_id TEXT)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func quietLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+type digestIssue struct {
+	namespace  string
+	state      models.IssueState
+	detected   time.Duration // Before now
+	resolved   time.Duration // Before now, when resolved
+	resourceID string
+}
+
+func TestGetDigestSummary(t *testing.T) {
+	db := digestDB(t)
+	now := time.Now().UTC()
+	issues := []digestIssue{
+		{"team-a", models.IssueStateActive, 2 * time.Hour, 0, "build-1"},
+		{"team-a", models.IssueStateActive, 5 * time.Hour, 0, "build-1"},
+		{"team-a", models.IssueStateResolved, 3 * time.Hour, time.Hour, "test-1"},
+		{"team-a", models.IssueStateResolved, 72 * time.Hour, 4 * time.Hour, "build-2"},
+		{"team-a", models.IssueStateResolved, 96 * time.Hour, 48 * time.Hour, "build-2"},
+		{"team-a", models.IssueStateActive, 240 * time.Hour, 0, "build-3"},
+		{"team-b", models.IssueStateActive, time.Hour, 0, "build-1"},
+	}
+	for i, issue := range issues {
+		scopeID := fmt.Sprintf("scope-%d", i)
+		if err := db.Exec(`INSERT INTO issue_scopes VALUES (?, 'component', ?, ?)`, scopeID, issue.resourceID, issue.namespace).Error; err != nil {
+			t.Fatal(err)
+		}
+		var resolvedAt *time.Time
+		if issue.resolved > 0 {
+			resolved := now.Add(-issue.resolved)
+			resolvedAt = &resolved
+		}
+		if err := db.Exec(`INSERT INTO issues VALUES (?, ?, 'major', ?, ?, ?, ?, ?)`,
+			fmt.Sprintf("issue-%d", i), fmt.Sprintf("Issue %d", i), issue.state, issue.namespace,
+			now.Add(-issue.detected), resolvedAt, scopeID).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := NewIssueRepository(db, quietLogger())
+	summary, err := repo.GetDigestSummary(context.Background(), "team-a", now.Add(-24*time.Hour), 2)
+	if err != nil {
+		t.Fatalf("GetDigestSummary() error = %v", err)
+	}
+
+	if summary.NewCount != 3 || summary.ResolvedCount != 2 || summary.ActiveCount != 3 {
+		t.Errorf("counted %d new, %d resolved and %d active, want 3, 2 and 3",
+			summary.NewCount, summary.ResolvedCount, summary.ActiveCount)
+	}
+
+	ids := func(issues []models.Issue) []string {
+		var ids []string
+		for _, issue := range issues {
+			ids = append(ids, issue.ID)
+		}
+		return ids
+	}
+	// Lists are capped at the limit, counts aren't
+	if got, want := ids(summary.NewIssues), []string{"issue-0", "issue-2"}; !slices.Equal(got, want) {
+		t.Errorf("new issues = %v, want %v", got, want)
+	}
+	if got, want := ids(summary.ResolvedIssues), []string{"issue-2", "issue-3"}; !slices.Equal(got, want) {
+		t.Errorf("resolved issues = %v, want %v", got, want)
+	}
+	if got, want := ids(summary.LongestOpen), []string{"issue-5", "issue-1"}; !slices.Equal(got, want) {
+		t.Errorf("longest open issues = %v, want %v", got, want)
+	}
+	if summary.NewIssues[0].Scope.ResourceName != "build-1" {
+		t.Errorf("scope of new issue = %+v, want it preloaded", summary.NewIssues[0].Scope)
+	}
+
+	want := []ScopeFailureCount{
+		{ResourceType: "component", ResourceName: "build-1", Count: 2},
+		{ResourceType: "component", ResourceName: "test-1", Count: 1},
+	}
+	if !slices.Equal(summary.TopFailingScopes, want) {
+		t.Errorf("top failing scopes = %+v, want %+v", summary.TopFailingScopes, want)
+	}
+}
+
+func TestGetDigestSummaryEmptyNamespace(t *testing.T) {
+	repo := NewIssueRepository(digestDB(t), quietLogger())
+
+	summary, err := repo.GetDigestSummary(context.Background(), "team-c", time.Now().Add(-24*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("GetDigestSummary() error = %v", err)
+	}
+	if summary.NewCount != 0 || len(summary.NewIssues) != 0 || len(summary.TopFailingScopes) != 0 {
+		t.Errorf("summary of an empty namespace = %+v", summary)
+	}
+}
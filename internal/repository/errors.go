@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/konflux-ci/kite/internal/apperrors"
+
+var (
+	// ErrIssueNotFound is returned when changing an issue that doesn't exist
+	ErrIssueNotFound = apperrors.New(apperrors.ErrNotFound, "issue not found")
+	// ErrRelatedIssueNotFound is returned when relating issues and either of them doesn't exist
+	ErrRelatedIssueNotFound = apperrors.New(apperrors.ErrNotFound, "one or both issues not found")
+	// ErrRelationshipNotFound is returned when removing a relationship between issues that aren't related
+	ErrRelationshipNotFound = apperrors.New(apperrors.ErrNotFound, "relationship not found")
+	// ErrRelationshipExists is returned when relating issues that are already related, in either direction
+	ErrRelationshipExists = apperrors.New(apperrors.ErrConflict, "relationship already exists")
+)
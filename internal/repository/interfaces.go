@@ -2,25 +2,59 @@ package repository
 
 import (
 	"context"
+	"time"
 
-	"github.com/konflux-ci/kite/internal/handlers/dto"
-	"github.com/konflux-ci/kite/internal/models"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
 )
 
 type IssueRepository interface {
 	Create(ctx context.Context, req dto.CreateIssueRequest) (*models.Issue, error)
 	FindByID(ctx context.Context, id string) (*models.Issue, error)
-	Update(ctx context.Context, id string, updates dto.UpdateIssueRequest) (*models.Issue, error)
-	Delete(ctx context.Context, id string) error
+	// Writes with a version other than 0 fail with ErrVersionMismatch unless the issue is at that version
+	Update(ctx context.Context, id string, updates dto.UpdateIssueRequest, version int64) (*models.Issue, error)
+	Replace(ctx context.Context, id string, req dto.ReplaceIssueRequest, version int64) (*models.Issue, error)
+	Delete(ctx context.Context, id string, version int64) error
 	// TODO - move IssueQueryFilters somewhere else
-	FindAll(ctx context.Context, filters IssueQueryFilters) ([]models.Issue, int64, error)
+	FindAll(ctx context.Context, filters IssueQueryFilters) (*IssuePage, error)
+	// Associations of many issues at once, for batching lookups
+	FindScopes(ctx context.Context, ids []string) ([]models.IssueScope, error)
+	FindLinks(ctx context.Context, issueIDs []string) ([]models.Link, error)
+	FindRelations(ctx context.Context, issueIDs []string) ([]models.RelatedIssue, error)
 	CheckDuplicate(ctx context.Context, req dto.CreateIssueRequest) (*DuplicateCheckResult, error)
 	ResolveByScope(ctx context.Context, resourceType, resourceName, namespace string) (int64, error)
 	AddRelatedIssue(ctx context.Context, sourceID, targetID string) error
 	RemoveRelatedIssue(ctx context.Context, sourceID, targetID string) error
+	GetDigestSummary(ctx context.Context, namespace string, since time.Time, limit int) (*DigestSummary, error)
+	FindActiveWithoutLink(ctx context.Context, linkPrefix string, severities []models.Severity) ([]models.Issue, error)
+	GetStats(ctx context.Context, filters IssueQueryFilters, options IssueStatsOptions) (*IssueStats, error)
+	GetReliability(ctx context.Context, q ReliabilityQuery) ([]ReliabilityMetrics, error)
+	StreamAll(ctx context.Context, filters IssueQueryFilters, fn func(issue *models.Issue) error) error
+	Import(ctx context.Context, reqs []dto.ImportIssueRequest) ([]models.Issue, error)
+	BulkUpdate(ctx context.Context, ids []string, action BulkAction) (map[string]bool, error)
 }
 
 type LinkRepository interface {
 	CreateBatch(ctx context.Context, issueID string, links []models.Link) error
 	DeleteByIssueID(ctx context.Context, issueID string) error
+	FindByIssueID(ctx context.Context, issueID string) ([]models.Link, error)
+	FindByURL(ctx context.Context, url string) (*models.Link, error)
+	FindByURLPrefix(ctx context.Context, prefix string) ([]models.Link, error)
+}
+
+type OutboxRepository interface {
+	DispatchPending(ctx context.Context, limit, maxAttempts int, dispatch func(event models.OutboxEvent) error) (int, error)
+	DeleteOlderThan(ctx context.Context, before time.Time, includePending bool) (int64, error)
+	FindAfter(ctx context.Context, after EventPosition, limit int) ([]models.OutboxEvent, error)
+	LatestPosition(ctx context.Context) (EventPosition, error)
+	HeldBack(ctx context.Context, after EventPosition) (bool, error)
+	FindPosition(ctx context.Context, id int64) (EventPosition, error)
+}
+
+type SavedViewRepository interface {
+	Create(ctx context.Context, view *models.SavedView) error
+	FindByID(ctx context.Context, id string) (*models.SavedView, error)
+	FindVisible(ctx context.Context, namespace, owner string) ([]models.SavedView, error)
+	Update(ctx context.Context, view *models.SavedView) error
+	Delete(ctx context.Context, id string) error
 }
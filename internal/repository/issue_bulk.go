@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BulkActionType string
+
+const (
+	BulkActionResolve  BulkActionType = "resolve"
+	BulkActionSeverity BulkActionType = "severity"
+	BulkActionAssign   BulkActionType = "assign"
+	BulkActionLabel    BulkActionType = "label"
+	BulkActionDelete   BulkActionType = "delete"
+)
+
+// BulkActionTypes lists every bulk action
+var BulkActionTypes = []BulkActionType{
+	BulkActionResolve, BulkActionSeverity, BulkActionAssign, BulkActionLabel, BulkActionDelete,
+}
+
+// BulkAction is a change applied to many issues at once
+type BulkAction struct {
+	Type BulkActionType
+	// Severity set by BulkActionSeverity
+	Severity models.Severity
+	// Assignee set by BulkActionAssign, nil unassigns issues
+	Assignee *string
+	// Label added by BulkActionLabel
+	Label string
+}
+
+// BulkUpdate applies an action to each issue in a single transaction, so either every issue is changed or none are.
+//
+// It returns whether each issue was changed, keyed by ID. Issues already in the state the action
+// would leave them in are left alone, and IDs of issues that don't exist are missing from the result.
+func (i *issueRepository) BulkUpdate(ctx context.Context, ids []string, action BulkAction) (map[string]bool, error) {
+	changed := make(map[string]bool, len(ids))
+	now := time.Now()
+
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the issues so they can't change between being checked and updated
+		var issues []models.Issue
+		if err := tx.
+			Where("id IN ?", ids).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Find(&issues).Error; err != nil {
+			return fmt.Errorf("failed to find issues to update: %w", err)
+		}
+
+		for idx := range issues {
+			issue := &issues[idx]
+			applied, err := i.applyBulkAction(tx, issue, action, now)
+			if err != nil {
+				return err
+			}
+			changed[issue.ID] = applied
+		}
+		return nil
+	})
+
+	if err != nil {
+		i.logger.WithError(err).WithField("action", action.Type).Error("Failed to bulk update issues")
+		return nil, err
+	}
+
+	i.logger.WithField("action", action.Type).WithField("count", len(changed)).Info("Bulk updated issues")
+	return changed, nil
+}
+
+// Helper function to apply a bulk action to one locked issue, returning false if it didn't need changing
+func (i *issueRepository) applyBulkAction(tx *gorm.DB, issue *models.Issue, action BulkAction, now time.Time) (bool, error) {
+	eventType := models.EventTypeIssueUpdated
+	updates := map[string]any{"updated_at": now, "version": gorm.Expr("version + 1")}
+
+	switch action.Type {
+	case BulkActionDelete:
+		return true, i.deleteIssue(tx, issue.ID, issue.ScopeID, 0)
+	case BulkActionResolve:
+		if issue.State == models.IssueStateResolved {
+			return false, nil
+		}
+		updates["state"] = models.IssueStateResolved
+		updates["resolved_at"] = &now
+		eventType = models.EventTypeIssueResolved
+	case BulkActionSeverity:
+		if issue.Severity == action.Severity {
+			return false, nil
+		}
+		updates["severity"] = action.Severity
+	case BulkActionAssign:
+		if issue.Assignee == nil && action.Assignee == nil ||
+			issue.Assignee != nil && action.Assignee != nil && *issue.Assignee == *action.Assignee {
+			return false, nil
+		}
+		updates["assignee"] = action.Assignee
+	case BulkActionLabel:
+		if slices.Contains(issue.Labels, action.Label) {
+			return false, nil
+		}
+		updates["labels"] = gorm.Expr("labels || jsonb_build_array(?::text)", action.Label)
+	default:
+		return false, fmt.Errorf("unknown bulk action %q", action.Type)
+	}
+
+	if err := tx.Model(&models.Issue{}).Where("id = ?", issue.ID).Updates(updates).Error; err != nil {
+		return false, fmt.Errorf("failed to update issue %s: %w", issue.ID, err)
+	}
+	return true, i.recordEvent(tx, eventType, issue.ID)
+}
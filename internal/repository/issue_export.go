@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// Columns read for each exported issue, links are aggregated so each issue is a single row
+var exportColumns = []string{
+	"issues.id", "issues.title", "issues.description", "issues.severity", "issues.issue_type", "issues.state",
+	"issues.detected_at", "issues.resolved_at", "issues.namespace", "issues.assignee", "issues.labels", "issues.created_at", "issues.updated_at",
+	"issue_scopes.id", "issue_scopes.resource_type", "issue_scopes.resource_name", "issue_scopes.resource_namespace",
+	`(SELECT COALESCE(json_agg(json_build_object('id', links.id, 'title', links.title, 'url', links.url, 'issueId', links.issue_id) ORDER BY links.title, links.id), '[]')
+	 FROM links WHERE links.issue_id = issues.id)`,
+}
+
+// StreamAll calls fn with every issue matching the filters, along with its scope and links.
+//
+// Rows are read one at a time from the database rather than loaded up front, so any number of issues can be exported.
+// Pagination and projection filters are ignored. Streaming stops at the first error returned by fn.
+func (i *issueRepository) StreamAll(ctx context.Context, filters IssueQueryFilters, fn func(issue *models.Issue) error) error {
+	query := applyFilters(i.db.WithContext(ctx).Model(&models.Issue{}), filters, false)
+	query = query.Joins("JOIN issue_scopes ON issues.scope_id = issue_scopes.id").Select(exportColumns)
+	if filters.Search != "" && len(filters.Sort) == 0 {
+		query = orderByRank(query, filters.Search)
+	} else {
+		query = applySort(query, filters.Sort)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		i.logger.WithError(err).Error("Failed to export issues")
+		return fmt.Errorf("failed to export issues: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var issue models.Issue
+		var labels, links []byte
+		if err := rows.Scan(
+			&issue.ID, &issue.Title, &issue.Description, &issue.Severity, &issue.IssueType, &issue.State,
+			&issue.DetectedAt, &issue.ResolvedAt, &issue.Namespace, &issue.Assignee, &labels, &issue.CreatedAt, &issue.UpdatedAt,
+			&issue.Scope.ID, &issue.Scope.ResourceType, &issue.Scope.ResourceName, &issue.Scope.ResourceNamespace,
+			&links,
+		); err != nil {
+			return fmt.Errorf("failed to read exported issue: %w", err)
+		}
+		issue.ScopeID = issue.Scope.ID
+		if err := json.Unmarshal(labels, &issue.Labels); err != nil {
+			return fmt.Errorf("failed to read labels of exported issue: %w", err)
+		}
+		if err := json.Unmarshal(links, &issue.Links); err != nil {
+			return fmt.Errorf("failed to read links of exported issue: %w", err)
+		}
+
+		if err := fn(&issue); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		i.logger.WithError(err).Error("Failed to export issues")
+		return fmt.Errorf("failed to export issues: %w", err)
+	}
+	return nil
+}
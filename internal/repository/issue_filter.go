@@ -0,0 +1,412 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/query"
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/gorm"
+)
+
+// IssueQuery is a q filter that has been parsed and checked against the issue fields.
+// Relative times such as now-7d are resolved each time the query is used.
+type IssueQuery struct {
+	root  filterNode
+	scope bool
+}
+
+type filterKind int
+
+const (
+	textFilter filterKind = iota
+	enumFilter
+	severityFilter
+	timeFilter
+)
+
+// filterField is a field that can be used in q filters
+type filterField struct {
+	column   string
+	kind     filterKind
+	nullable bool
+	// Joins issue_scopes when used
+	scope bool
+	// Valid values for enum fields
+	values []string
+	// Returns the field's value, a string, *string or *time.Time
+	get func(issue *models.Issue) any
+}
+
+// Maps the JSON name of each field that can be filtered on to how it's filtered
+var filterFields = map[string]filterField{
+	"title":       {column: "issues.title", kind: textFilter, get: func(i *models.Issue) any { return i.Title }},
+	"description": {column: "issues.description", kind: textFilter, get: func(i *models.Issue) any { return i.Description }},
+	"namespace":   {column: "issues.namespace", kind: textFilter, get: func(i *models.Issue) any { return i.Namespace }},
+	"assignee":    {column: "issues.assignee", kind: textFilter, nullable: true, get: func(i *models.Issue) any { return i.Assignee }},
+	"severity":    {column: "issues.severity", kind: severityFilter, values: enumValues(models.Severities), get: func(i *models.Issue) any { return string(i.Severity) }},
+	"issueType":   {column: "issues.issue_type", kind: enumFilter, values: enumValues(models.IssueTypes), get: func(i *models.Issue) any { return string(i.IssueType) }},
+	"state":       {column: "issues.state", kind: enumFilter, values: enumValues(models.IssueStates), get: func(i *models.Issue) any { return string(i.State) }},
+	"detectedAt":  {column: "issues.detected_at", kind: timeFilter, get: func(i *models.Issue) any { return &i.DetectedAt }},
+	"resolvedAt":  {column: "issues.resolved_at", kind: timeFilter, nullable: true, get: func(i *models.Issue) any { return i.ResolvedAt }},
+	"createdAt":   {column: "issues.created_at", kind: timeFilter, get: func(i *models.Issue) any { return &i.CreatedAt }},
+	"updatedAt":   {column: "issues.updated_at", kind: timeFilter, get: func(i *models.Issue) any { return &i.UpdatedAt }},
+
+	"scope.resourceType":      {column: "issue_scopes.resource_type", kind: textFilter, scope: true, get: func(i *models.Issue) any { return i.Scope.ResourceType }},
+	"scope.resourceName":      {column: "issue_scopes.resource_name", kind: textFilter, scope: true, get: func(i *models.Issue) any { return i.Scope.ResourceName }},
+	"scope.resourceNamespace": {column: "issue_scopes.resource_namespace", kind: textFilter, scope: true, get: func(i *models.Issue) any { return i.Scope.ResourceNamespace }},
+}
+
+// Operators allowed for each kind of field
+var filterOperators = map[filterKind][]query.Operator{
+	textFilter:     {query.OpEqual, query.OpNotEqual, query.OpContains, query.OpIn, query.OpNotIn},
+	enumFilter:     {query.OpEqual, query.OpNotEqual, query.OpIn, query.OpNotIn},
+	severityFilter: {query.OpEqual, query.OpNotEqual, query.OpLess, query.OpLessEqual, query.OpGreater, query.OpGreaterEqual, query.OpIn, query.OpNotIn},
+	timeFilter:     {query.OpEqual, query.OpNotEqual, query.OpLess, query.OpLessEqual, query.OpGreater, query.OpGreaterEqual},
+}
+
+// Matches relative times, e.g. now, now-7d or now+12h
+var relativeTimePattern = regexp.MustCompile(`^now(?:([+-])(\d+)([smhdw]))?$`)
+
+// Largest offset of a relative time, well below the ~292 years a time.Duration can hold
+const maxRelativeOffset = 100 * 365 * 24 * time.Hour
+
+var timeUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// ParseIssueQuery parses a q filter, e.g. severity>=major AND detectedAt>now-7d.
+// Errors are returned as *query.Error with the position of the problem.
+func ParseIssueQuery(q string) (*IssueQuery, error) {
+	expr, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+
+	issueQuery := &IssueQuery{}
+	root, err := issueQuery.check(expr)
+	if err != nil {
+		return nil, err
+	}
+	issueQuery.root = root
+	return issueQuery, nil
+}
+
+// Matches reports whether an issue passes the filter
+func (q *IssueQuery) Matches(issue *models.Issue, now time.Time) bool {
+	return q.root.match(issue, now)
+}
+
+// Helper function to filter a query, the caller joins issue_scopes when the filter uses scope fields
+func (q *IssueQuery) apply(db *gorm.DB, now time.Time) *gorm.DB {
+	sql, vars := q.root.sql(now)
+	return db.Where(sql, vars...)
+}
+
+// Helper function to check the fields, operators and values in a syntax tree
+func (q *IssueQuery) check(expr query.Expr) (filterNode, error) {
+	switch expr := expr.(type) {
+	case *query.And:
+		left, right, err := q.checkBoth(expr.Left, expr.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &andNode{left: left, right: right}, nil
+	case *query.Or:
+		left, right, err := q.checkBoth(expr.Left, expr.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &orNode{left: left, right: right}, nil
+	case *query.Not:
+		node, err := q.check(expr.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: node}, nil
+	case *query.Comparison:
+		return q.checkComparison(expr)
+	}
+	return nil, query.Errorf(expr.Pos(), "unsupported expression")
+}
+
+func (q *IssueQuery) checkBoth(left, right query.Expr) (filterNode, filterNode, error) {
+	leftNode, err := q.check(left)
+	if err != nil {
+		return nil, nil, err
+	}
+	rightNode, err := q.check(right)
+	if err != nil {
+		return nil, nil, err
+	}
+	return leftNode, rightNode, nil
+}
+
+func (q *IssueQuery) checkComparison(comparison *query.Comparison) (filterNode, error) {
+	field, ok := filterFields[comparison.Field]
+	if !ok {
+		return nil, query.Errorf(comparison.Position, "unknown field %q", comparison.Field)
+	}
+	if !slices.Contains(filterOperators[field.kind], comparison.Op) {
+		return nil, query.Errorf(comparison.OpPosition, "operator %s can't be used with %s", comparison.Op, comparison.Field)
+	}
+	q.scope = q.scope || field.scope
+
+	node := &conditionNode{field: field, op: comparison.Op}
+	for _, value := range comparison.Values {
+		parsed, err := parseFilterValue(field, comparison, value)
+		if err != nil {
+			return nil, err
+		}
+		node.values = append(node.values, parsed)
+	}
+	return node, nil
+}
+
+// filterValue is a value checked against its field
+type filterValue struct {
+	text string
+	null bool
+	// Set for severities
+	rank int
+	// Set for times, relative times are an offset from now
+	at       time.Time
+	relative bool
+	offset   time.Duration
+}
+
+// Helper function to resolve a time value
+func (v filterValue) time(now time.Time) time.Time {
+	if v.relative {
+		return now.Add(v.offset)
+	}
+	return v.at
+}
+
+// Helper function to check a value is valid for its field
+func parseFilterValue(field filterField, comparison *query.Comparison, value query.Value) (filterValue, error) {
+	if value.IsNull() {
+		if !field.nullable {
+			return filterValue{}, query.Errorf(value.Position, "%s can't be null", comparison.Field)
+		}
+		if comparison.Op != query.OpEqual && comparison.Op != query.OpNotEqual {
+			return filterValue{}, query.Errorf(value.Position, "null can only be compared with = or !=")
+		}
+		return filterValue{null: true}, nil
+	}
+
+	switch field.kind {
+	case enumFilter, severityFilter:
+		index := slices.IndexFunc(field.values, func(valid string) bool { return strings.EqualFold(valid, value.Text) })
+		if index < 0 {
+			return filterValue{}, query.Errorf(value.Position, "invalid %s %q, expected one of %s",
+				comparison.Field, value.Text, strings.Join(field.values, ", "))
+		}
+		return filterValue{text: field.values[index], rank: index}, nil
+	case timeFilter:
+		return parseTimeValue(value)
+	}
+	return filterValue{text: value.Text}, nil
+}
+
+// Helper function to parse an absolute time (RFC 3339 or a date) or a time relative to now
+func parseTimeValue(value query.Value) (filterValue, error) {
+	if match := relativeTimePattern.FindStringSubmatch(value.Text); match != nil {
+		parsed := filterValue{relative: true}
+		if match[1] != "" {
+			// Offsets are bounded before multiplying, so large amounts can't overflow into another time
+			unit := timeUnits[match[3]]
+			amount, err := strconv.ParseInt(match[2], 10, 64)
+			if err != nil || amount > int64(maxRelativeOffset/unit) {
+				return filterValue{}, query.Errorf(value.Position, "invalid time %q, relative times can be at most 100 years from now", value.Text)
+			}
+			parsed.offset = time.Duration(amount) * unit
+			if match[1] == "-" {
+				parsed.offset = -parsed.offset
+			}
+		}
+		return parsed, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if at, err := time.Parse(layout, value.Text); err == nil {
+			return filterValue{at: at}, nil
+		}
+	}
+	return filterValue{}, query.Errorf(value.Position,
+		"invalid time %q, expected RFC 3339, a date such as 2006-01-02, or a relative time such as now-7d", value.Text)
+}
+
+// filterNode is a checked expression, it's turned into SQL or evaluated in memory the same way
+type filterNode interface {
+	sql(now time.Time) (string, []any)
+	match(issue *models.Issue, now time.Time) bool
+}
+
+type andNode struct {
+	left, right filterNode
+}
+
+type orNode struct {
+	left, right filterNode
+}
+
+type notNode struct {
+	node filterNode
+}
+
+type conditionNode struct {
+	field  filterField
+	op     query.Operator
+	values []filterValue
+}
+
+func (n *andNode) sql(now time.Time) (string, []any) {
+	return joinSQL("AND", n.left, n.right, now)
+}
+
+func (n *andNode) match(issue *models.Issue, now time.Time) bool {
+	return n.left.match(issue, now) && n.right.match(issue, now)
+}
+
+func (n *orNode) sql(now time.Time) (string, []any) {
+	return joinSQL("OR", n.left, n.right, now)
+}
+
+func (n *orNode) match(issue *models.Issue, now time.Time) bool {
+	return n.left.match(issue, now) || n.right.match(issue, now)
+}
+
+// Comparisons with NULL are treated as false rather than unknown, so NOT matches them like it does in memory
+func (n *notNode) sql(now time.Time) (string, []any) {
+	sql, vars := n.node.sql(now)
+	return "NOT COALESCE(" + sql + ", false)", vars
+}
+
+func (n *notNode) match(issue *models.Issue, now time.Time) bool {
+	return !n.node.match(issue, now)
+}
+
+func (n *conditionNode) sql(now time.Time) (string, []any) {
+	column := n.field.column
+	value := n.values[0]
+
+	if value.null {
+		if n.op == query.OpEqual {
+			return column + " IS NULL", nil
+		}
+		return column + " IS NOT NULL", nil
+	}
+
+	switch n.op {
+	case query.OpIn, query.OpNotIn:
+		values := make([]string, len(n.values))
+		for i, value := range n.values {
+			values[i] = value.text
+		}
+		return fmt.Sprintf("%s %s ?", column, n.op), []any{values}
+	case query.OpContains:
+		return column + ` ILIKE ?`, []any{"%" + escapeLike(value.text) + "%"}
+	case query.OpEqual, query.OpNotEqual:
+		if n.field.kind != timeFilter {
+			return fmt.Sprintf("%s %s ?", column, sqlOperator(n.op)), []any{value.text}
+		}
+	}
+
+	// Ordered comparisons
+	switch n.field.kind {
+	case severityFilter:
+		return fmt.Sprintf("(%s) %s ?", severityRankSQL(), sqlOperator(n.op)), []any{value.rank}
+	case timeFilter:
+		return fmt.Sprintf("%s %s ?", column, sqlOperator(n.op)), []any{value.time(now)}
+	}
+	return "false", nil
+}
+
+func (n *conditionNode) match(issue *models.Issue, now time.Time) bool {
+	value := n.field.get(issue)
+	if text, ok := value.(*string); ok {
+		if n.values[0].null {
+			return (text == nil) == (n.op == query.OpEqual)
+		}
+		if text == nil {
+			return false
+		}
+		value = *text
+	}
+
+	switch actual := value.(type) {
+	case *time.Time:
+		if n.values[0].null {
+			return (actual == nil) == (n.op == query.OpEqual)
+		}
+		if actual == nil {
+			return false
+		}
+		return compare(actual.Compare(n.values[0].time(now)), n.op)
+	case string:
+		switch n.op {
+		case query.OpIn, query.OpNotIn:
+			found := slices.ContainsFunc(n.values, func(value filterValue) bool { return value.text == actual })
+			return found == (n.op == query.OpIn)
+		case query.OpContains:
+			return strings.Contains(strings.ToLower(actual), strings.ToLower(n.values[0].text))
+		}
+		if n.field.kind == severityFilter {
+			return compare(models.Severity(actual).Rank()-n.values[0].rank, n.op)
+		}
+		return compare(strings.Compare(actual, n.values[0].text), n.op)
+	}
+	return false
+}
+
+// Helper function to combine two nodes
+func joinSQL(operator string, left, right filterNode, now time.Time) (string, []any) {
+	leftSQL, leftVars := left.sql(now)
+	rightSQL, rightVars := right.sql(now)
+	return fmt.Sprintf("(%s %s %s)", leftSQL, operator, rightSQL), append(leftVars, rightVars...)
+}
+
+// Helper function to map an operator to SQL
+func sqlOperator(op query.Operator) string {
+	if op == query.OpNotEqual {
+		return "<>"
+	}
+	return string(op)
+}
+
+// Helper function to check the result of a comparison against an operator
+func compare(result int, op query.Operator) bool {
+	switch op {
+	case query.OpEqual:
+		return result == 0
+	case query.OpNotEqual:
+		return result != 0
+	case query.OpLess:
+		return result < 0
+	case query.OpLessEqual:
+		return result <= 0
+	case query.OpGreater:
+		return result > 0
+	case query.OpGreaterEqual:
+		return result >= 0
+	}
+	return false
+}
+
+// Helper function to list the values of an enum
+func enumValues[T ~string](values []T) []string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = string(value)
+	}
+	return strs
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/query"
+)
+
+func TestParseTimeRelative(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"now", now},
+		{"now-7d", now.Add(-7 * 24 * time.Hour)},
+		{"now+12h", now.Add(12 * time.Hour)},
+		{"now-5200w", now.Add(-5200 * 7 * 24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseTime(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimeRelativeOutOfRange(t *testing.T) {
+	for _, value := range []string{"now-36501d", "now+9223372036854775807s", "now-99999999999999999999w"} {
+		if _, err := ParseTime(value, time.Now()); err == nil {
+			t.Errorf("ParseTime(%q) should fail for an offset out of range", value)
+		}
+	}
+}
+
+func TestParseIssueQueryOutOfRangePosition(t *testing.T) {
+	_, err := ParseIssueQuery("severity=major AND detectedAt>now-9223372036854775807s")
+	var queryErr *query.Error
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("expected a query error for an offset out of range, got %v", err)
+	}
+	// The position of the value
+	if queryErr.Position != 31 {
+		t.Errorf("error position = %d, want 31", queryErr.Position)
+	}
+}
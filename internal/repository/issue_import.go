@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/gorm"
+)
+
+// Import creates an issue for each request in a single transaction, so either every issue is imported or none are.
+//
+// Unlike Create, issues keep the detection and resolution times given and duplicates aren't merged,
+// callers are expected to have checked for them already.
+func (i *issueRepository) Import(ctx context.Context, reqs []dto.ImportIssueRequest) ([]models.Issue, error) {
+	now := time.Now()
+	issues := make([]models.Issue, len(reqs))
+	for idx, req := range reqs {
+		state := req.State
+		if state == "" {
+			state = models.IssueStateActive
+		}
+		detectedAt := now
+		if req.DetectedAt != nil {
+			detectedAt = *req.DetectedAt
+		}
+		resourceNamespace := req.Scope.ResourceNamespace
+		if resourceNamespace == "" {
+			resourceNamespace = req.Namespace
+		}
+
+		issues[idx] = models.Issue{
+			Title:       req.Title,
+			Description: req.Description,
+			Severity:    req.Severity,
+			IssueType:   req.IssueType,
+			State:       state,
+			DetectedAt:  detectedAt,
+			ResolvedAt:  req.ResolvedAt,
+			Namespace:   req.Namespace,
+			Scope: models.IssueScope{
+				ResourceType:      req.Scope.ResourceType,
+				ResourceName:      req.Scope.ResourceName,
+				ResourceNamespace: resourceNamespace,
+			},
+		}
+		for _, linkReq := range req.Links {
+			issues[idx].Links = append(issues[idx].Links, models.Link{
+				Title: linkReq.Title,
+				URL:   linkReq.URL,
+			})
+		}
+	}
+
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for idx := range issues {
+			if err := tx.Create(&issues[idx]).Error; err != nil {
+				return fmt.Errorf("failed to import issue %d: %w", idx+1, err)
+			}
+			if err := i.recordEvent(tx, models.EventTypeIssueCreated, issues[idx].ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		i.logger.WithError(err).Error("Failed to import issues")
+		return nil, err
+	}
+
+	i.logger.WithField("count", len(issues)).Info("Imported issues")
+	return issues, nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/gorm"
+)
+
+// SortField is a field issues are ordered by
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// IssueIncludes controls which associations are loaded with each issue
+type IssueIncludes struct {
+	Scope   bool
+	Links   bool
+	Related bool
+}
+
+// AllIssueIncludes loads every association, this is the default when listing issues
+var AllIssueIncludes = IssueIncludes{Scope: true, Links: true, Related: true}
+
+// Maps the JSON name of each sortable field to the expression it's sorted by
+var sortableFields = map[string]string{
+	"title":      "issues.title",
+	"severity":   severityRankSQL(),
+	"issueType":  "issues.issue_type",
+	"state":      "issues.state",
+	"namespace":  "issues.namespace",
+	"assignee":   "issues.assignee",
+	"detectedAt": "issues.detected_at",
+	"resolvedAt": "issues.resolved_at",
+	"createdAt":  "issues.created_at",
+	"updatedAt":  "issues.updated_at",
+}
+
+// Maps the JSON name of each selectable issue field to its column
+var selectableFields = map[string]string{
+	"id":          "issues.id",
+	"title":       "issues.title",
+	"description": "issues.description",
+	"severity":    "issues.severity",
+	"issueType":   "issues.issue_type",
+	"state":       "issues.state",
+	"detectedAt":  "issues.detected_at",
+	"resolvedAt":  "issues.resolved_at",
+	"namespace":   "issues.namespace",
+	"assignee":    "issues.assignee",
+	"labels":      "issues.labels",
+	"scopeId":     "issues.scope_id",
+	"version":     "issues.version",
+	"createdAt":   "issues.created_at",
+	"updatedAt":   "issues.updated_at",
+}
+
+// ParseSort parses a comma separated list of fields, a leading "-" sorts a field in descending order.
+// e.g. severity,-updatedAt
+func ParseSort(value string) ([]SortField, error) {
+	var fields []SortField
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortableFields[field.Field]; !ok {
+			return nil, fmt.Errorf("cannot sort by %q", field.Field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// ParseFields parses a comma separated list of issue fields to return
+func ParseFields(value string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if _, ok := selectableFields[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// ParseIncludes parses a comma separated list of associations to load: scope, links and related
+func ParseIncludes(value string) (IssueIncludes, error) {
+	var includes IssueIncludes
+	for _, include := range strings.Split(value, ",") {
+		switch strings.TrimSpace(include) {
+		case "scope":
+			includes.Scope = true
+		case "links":
+			includes.Links = true
+		case "related":
+			includes.Related = true
+		case "":
+		default:
+			return includes, fmt.Errorf("cannot include %q", include)
+		}
+	}
+	return includes, nil
+}
+
+// Helper function to build an expression that orders severities by rank instead of alphabetically
+func severityRankSQL() string {
+	var sql strings.Builder
+	sql.WriteString("CASE issues.severity")
+	for rank, severity := range models.Severities {
+		fmt.Fprintf(&sql, " WHEN '%s' THEN %d", severity, rank)
+	}
+	sql.WriteString(" END")
+	return sql.String()
+}
+
+// Helper function to apply the sort fields to a query.
+// The default order is always appended so issues with equal sort values keep a stable order.
+func applySort(query *gorm.DB, sort []SortField) *gorm.DB {
+	for _, field := range sort {
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		query = query.Order(fmt.Sprintf("%s %s NULLS LAST", sortableFields[field.Field], direction))
+	}
+	return query.Order("issues.detected_at DESC, issues.id DESC")
+}
+
+// Helper function to only select the fields requested, along with the columns needed to load associations
+func applyFields(query *gorm.DB, fields []string, includes IssueIncludes) *gorm.DB {
+	if len(fields) == 0 {
+		return query
+	}
+
+	// Associations and cursors are resolved using these
+	required := []string{"id", "detectedAt"}
+	if includes.Scope {
+		required = append(required, "scopeId")
+	}
+
+	var columns []string
+	for _, field := range append(required, fields...) {
+		if column := selectableFields[field]; !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return query.Select(columns)
+}
+
+// Helper function to preload the associations requested
+func applyIncludes(query *gorm.DB, includes IssueIncludes) *gorm.DB {
+	if includes.Scope {
+		query = query.Preload("Scope")
+	}
+	if includes.Links {
+		query = query.Preload("Links")
+	}
+	if includes.Related {
+		query = query.
+			Preload("RelatedFrom.Target.Scope").
+			Preload("RelatedTo.Source.Scope")
+	}
+	return query
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqlRecorder keeps the SQL of every statement run, with its values inlined
+type sqlRecorder struct {
+	logger.Interface
+	statements *[]string
+}
+
+func (r sqlRecorder) LogMode(logger.LogLevel) logger.Interface { return r }
+
+func (r sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	*r.statements = append(*r.statements, sql)
+}
+
+// dryRunPool lets dry run statements run in transactions, without a database to connect to
+type dryRunPool struct {
+	gorm.ConnPool
+}
+
+func (p *dryRunPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) { return p, nil }
+func (*dryRunPool) Commit() error                                                    { return nil }
+func (*dryRunPool) Rollback() error                                                  { return nil }
+
+// Helper function to open a Postgres database in dry run mode, statements are built and recorded but never run
+func dryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	var statements []string
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunPool{}}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               sqlRecorder{Interface: logger.Discard, statements: &statements},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, &statements
+}
+
+// Helper function to get the SQL listing issues with a query built by the function passed
+func listSQL(t *testing.T, build func(query *gorm.DB) *gorm.DB) string {
+	db, _ := dryRunDB(t)
+	return db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return build(tx.Model(&models.Issue{})).Find(&[]models.Issue{})
+	})
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []SortField
+		wantErr string
+	}{
+		{value: "severity", want: []SortField{{Field: "severity"}}},
+		{value: "severity,-updatedAt", want: []SortField{{Field: "severity"}, {Field: "updatedAt", Desc: true}}},
+		{value: " title , ,-detectedAt ", want: []SortField{{Field: "title"}, {Field: "detectedAt", Desc: true}}},
+		{value: "", want: nil},
+		{value: "description", wantErr: `cannot sort by "description"`},
+		{value: "severity,issues.id; DROP TABLE issues", wantErr: `cannot sort by "issues.id; DROP TABLE issues"`},
+		{value: "Severity", wantErr: `cannot sort by "Severity"`},
+		{value: "-", wantErr: `cannot sort by ""`},
+		{value: "--title", wantErr: `cannot sort by "-title"`},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSort(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ParseSort(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSort(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseSort(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	got, err := ParseFields("title, severity,,title,scopeId")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"title", "severity", "scopeId"}; !slices.Equal(got, want) {
+		t.Errorf("ParseFields() = %v, want %v", got, want)
+	}
+
+	// Associations are chosen with include, not fields
+	for _, value := range []string{"scope", "links", "search_vector", "title,password"} {
+		if _, err := ParseFields(value); err == nil {
+			t.Errorf("ParseFields(%q) should fail", value)
+		}
+	}
+}
+
+func TestParseIncludes(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    IssueIncludes
+		wantErr bool
+	}{
+		{value: "", want: IssueIncludes{}},
+		{value: "scope", want: IssueIncludes{Scope: true}},
+		{value: "links, related", want: IssueIncludes{Links: true, Related: true}},
+		{value: "scope,links,related", want: AllIssueIncludes},
+		{value: "scope,comments", wantErr: true},
+		{value: "Links", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseIncludes(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseIncludes(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseIncludes(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestApplySort(t *testing.T) {
+	tests := []struct {
+		name      string
+		sort      []SortField
+		wantOrder string
+	}{
+		{
+			name:      "default order",
+			wantOrder: "ORDER BY issues.detected_at DESC, issues.id DESC",
+		},
+		{
+			name:      "severity by rank",
+			sort:      []SortField{{Field: "severity", Desc: true}},
+			wantOrder: "ORDER BY CASE issues.severity WHEN 'info' THEN 0 WHEN 'minor' THEN 1 WHEN 'major' THEN 2 WHEN 'critical' THEN 3 END DESC NULLS LAST,issues.detected_at DESC, issues.id DESC",
+		},
+		{
+			name:      "several fields",
+			sort:      []SortField{{Field: "assignee"}, {Field: "updatedAt", Desc: true}},
+			wantOrder: "ORDER BY issues.assignee ASC NULLS LAST,issues.updated_at DESC NULLS LAST,issues.detected_at DESC, issues.id DESC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := listSQL(t, func(query *gorm.DB) *gorm.DB { return applySort(query, tt.sort) })
+			if !strings.HasSuffix(sql, tt.wantOrder) {
+				t.Errorf("SQL = %s, want it to end with %s", sql, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestApplyFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		fields     []string
+		includes   IssueIncludes
+		wantSelect string
+	}{
+		{name: "all fields", includes: AllIssueIncludes, wantSelect: `SELECT * FROM "issues"`},
+		{name: "fields without associations", fields: []string{"title", "severity"}, wantSelect: `SELECT issues.id,issues.detected_at,issues.title,issues.severity FROM "issues"`},
+		{name: "scope needs its ID", fields: []string{"title"}, includes: IssueIncludes{Scope: true}, wantSelect: `SELECT issues.id,issues.detected_at,issues.scope_id,issues.title FROM "issues"`},
+		{name: "required fields aren't repeated", fields: []string{"id", "scopeId"}, includes: IssueIncludes{Scope: true}, wantSelect: `SELECT issues.id,issues.detected_at,issues.scope_id FROM "issues"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := listSQL(t, func(query *gorm.DB) *gorm.DB { return applyFields(query, tt.fields, tt.includes) })
+			if sql != tt.wantSelect {
+				t.Errorf("SQL = %s, want %s", sql, tt.wantSelect)
+			}
+		})
+	}
+}
+
+func TestApplyIncludes(t *testing.T) {
+	tests := []struct {
+		includes IssueIncludes
+		want     []string
+	}{
+		{includes: IssueIncludes{}, want: nil},
+		{includes: IssueIncludes{Scope: true}, want: []string{"Scope"}},
+		{includes: IssueIncludes{Links: true, Related: true}, want: []string{"Links", "RelatedFrom.Target.Scope", "RelatedTo.Source.Scope"}},
+		{includes: AllIssueIncludes, want: []string{"Links", "RelatedFrom.Target.Scope", "RelatedTo.Source.Scope", "Scope"}},
+	}
+
+	db, _ := dryRunDB(t)
+	for _, tt := range tests {
+		query := applyIncludes(db.Model(&models.Issue{}), tt.includes)
+		var preloads []string
+		for preload := range query.Statement.Preloads {
+			preloads = append(preloads, preload)
+		}
+		slices.Sort(preloads)
+		if !slices.Equal(preloads, tt.want) {
+			t.Errorf("includes %+v preload %v, want %v", tt.includes, preloads, tt.want)
+		}
+	}
+}
@@ -2,14 +2,19 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"time"
 
-	"github.com/konflux-ci/kite/internal/handlers/dto"
-	"github.com/konflux-ci/kite/internal/models"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type issueRepository struct {
@@ -25,6 +30,9 @@ func NewIssueRepository(db *gorm.DB, logger *logrus.Logger) IssueRepository {
 	}
 }
 
+// ErrVersionMismatch is returned when an issue written with an expected version has been changed since
+var ErrVersionMismatch = apperrors.New(apperrors.ErrPreconditionFailed, "the issue has been changed since it was fetched")
+
 type DuplicateCheckResult struct {
 	IsDuplicate   bool
 	ExistingIssue *models.Issue
@@ -58,6 +66,8 @@ func (i *issueRepository) CheckDuplicate(ctx context.Context, req dto.CreateIssu
 }
 
 type IssueQueryFilters struct {
+	// IDs limits the issues to those with one of the IDs, when not empty
+	IDs          []string
 	Namespace    string
 	Severity     *models.Severity
 	IssueType    *models.IssueType
@@ -65,52 +75,122 @@ type IssueQueryFilters struct {
 	ResourceType string
 	ResourceName string
 	Search       string
-	Limit        int
-	Offset       int
+	// Query is a parsed q filter, applied along with the other filters
+	Query  *IssueQuery
+	Limit  int
+	Offset int
+	// Cursor switches to keyset pagination, Offset is ignored when set.
+	// Cursors can only be used with the default sort.
+	Cursor *Cursor
+	Sort   []SortField
+	// Fields limits the issue fields loaded, all fields are loaded when empty
+	Fields []string
+	// Include limits the associations loaded, all associations are loaded when nil
+	Include *IssueIncludes
 }
 
-func (i *issueRepository) FindAll(ctx context.Context, filters IssueQueryFilters) ([]models.Issue, int64, error) {
-	var issues []models.Issue
-	var total int64
+// Includes returns the associations to load
+func (f IssueQueryFilters) Includes() IssueIncludes {
+	if f.Include == nil {
+		return AllIssueIncludes
+	}
+	return *f.Include
+}
 
-	// Build base query
-	// Preload any associations
-	query := i.db.WithContext(ctx).Model(&models.Issue{}).
-		Preload("Scope").
-		Preload("Links").
-		Preload("RelatedFrom.Target.Scope").
-		Preload("RelatedTo.Source.Scope")
+// Matches reports whether an issue passes the filters, the same way FindAll filters issues in the database.
+// Pagination is ignored, and searches are approximated since full-text matching happens in the database.
+func (f IssueQueryFilters) Matches(issue *models.Issue) bool {
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, issue.ID) {
+		return false
+	}
+	if f.Namespace != "" && issue.Namespace != f.Namespace {
+		return false
+	}
+	if f.Severity != nil && issue.Severity != *f.Severity {
+		return false
+	}
+	if f.IssueType != nil && issue.IssueType != *f.IssueType {
+		return false
+	}
+	if f.State != nil && issue.State != *f.State {
+		return false
+	}
+	if f.ResourceType != "" && issue.Scope.ResourceType != f.ResourceType {
+		return false
+	}
+	if f.ResourceName != "" && issue.Scope.ResourceName != f.ResourceName {
+		return false
+	}
+	if f.Search != "" && !matchesSearch(issue, f.Search) {
+		return false
+	}
+	if f.Query != nil && !f.Query.Matches(issue, time.Now()) {
+		return false
+	}
+	return true
+}
 
-	// Apply filters to the database query
+// Helper function to check if the filters need issue_scopes joined
+func (f IssueQueryFilters) joinsScope() bool {
+	return f.ResourceType != "" || f.ResourceName != "" || (f.Query != nil && f.Query.scope)
+}
+
+// Helper function to apply the filters to a query, joining issue_scopes if requested.
+// Pagination, sorting and projection are left to the caller.
+func applyFilters(query *gorm.DB, filters IssueQueryFilters, joinScope bool) *gorm.DB {
+	if len(filters.IDs) > 0 {
+		query = query.Where("issues.id IN ?", filters.IDs)
+	}
 	if filters.Namespace != "" {
-		query = query.Where("namespace = ?", filters.Namespace)
+		query = query.Where("issues.namespace = ?", filters.Namespace)
 	}
 	if filters.Severity != nil {
-		query = query.Where("severity = ?", *filters.Severity)
+		query = query.Where("issues.severity = ?", *filters.Severity)
 	}
 	if filters.IssueType != nil {
-		query = query.Where("issue_type = ?", *filters.IssueType)
+		query = query.Where("issues.issue_type = ?", *filters.IssueType)
 	}
 	if filters.State != nil {
-		query = query.Where("state = ?", *filters.State)
+		query = query.Where("issues.state = ?", *filters.State)
+	}
+	// Scope filters share a single join
+	if joinScope {
+		query = query.Joins("JOIN issue_scopes ON issues.scope_id = issue_scopes.id")
 	}
 	if filters.ResourceType != "" {
-		query = query.Joins("JOIN issue_scopes ON issues.scope_id = issue_scopes.id").
-			Where("issue_scopes.resource_type = ?", filters.ResourceType)
+		query = query.Where("issue_scopes.resource_type = ?", filters.ResourceType)
 	}
 	if filters.ResourceName != "" {
-		query = query.Joins("JOIN issue_scopes ON issues.scope_id = issue_scopes.id").
-			Where("issue_scopes.resource_name = ?", filters.ResourceName)
+		query = query.Where("issue_scopes.resource_name = ?", filters.ResourceName)
 	}
 	if filters.Search != "" {
-		searchPattern := "%" + filters.Search + "%"
-		query = query.Where("title ILIKE ? OR description ILIKE ?", searchPattern, searchPattern)
+		query = applySearch(query, filters.Search)
+	}
+	if filters.Query != nil {
+		query = filters.Query.apply(query, time.Now())
+	}
+	return query
+}
+
+func (i *issueRepository) FindAll(ctx context.Context, filters IssueQueryFilters) (*IssuePage, error) {
+	var issues []models.Issue
+	var total int64
+
+	if filters.Cursor != nil && len(filters.Sort) > 0 {
+		return nil, ErrCursorWithSort
 	}
 
+	// Build base query
+	query := i.db.WithContext(ctx).Model(&models.Issue{})
+
+	// Apply filters to the database query
+	joinScope := filters.joinsScope()
+	query = applyFilters(query, filters, joinScope)
+
 	// Get total count for pagination
 	if err := query.Count(&total).Error; err != nil {
 		i.logger.WithError(err).Error("Failed to count issues")
-		return nil, 0, fmt.Errorf("failed to count issues: %w", err)
+		return nil, fmt.Errorf("failed to count issues: %w", err)
 	}
 
 	// Apply pagination and ordering
@@ -118,16 +198,115 @@ func (i *issueRepository) FindAll(ctx context.Context, filters IssueQueryFilters
 		filters.Limit = 50
 	}
 
-	if err := query.Order("detected_at DESC").
+	// Don't let the scope's columns overwrite the issue's
+	if joinScope {
+		query = query.Select("issues.*")
+	}
+
+	// Preload only the associations and fields requested
+	query = applyIncludes(query, filters.Includes())
+	query = applyFields(query, filters.Fields, filters.Includes())
+
+	if filters.Cursor != nil {
+		page, err := i.findPageByCursor(query, filters, total)
+		if err != nil {
+			return nil, err
+		}
+		if err := i.highlight(ctx, page, filters.Search); err != nil {
+			return nil, err
+		}
+		return page, nil
+	}
+
+	// Without an explicit sort, searches return the best matches first
+	if filters.Search != "" && len(filters.Sort) == 0 {
+		query = orderByRank(query, filters.Search)
+	} else {
+		query = applySort(query, filters.Sort)
+	}
+
+	if err := query.
 		Offset(filters.Offset).
 		Limit(filters.Limit).
 		Find(&issues).
 		Error; err != nil {
 		i.logger.WithError(err).Error("Failed to find issues")
-		return nil, 0, fmt.Errorf("failed to find issues: %w", err)
+		return nil, fmt.Errorf("failed to find issues: %w", err)
 	}
 
-	return issues, total, nil
+	page := &IssuePage{Issues: issues, Total: total}
+	// Offset pages also return cursors so clients can switch to keyset pagination,
+	// unless they're ranked by relevance since cursors follow the default order
+	if len(issues) > 0 && len(filters.Sort) == 0 && filters.Search == "" {
+		if int64(filters.Offset+len(issues)) < total {
+			page.NextCursor = nextCursor(issues[len(issues)-1])
+		}
+		if filters.Offset > 0 {
+			page.PrevCursor = prevCursor(issues[0])
+		}
+	}
+	if err := i.highlight(ctx, page, filters.Search); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Helper function to add highlights to a page of search results
+func (i *issueRepository) highlight(ctx context.Context, page *IssuePage, search string) error {
+	if search == "" {
+		return nil
+	}
+	if err := i.loadHighlights(ctx, page.Issues, search); err != nil {
+		i.logger.WithError(err).Error("Failed to highlight issues")
+		return err
+	}
+	return nil
+}
+
+// Helper function to find a page of issues with keyset pagination.
+//
+// Rather than skipping rows, the query seeks straight to the cursor using the
+// (detected_at, id) indexes, so pages stay fast and consistent while new issues arrive.
+func (i *issueRepository) findPageByCursor(query *gorm.DB, filters IssueQueryFilters, total int64) (*IssuePage, error) {
+	var issues []models.Issue
+	cursor := filters.Cursor
+
+	// Fetch one extra row to know if there's another page
+	if cursor.Backward {
+		query = query.
+			Where("(issues.detected_at, issues.id) > (?, ?)", cursor.DetectedAt, cursor.ID).
+			Order("issues.detected_at ASC, issues.id ASC")
+	} else {
+		query = query.
+			Where("(issues.detected_at, issues.id) < (?, ?)", cursor.DetectedAt, cursor.ID).
+			Order("issues.detected_at DESC, issues.id DESC")
+	}
+	if err := query.Limit(filters.Limit + 1).Find(&issues).Error; err != nil {
+		i.logger.WithError(err).Error("Failed to find issues")
+		return nil, fmt.Errorf("failed to find issues: %w", err)
+	}
+
+	hasMore := len(issues) > filters.Limit
+	if hasMore {
+		issues = issues[:filters.Limit]
+	}
+	if cursor.Backward {
+		slices.Reverse(issues)
+	}
+
+	page := &IssuePage{Issues: issues, Total: total}
+	if len(issues) == 0 {
+		return page, nil
+	}
+
+	// Whichever direction we came from always has more issues
+	if hasMore || cursor.Backward {
+		page.NextCursor = nextCursor(issues[len(issues)-1])
+	}
+	if hasMore || !cursor.Backward {
+		page.PrevCursor = prevCursor(issues[0])
+	}
+	return page, nil
 }
 
 func (i *issueRepository) FindByID(ctx context.Context, id string) (*models.Issue, error) {
@@ -137,6 +316,7 @@ func (i *issueRepository) FindByID(ctx context.Context, id string) (*models.Issu
 	err := i.db.
 		WithContext(ctx).
 		Preload("Scope").
+		Preload("Links", func(db *gorm.DB) *gorm.DB { return db.Order("links.title, links.id") }).
 		Preload("RelatedFrom.Target.Scope").
 		Preload("RelatedTo.Source.Scope").
 		First(&issue, "id = ?", id).Error
@@ -152,6 +332,33 @@ func (i *issueRepository) FindByID(ctx context.Context, id string) (*models.Issu
 	return &issue, nil
 }
 
+// FindScopes returns the scopes with the IDs passed
+func (i *issueRepository) FindScopes(ctx context.Context, ids []string) ([]models.IssueScope, error) {
+	var scopes []models.IssueScope
+	if err := i.db.WithContext(ctx).Where("id IN ?", ids).Find(&scopes).Error; err != nil {
+		return nil, fmt.Errorf("failed to find scopes: %w", err)
+	}
+	return scopes, nil
+}
+
+// FindLinks returns the links of the issues passed, ordered the same way as when loading a single issue
+func (i *issueRepository) FindLinks(ctx context.Context, issueIDs []string) ([]models.Link, error) {
+	var links []models.Link
+	if err := i.db.WithContext(ctx).Where("issue_id IN ?", issueIDs).Order("links.title, links.id").Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to find links: %w", err)
+	}
+	return links, nil
+}
+
+// FindRelations returns the relationships of the issues passed, in either direction
+func (i *issueRepository) FindRelations(ctx context.Context, issueIDs []string) ([]models.RelatedIssue, error) {
+	var relations []models.RelatedIssue
+	if err := i.db.WithContext(ctx).Where("source_id IN ? OR target_id IN ?", issueIDs, issueIDs).Find(&relations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find related issues: %w", err)
+	}
+	return relations, nil
+}
+
 func (i *issueRepository) Create(ctx context.Context, req dto.CreateIssueRequest) (*models.Issue, error) {
 	// check for duplicates
 	duplicateResult, err := i.CheckDuplicate(ctx, req)
@@ -171,7 +378,7 @@ func (i *issueRepository) Create(ctx context.Context, req dto.CreateIssueRequest
 		if req.State != "" {
 			updateReq.State = &req.State
 		}
-		return i.Update(ctx, duplicateResult.ExistingIssue.ID, updateReq)
+		return i.Update(ctx, duplicateResult.ExistingIssue.ID, updateReq, 0)
 	}
 
 	// Create new issue
@@ -216,7 +423,7 @@ func (i *issueRepository) Create(ctx context.Context, req dto.CreateIssueRequest
 		if err := tx.Create(&issue).Error; err != nil {
 			return fmt.Errorf("failed to create issue: %w", err)
 		}
-		return nil
+		return i.recordEvent(tx, models.EventTypeIssueCreated, issue.ID)
 	})
 
 	if err != nil {
@@ -230,19 +437,22 @@ func (i *issueRepository) Create(ctx context.Context, req dto.CreateIssueRequest
 	return i.FindByID(ctx, issue.ID)
 }
 
-func (i *issueRepository) Update(ctx context.Context, id string, req dto.UpdateIssueRequest) (*models.Issue, error) {
+// Update changes the fields set in the request. If version isn't 0 the issue is only updated
+// if it's still at that version, otherwise ErrVersionMismatch is returned.
+func (i *issueRepository) Update(ctx context.Context, id string, req dto.UpdateIssueRequest, version int64) (*models.Issue, error) {
 	// Find existing issue
 	existingIssue, err := i.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if existingIssue == nil {
-		return nil, fmt.Errorf("issue with ID %s not found", id)
+		return nil, ErrIssueNotFound
 	}
 
 	// Prepare updates
 	updates := map[string]interface{}{
 		"updated_at": time.Now(),
+		"version":    gorm.Expr("version + 1"),
 	}
 
 	if req.Title != nil {
@@ -273,9 +483,9 @@ func (i *issueRepository) Update(ctx context.Context, id string, req dto.UpdateI
 	// Perform updates in a transaction
 	// Update issue first, then links (if any)
 	err = i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Update issue
-		if err := tx.Model(&existingIssue).Updates(updates).Error; err != nil {
-			return fmt.Errorf("failed to update issue: %w", err)
+		// Update issue, checking the version in the same statement so nothing can change in between
+		if err := updateVersioned(tx, id, version, updates); err != nil {
+			return err
 		}
 
 		// Handle link updates if provided
@@ -297,7 +507,12 @@ func (i *issueRepository) Update(ctx context.Context, id string, req dto.UpdateI
 				}
 			}
 		}
-		return nil
+
+		eventType := models.EventTypeIssueUpdated
+		if req.State != nil && *req.State == models.IssueStateResolved && existingIssue.State != models.IssueStateResolved {
+			eventType = models.EventTypeIssueResolved
+		}
+		return i.recordEvent(tx, eventType, id)
 	})
 
 	if err != nil {
@@ -310,39 +525,116 @@ func (i *issueRepository) Update(ctx context.Context, id string, req dto.UpdateI
 	return i.FindByID(ctx, id)
 }
 
-func (i *issueRepository) Delete(ctx context.Context, id string) error {
-	// Find the issue to get scope ID
-	issue, err := i.FindByID(ctx, id)
-	if err != nil {
-		return err
+// Replace overwrites every editable field of an issue. Links with an ID are kept and updated,
+// other links of the issue are deleted and links without an ID are created.
+// Like Update, a version other than 0 is checked before the issue is replaced.
+func (i *issueRepository) Replace(ctx context.Context, id string, req dto.ReplaceIssueRequest, version int64) (*models.Issue, error) {
+	labels := req.Labels
+	if labels == nil {
+		labels = []string{}
 	}
-	if issue == nil {
-		return fmt.Errorf("issue with ID %s not found", id)
+	encodedLabels, err := json.Marshal(labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode labels: %w", err)
 	}
 
-	// Delete in transaction so we have control of the order
 	err = i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Delete related issue relationships first using issue id
-		if err := tx.Where("source_id = ? OR target_id = ?", id, id).Delete(&models.RelatedIssue{}).Error; err != nil {
-			return fmt.Errorf("failed to delete related issues: %w", err)
+		// Lock the issue so the state it's changing from is known
+		var existing models.Issue
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&existing, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("failed to find issue to replace: %w", err)
 		}
 
-		// Delete links by issue id
-		if err := tx.Where("issue_id = ?", id).Delete(&models.Link{}).Error; err != nil {
-			return fmt.Errorf("failed to delete links: %w", err)
+		if err := updateVersioned(tx, id, version, map[string]any{
+			"title":       req.Title,
+			"description": req.Description,
+			"severity":    req.Severity,
+			"issue_type":  req.IssueType,
+			"state":       req.State,
+			"resolved_at": req.ResolvedAt,
+			"assignee":    req.Assignee,
+			"labels":      gorm.Expr("?::jsonb", string(encodedLabels)),
+			"updated_at":  time.Now(),
+			"version":     gorm.Expr("version + 1"),
+		}); err != nil {
+			return err
 		}
 
-		// Delete the issue by id
-		if err := tx.Delete(&models.Issue{}, "id = ?", id).Error; err != nil {
-			return fmt.Errorf("failed to delete issue: %w", err)
+		// Links kept are identified by ID, the rest are replaced
+		var kept []string
+		for _, linkReq := range req.Links {
+			if linkReq.ID != "" {
+				kept = append(kept, linkReq.ID)
+			}
+		}
+		deleteLinks := tx.Where("issue_id = ?", id)
+		if len(kept) > 0 {
+			deleteLinks = deleteLinks.Where("id NOT IN ?", kept)
+		}
+		if err := deleteLinks.Delete(&models.Link{}).Error; err != nil {
+			return fmt.Errorf("failed to delete old links: %w", err)
+		}
+		for idx, linkReq := range req.Links {
+			if linkReq.ID != "" {
+				result := tx.Model(&models.Link{}).
+					Where("id = ? AND issue_id = ?", linkReq.ID, id).
+					Updates(map[string]any{"title": linkReq.Title, "url": linkReq.URL})
+				if result.Error != nil {
+					return fmt.Errorf("failed to update link: %w", result.Error)
+				}
+				// The link was moved or deleted since the request was validated, or was never this issue's
+				if result.RowsAffected == 0 {
+					return apperrors.InvalidField(fmt.Sprintf("links[%d].id", idx), fmt.Sprintf("link %s does not belong to this issue", linkReq.ID))
+				}
+				continue
+			}
+			link := models.Link{
+				Title:   linkReq.Title,
+				URL:     linkReq.URL,
+				IssueID: id,
+			}
+			if err := tx.Create(&link).Error; err != nil {
+				return fmt.Errorf("failed to create link: %w", err)
+			}
 		}
 
-		// Delete the issue scope by scope id
-		if err := tx.Delete(&models.IssueScope{}, "id = ?", issue.ScopeID).Error; err != nil {
-			return fmt.Errorf("failed to delete issue scope: %w", err)
+		eventType := models.EventTypeIssueUpdated
+		if req.State == models.IssueStateResolved && existing.State != models.IssueStateResolved {
+			eventType = models.EventTypeIssueResolved
 		}
+		return i.recordEvent(tx, eventType, id)
+	})
 
-		return nil
+	if err != nil {
+		i.logger.WithError(err).WithField("issue_id", id).Error("Failed to replace issue")
+		return nil, err
+	}
+
+	i.logger.WithField("issue_id", id).Info("Replaced issue")
+
+	return i.FindByID(ctx, id)
+}
+
+// Delete removes an issue along with its links, relationships and scope.
+// Like Update, a version other than 0 is checked before the issue is deleted.
+func (i *issueRepository) Delete(ctx context.Context, id string, version int64) error {
+	// Find the issue to get scope ID
+	issue, err := i.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return ErrIssueNotFound
+	}
+
+	// Delete in transaction so we have control of the order
+	err = i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return i.deleteIssue(tx, id, issue.ScopeID, version)
 	})
 
 	if err != nil {
@@ -356,23 +648,49 @@ func (i *issueRepository) Delete(ctx context.Context, id string) error {
 
 func (i *issueRepository) ResolveByScope(ctx context.Context, resourceType, resourceName, namespace string) (int64, error) {
 	now := time.Now()
+	var count int64
+
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the matching issues so we know exactly which ones we resolve
+		var ids []string
+		if err := tx.Model(&models.Issue{}).
+			Joins("JOIN issue_scopes ON issues.scope_id = issue_scopes.id").
+			Where("issues.state = ? AND issues.namespace = ?", models.IssueStateActive, namespace).
+			Where("issue_scopes.resource_type = ? AND issue_scopes.resource_name = ?", resourceType, resourceName).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "issues"}}).
+			Pluck("issues.id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to find issues to resolve: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
 
-	result := i.db.WithContext(ctx).Model(&models.Issue{}).
-		Joins("JOIN issue_scopes ON issues.scope_id = issue_scopes.id").
-		Where("issues.state = ? AND issues.namespace = ?", models.IssueStateActive, namespace).
-		Where("issue_scopes.resource_type = ? AND issue_scopes.resource_name = ?", resourceType, resourceName).
-		Updates(map[string]any{
-			"state":       models.IssueStateResolved,
-			"resolved_at": &now,
-			"updated_at":  now,
-		})
+		result := tx.Model(&models.Issue{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"state":       models.IssueStateResolved,
+				"resolved_at": &now,
+				"updated_at":  now,
+				"version":     gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
 
-	if result.Error != nil {
-		i.logger.WithError(result.Error).Error("Failed to resolve issues by scope")
-		return 0, fmt.Errorf("failed to resolve issues: %w", result.Error)
+		for _, id := range ids {
+			if err := i.recordEvent(tx, models.EventTypeIssueResolved, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		i.logger.WithError(err).Error("Failed to resolve issues by scope")
+		return 0, fmt.Errorf("failed to resolve issues: %w", err)
 	}
 
-	count := result.RowsAffected
 	i.logger.WithFields(logrus.Fields{
 		"resource_type": resourceType,
 		"resource_name": resourceName,
@@ -395,7 +713,7 @@ func (i *issueRepository) AddRelatedIssue(ctx context.Context, sourceID, targetI
 		return err
 	}
 	if source == nil || target == nil {
-		return errors.New("one or both issues not found")
+		return ErrRelatedIssueNotFound
 	}
 
 	// Check if relationship already exists
@@ -404,7 +722,7 @@ func (i *issueRepository) AddRelatedIssue(ctx context.Context, sourceID, targetI
 		sourceID, targetID, targetID, sourceID).First(&existingRelation).Error
 
 	if err == nil {
-		return errors.New("relationship already exists")
+		return ErrRelationshipExists
 	}
 	// Check if we get any other error besides Record Not Found
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -417,7 +735,13 @@ func (i *issueRepository) AddRelatedIssue(ctx context.Context, sourceID, targetI
 		TargetID: targetID,
 	}
 
-	if err := i.db.WithContext(ctx).Create(&relation).Error; err != nil {
+	err = i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&relation).Error; err != nil {
+			return err
+		}
+		return bumpVersions(tx, sourceID, targetID)
+	})
+	if err != nil {
 		i.logger.WithError(err).Error("Failed to add related issue")
 		return fmt.Errorf("failed to create relationship: %w", err)
 	}
@@ -431,16 +755,24 @@ func (i *issueRepository) AddRelatedIssue(ctx context.Context, sourceID, targetI
 
 // RemoveRelatedIssue removes a relationship between issues
 func (i *issueRepository) RemoveRelatedIssue(ctx context.Context, sourceID, targetID string) error {
-	result := i.db.WithContext(ctx).Where("(source_id = ? AND target_id = ?) OR (source_id = ? AND target_id = ?)",
-		sourceID, targetID, targetID, sourceID).Delete(&models.RelatedIssue{})
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("(source_id = ? AND target_id = ?) OR (source_id = ? AND target_id = ?)",
+			sourceID, targetID, targetID, sourceID).Delete(&models.RelatedIssue{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRelationshipNotFound
+		}
+		return bumpVersions(tx, sourceID, targetID)
+	})
 
-	if result.Error != nil {
-		i.logger.WithError(result.Error).Error("failed to remove related issue")
-		return fmt.Errorf("failed to remove relationship: %w", result.Error)
+	if errors.Is(err, ErrRelationshipNotFound) {
+		return err
 	}
-
-	if result.RowsAffected == 0 {
-		return errors.New("relationship not found")
+	if err != nil {
+		i.logger.WithError(err).Error("failed to remove related issue")
+		return fmt.Errorf("failed to remove relationship: %w", err)
 	}
 
 	i.logger.WithFields(logrus.Fields{
@@ -450,3 +782,122 @@ func (i *issueRepository) RemoveRelatedIssue(ctx context.Context, sourceID, targ
 
 	return nil
 }
+
+// FindActiveWithoutLink finds active issues with one of the severities passed
+// that don't have a link starting with linkPrefix
+func (i *issueRepository) FindActiveWithoutLink(ctx context.Context, linkPrefix string, severities []models.Severity) ([]models.Issue, error) {
+	var issues []models.Issue
+
+	err := i.db.WithContext(ctx).
+		Preload("Scope").
+		Where("state = ? AND severity IN ?", models.IssueStateActive, severities).
+		Where("NOT EXISTS (SELECT 1 FROM links WHERE links.issue_id = issues.id AND links.url LIKE ?)", escapeLike(linkPrefix)+"%").
+		Order("detected_at ASC").
+		Find(&issues).Error
+	if err != nil {
+		i.logger.WithError(err).Error("Failed to find issues without link")
+		return nil, fmt.Errorf("failed to find issues without link: %w", err)
+	}
+	return issues, nil
+}
+
+// updateVersioned updates an issue as part of the transaction passed.
+// When version isn't 0 the issue is only updated if it's at that version, otherwise ErrVersionMismatch is returned.
+func updateVersioned(tx *gorm.DB, id string, version int64, updates map[string]any) error {
+	query := tx.Model(&models.Issue{}).Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update issue: %w", result.Error)
+	}
+	if version != 0 && result.RowsAffected == 0 {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// bumpVersions increments the version of issues whose associations changed, as part of the transaction passed
+func bumpVersions(tx *gorm.DB, ids ...string) error {
+	if err := tx.Model(&models.Issue{}).Where("id IN ?", ids).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+		return fmt.Errorf("failed to update issue versions: %w", err)
+	}
+	return nil
+}
+
+// deleteIssue deletes an issue along with everything belonging to it, as part of the transaction passed.
+// When version isn't 0 nothing is deleted unless the issue is at that version.
+func (i *issueRepository) deleteIssue(tx *gorm.DB, id, scopeID string, version int64) error {
+	// Record the event first, while the issue can still be loaded
+	if err := i.recordEvent(tx, models.EventTypeIssueDeleted, id); err != nil {
+		return err
+	}
+
+	// Delete related issue relationships first using issue id
+	if err := tx.Where("source_id = ? OR target_id = ?", id, id).Delete(&models.RelatedIssue{}).Error; err != nil {
+		return fmt.Errorf("failed to delete related issues: %w", err)
+	}
+
+	// Delete links by issue id
+	if err := tx.Where("issue_id = ?", id).Delete(&models.Link{}).Error; err != nil {
+		return fmt.Errorf("failed to delete links: %w", err)
+	}
+
+	// Delete the issue by id, the transaction is rolled back if it has changed
+	query := tx.Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+	result := query.Delete(&models.Issue{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete issue: %w", result.Error)
+	}
+	if version != 0 && result.RowsAffected == 0 {
+		return ErrVersionMismatch
+	}
+
+	// Delete the issue scope by scope id
+	if err := tx.Delete(&models.IssueScope{}, "id = ?", scopeID).Error; err != nil {
+		return fmt.Errorf("failed to delete issue scope: %w", err)
+	}
+
+	return nil
+}
+
+// recordEvent writes an outbox event for an issue as part of the transaction passed,
+// so the event only exists if the change it describes is committed
+func (i *issueRepository) recordEvent(tx *gorm.DB, eventType, issueID string) error {
+	var issue models.Issue
+	if err := tx.
+		Preload("Scope").
+		Preload("Links").
+		Preload("RelatedFrom.Target.Scope").
+		Preload("RelatedTo.Source.Scope").
+		First(&issue, "id = ?", issueID).Error; err != nil {
+		return fmt.Errorf("failed to load issue for event: %w", err)
+	}
+
+	payload, err := json.Marshal(issue)
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue for event: %w", err)
+	}
+
+	event := models.OutboxEvent{
+		Type:      eventType,
+		IssueID:   issue.ID,
+		Namespace: issue.Namespace,
+		Payload:   payload,
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+
+	// Notifications are only delivered once the transaction commits.
+	// The payload is just the event ID since notifications are limited to 8000 bytes.
+	if err := tx.Exec("SELECT pg_notify(?, ?)", EventNotifyChannel, strconv.FormatInt(event.ID, 10)).Error; err != nil {
+		return fmt.Errorf("failed to notify %s event: %w", eventType, err)
+	}
+	return nil
+}
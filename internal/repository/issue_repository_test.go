@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+func TestReplaceMissingIssue(t *testing.T) {
+	repo := NewIssueRepository(digestDB(t), quietLogger())
+
+	_, err := repo.Replace(context.Background(), "missing", dto.ReplaceIssueRequest{Title: "Build failed"}, 1)
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("error = %v, want not found", err)
+	}
+}
+
+func TestReplaceRejectsLinkOfAnotherIssue(t *testing.T) {
+	// No rows are ever matched in dry run mode, as for a kept link that belongs to another issue
+	db, _ := dryRunDB(t)
+	repo := &issueRepository{db: db, logger: quietLogger()}
+
+	_, err := repo.Replace(context.Background(), "issue-1", dto.ReplaceIssueRequest{
+		Title:    "Build failed",
+		Severity: models.SeverityMajor,
+		State:    models.IssueStateActive,
+		Links: []dto.ReplaceLinkRequest{
+			{Title: "New", URL: "https://logs.example.com/new"},
+			{ID: "link-of-issue-2", Title: "Logs", URL: "https://logs.example.com/2"},
+		},
+	}, 0)
+
+	var validationErr *apperrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+	if field := validationErr.Fields[0]; field.Field != "links[1].id" || field.Message != "link link-of-issue-2 does not belong to this issue" {
+		t.Errorf("error = %+v, want the kept link rejected", field)
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Marks the matched terms in search highlights
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+
+// Helper function to build the tsquery for a search, written the way users write web searches.
+// e.g. "pipeline failed" -timeout or "build OR deploy"
+func searchQuery(search string) clause.Expr {
+	return gorm.Expr("websearch_to_tsquery('english', ?)", search)
+}
+
+// Helper function to only match issues whose title, description or scope matches the search
+func applySearch(query *gorm.DB, search string) *gorm.DB {
+	return query.Where("issues.search_vector @@ ?", searchQuery(search))
+}
+
+// Helper function to order the best matches first, title matches are weighted above the description and scope.
+// This replaces applySort, the default order is kept as a tiebreak.
+func orderByRank(query *gorm.DB, search string) *gorm.DB {
+	return query.Order(clause.OrderBy{Expression: gorm.Expr(
+		"ts_rank(issues.search_vector, ?) DESC, issues.detected_at DESC, issues.id DESC", searchQuery(search),
+	)})
+}
+
+// Helper function to set the highlighted snippets on search results.
+// Headlines are expensive to generate, so they're only built for the page returned.
+func (i *issueRepository) loadHighlights(ctx context.Context, issues []models.Issue, search string) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(issues))
+	for idx, issue := range issues {
+		ids[idx] = issue.ID
+	}
+
+	var highlights []struct {
+		ID          string
+		Title       string
+		Description string
+	}
+	if err := i.db.WithContext(ctx).Model(&models.Issue{}).
+		Select(
+			"issues.id, ts_headline('english', issues.title, @query, @options) AS title, ts_headline('english', issues.description, @query, @options) AS description",
+			map[string]any{"query": searchQuery(search), "options": searchHeadlineOptions},
+		).
+		Where("issues.id IN ?", ids).
+		Scan(&highlights).Error; err != nil {
+		return fmt.Errorf("failed to highlight search results: %w", err)
+	}
+
+	byID := make(map[string]*models.IssueHighlight, len(highlights))
+	for _, highlight := range highlights {
+		byID[highlight.ID] = &models.IssueHighlight{Title: highlight.Title, Description: highlight.Description}
+	}
+	for idx := range issues {
+		issues[idx].Highlight = byID[issues[idx].ID]
+	}
+	return nil
+}
+
+// Helper function to approximate a search in memory for issues that aren't in the database yet.
+// Every search word has to appear in the issue, operators and stemming are ignored.
+func matchesSearch(issue *models.Issue, search string) bool {
+	text := strings.ToLower(strings.Join([]string{
+		issue.Title, issue.Description, issue.Scope.ResourceType, issue.Scope.ResourceName, issue.ScopeTerms,
+	}, " "))
+
+	for _, word := range strings.Fields(strings.ToLower(search)) {
+		word = strings.Trim(word, `"`)
+		if word == "or" || strings.HasPrefix(word, "-") || word == "" {
+			continue
+		}
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
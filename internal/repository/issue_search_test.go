@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/gorm"
+)
+
+func TestApplySearch(t *testing.T) {
+	db, _ := dryRunDB(t)
+	search := `"pipeline failed" -timeout'; DROP TABLE issues; --`
+	stmt := applySearch(db.Model(&models.Issue{}), search).Find(&[]models.Issue{}).Statement
+
+	want := `SELECT * FROM "issues" WHERE issues.search_vector @@ websearch_to_tsquery('english', $1)`
+	if sql := stmt.SQL.String(); sql != want {
+		t.Errorf("SQL = %s, want %s", sql, want)
+	}
+	// The search is passed as a parameter rather than written into the query
+	if len(stmt.Vars) != 1 || stmt.Vars[0] != search {
+		t.Errorf("vars = %v, want the search", stmt.Vars)
+	}
+}
+
+func TestOrderByRank(t *testing.T) {
+	sql := listSQL(t, func(query *gorm.DB) *gorm.DB { return orderByRank(query, "build OR deploy") })
+
+	// Best matches first, newest first among equal ranks
+	want := `ORDER BY ts_rank(issues.search_vector, websearch_to_tsquery('english', 'build OR deploy')) DESC, issues.detected_at DESC, issues.id DESC`
+	if !strings.HasSuffix(sql, want) {
+		t.Errorf("SQL = %s, want it to end with %s", sql, want)
+	}
+}
+
+func TestLoadHighlightsQuery(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := &issueRepository{db: db, logger: quietLogger()}
+
+	err := repo.loadHighlights(context.Background(), []models.Issue{{ID: "issue-1"}, {ID: "issue-2"}}, "it's failing")
+	if !errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+		t.Fatalf("error = %v, want the query to be built without running", err)
+	}
+	if len(*statements) != 1 {
+		t.Fatalf("ran %d statements, want 1", len(*statements))
+	}
+
+	sql := (*statements)[0]
+	query := `websearch_to_tsquery('english', 'it''s failing')`
+	for _, want := range []string{
+		"ts_headline('english', issues.title, " + query + ", '" + searchHeadlineOptions + "') AS title",
+		"ts_headline('english', issues.description, " + query + ", '" + searchHeadlineOptions + "') AS description",
+		// Headlines are only built for the page returned
+		"WHERE issues.id IN ('issue-1','issue-2')",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("SQL = %s, want it to contain %s", sql, want)
+		}
+	}
+}
+
+func TestLoadHighlightsWithoutIssues(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := &issueRepository{db: db, logger: quietLogger()}
+
+	if err := repo.loadHighlights(context.Background(), nil, "failing"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*statements) != 0 {
+		t.Errorf("ran %v for an empty page", *statements)
+	}
+}
+
+func TestMatchesSearch(t *testing.T) {
+	issue := &models.Issue{
+		Title:       "Build failed for component",
+		Description: "The pipeline timed out",
+		Scope:       models.IssueScope{ResourceType: "component", ResourceName: "frontend"},
+	}
+
+	tests := []struct {
+		search string
+		want   bool
+	}{
+		{search: "build", want: true},
+		{search: "BUILD Failed", want: true},
+		{search: "pipeline frontend", want: true},
+		{search: `"timed out"`, want: true},
+		{search: "build OR pipeline", want: true},
+		{search: "build -flaky", want: true},
+		{search: "deploy", want: false},
+		{search: "build deploy", want: false},
+		// Operators are ignored, every word still has to appear
+		{search: "build OR deploy", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchesSearch(issue, tt.search); got != tt.want {
+			t.Errorf("matchesSearch(%q) = %v, want %v", tt.search, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// GroupByDay buckets issues by the UTC day of the stats date field
+const GroupByDay = "day"
+
+// Maps the name of each field issues can be grouped by to the expression grouped on
+var groupableFields = map[string]string{
+	"severity":           "issues.severity",
+	"issueType":          "issues.issue_type",
+	"state":              "issues.state",
+	"namespace":          "issues.namespace",
+	"assignee":           "issues.assignee",
+	"scope.resourceType": "issue_scopes.resource_type",
+}
+
+// Maps the JSON name of each date issues can be bucketed by to its column
+var bucketableDates = map[string]string{
+	"detectedAt": "issues.detected_at",
+	"resolvedAt": "issues.resolved_at",
+}
+
+// IssueStatsOptions controls how issue counts are grouped
+type IssueStatsOptions struct {
+	// GroupBy lists the groupable fields and GroupByDay, issues are only counted when empty
+	GroupBy []string
+	// DateField is the date bucketed when grouping by day, detectedAt by default
+	DateField string
+}
+
+// IssueStatsGroup is the number of issues with the same value for each grouped field
+type IssueStatsGroup struct {
+	Key   map[string]*string
+	Count int64
+}
+
+// IssueStats holds issue counts, grouped by the requested fields
+type IssueStats struct {
+	Total  int64
+	Groups []IssueStatsGroup
+}
+
+// ParseGroupBy parses a comma separated list of fields to group issues by
+func ParseGroupBy(value string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if _, ok := groupableFields[field]; !ok && field != GroupByDay {
+			return nil, fmt.Errorf("cannot group by %q", field)
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// ParseDateField checks the date to bucket issues by when grouping by day
+func ParseDateField(value string) (string, error) {
+	if _, ok := bucketableDates[value]; !ok {
+		return "", fmt.Errorf("cannot bucket by %q", value)
+	}
+	return value, nil
+}
+
+// GetStats counts the issues matching the filters, grouped by the fields requested.
+// Pagination, sorting and projection filters are ignored.
+func (i *issueRepository) GetStats(ctx context.Context, filters IssueQueryFilters, options IssueStatsOptions) (*IssueStats, error) {
+	dateColumn := bucketableDates["detectedAt"]
+	if options.DateField != "" {
+		dateColumn = bucketableDates[options.DateField]
+	}
+
+	var groups, orders []string
+	joinScope := filters.joinsScope()
+	for _, field := range options.GroupBy {
+		switch field {
+		case GroupByDay:
+			day := fmt.Sprintf("to_char(%s AT TIME ZONE 'UTC', 'YYYY-MM-DD')", dateColumn)
+			groups = append(groups, day)
+			orders = append(orders, day)
+		case "severity":
+			groups = append(groups, groupableFields[field])
+			orders = append(orders, severityRankSQL())
+		default:
+			groups = append(groups, groupableFields[field])
+			orders = append(orders, groupableFields[field])
+		}
+		joinScope = joinScope || strings.HasPrefix(field, "scope.")
+	}
+
+	query := applyFilters(i.db.WithContext(ctx).Model(&models.Issue{}), filters, joinScope)
+	// Unresolved issues have no day to be bucketed into
+	if slices.Contains(options.GroupBy, GroupByDay) {
+		query = query.Where(dateColumn + " IS NOT NULL")
+	}
+
+	columns := make([]string, 0, len(groups)+1)
+	for idx, group := range groups {
+		columns = append(columns, fmt.Sprintf("%s AS g%d", group, idx))
+	}
+	columns = append(columns, "COUNT(*) AS count")
+	query = query.Select(strings.Join(columns, ", "))
+	if len(groups) > 0 {
+		query = query.Group(strings.Join(groups, ", ")).Order(strings.Join(orders, ", "))
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		i.logger.WithError(err).Error("Failed to count issues")
+		return nil, fmt.Errorf("failed to count issues: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &IssueStats{Groups: []IssueStatsGroup{}}
+	for rows.Next() {
+		values := make([]sql.NullString, len(groups))
+		dest := make([]any, 0, len(groups)+1)
+		for idx := range values {
+			dest = append(dest, &values[idx])
+		}
+		var count int64
+		if err := rows.Scan(append(dest, &count)...); err != nil {
+			return nil, fmt.Errorf("failed to read issue counts: %w", err)
+		}
+
+		stats.Total += count
+		if len(groups) == 0 {
+			continue
+		}
+		group := IssueStatsGroup{Key: make(map[string]*string, len(groups)), Count: count}
+		for idx, field := range options.GroupBy {
+			// Null values, like issues without a scope type, are kept as their own group
+			group.Key[field] = nil
+			if values[idx].Valid {
+				group.Key[field] = &values[idx].String
+			}
+		}
+		stats.Groups = append(stats.Groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read issue counts: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr string
+	}{
+		{value: "", want: nil},
+		{value: "severity", want: []string{"severity"}},
+		{value: "day, scope.resourceType", want: []string{"day", "scope.resourceType"}},
+		{value: "state,severity,state", want: []string{"state", "severity"}},
+		{value: "title", wantErr: `cannot group by "title"`},
+		{value: "severity,issues.title", wantErr: `cannot group by "issues.title"`},
+		{value: "detectedAt", wantErr: `cannot group by "detectedAt"`},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseGroupBy(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ParseGroupBy(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseGroupBy(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseGroupBy(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseDateField(t *testing.T) {
+	for _, value := range []string{"detectedAt", "resolvedAt"} {
+		if got, err := ParseDateField(value); err != nil || got != value {
+			t.Errorf("ParseDateField(%q) = %q, %v", value, got, err)
+		}
+	}
+	for _, value := range []string{"", "updatedAt", "resolved_at", "issues.detected_at"} {
+		if _, err := ParseDateField(value); err == nil || err.Error() != `cannot bucket by "`+value+`"` {
+			t.Errorf("ParseDateField(%q) error = %v, want it rejected", value, err)
+		}
+	}
+}
+
+func TestGetStatsDayBuckets(t *testing.T) {
+	tests := []struct {
+		dateField string
+		column    string
+	}{
+		{dateField: "", column: "issues.detected_at"},
+		{dateField: "detectedAt", column: "issues.detected_at"},
+		{dateField: "resolvedAt", column: "issues.resolved_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			db, statements := dryRunDB(t)
+			repo := &issueRepository{db: db, logger: quietLogger()}
+			severity := models.SeverityMajor
+			filters := IssueQueryFilters{Namespace: "team-a", Severity: &severity}
+
+			// The statement is built and recorded, dry run mode can't read the rows back
+			_, err := repo.GetStats(context.Background(), filters, IssueStatsOptions{
+				GroupBy:   []string{GroupByDay, "severity", "scope.resourceType"},
+				DateField: tt.dateField,
+			})
+			if err == nil {
+				t.Fatal("expected dry run to fail reading the counts")
+			}
+			if len(*statements) != 1 {
+				t.Fatalf("ran %d statements, want 1", len(*statements))
+			}
+
+			day := "to_char(" + tt.column + " AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+			rank := "CASE issues.severity WHEN 'info' THEN 0 WHEN 'minor' THEN 1 WHEN 'major' THEN 2 WHEN 'critical' THEN 3 END"
+			want := "SELECT " + day + " AS g0, issues.severity AS g1, issue_scopes.resource_type AS g2, COUNT(*) AS count " +
+				`FROM "issues" JOIN issue_scopes ON issues.scope_id = issue_scopes.id ` +
+				"WHERE issues.namespace = 'team-a' AND issues.severity = 'major' AND " + tt.column + " IS NOT NULL " +
+				"GROUP BY " + day + ", issues.severity, issue_scopes.resource_type " +
+				"ORDER BY " + day + ", " + rank + ", issue_scopes.resource_type"
+			if sql := (*statements)[0]; sql != want {
+				t.Errorf("SQL = %s\nwant  %s", sql, want)
+			}
+		})
+	}
+}
+
+func TestGetStatsWithoutGroups(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := &issueRepository{db: db, logger: quietLogger()}
+
+	repo.GetStats(context.Background(), IssueQueryFilters{Namespace: "team-a"}, IssueStatsOptions{})
+	if len(*statements) != 1 {
+		t.Fatalf("ran %d statements, want 1", len(*statements))
+	}
+	sql := (*statements)[0]
+	if want := `SELECT COUNT(*) AS count FROM "issues" WHERE issues.namespace = 'team-a'`; sql != want {
+		t.Errorf("SQL = %s, want %s", sql, want)
+	}
+}
+
+func TestGetStatsGroups(t *testing.T) {
+	db := digestDB(t)
+	for _, stmt := range []string{
+		`INSERT INTO issue_scopes VALUES ('s1', 'component', 'api', 'team-a'), ('s2', 'pipelinerun', 'api-push', 'team-a'), ('s3', NULL, 'other', 'team-a')`,
+		`INSERT INTO issues (id, title, severity, state, namespace, scope_id) VALUES
+			('1', 'a', 'critical', 'ACTIVE', 'team-a', 's1'),
+			('2', 'b', 'minor', 'ACTIVE', 'team-a', 's1'),
+			('3', 'c', 'critical', 'ACTIVE', 'team-a', 's1'),
+			('4', 'd', 'critical', 'RESOLVED', 'team-a', 's2'),
+			('5', 'e', 'info', 'ACTIVE', 'team-a', 's3'),
+			('6', 'f', 'critical', 'ACTIVE', 'team-b', 's1')`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := NewIssueRepository(db, quietLogger())
+
+	stats, err := repo.GetStats(context.Background(), IssueQueryFilters{Namespace: "team-a"}, IssueStatsOptions{
+		GroupBy: []string{"severity", "scope.resourceType"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Total != 5 {
+		t.Errorf("total = %d, want 5", stats.Total)
+	}
+	// Groups come in severity rank order, with null values kept as their own group
+	want := []string{"info/<nil>=1", "minor/component=1", "critical/component=2", "critical/pipelinerun=1"}
+	var got []string
+	for _, group := range stats.Groups {
+		resourceType := "<nil>"
+		if value := group.Key["scope.resourceType"]; value != nil {
+			resourceType = *value
+		}
+		got = append(got, fmt.Sprintf("%s/%s=%d", *group.Key["severity"], resourceType, group.Count))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("groups = %v, want %v", got, want)
+	}
+
+	// Without groups only the total is counted
+	stats, err = repo.GetStats(context.Background(), IssueQueryFilters{Namespace: "team-b"}, IssueStatsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Total != 1 || len(stats.Groups) != 0 {
+		t.Errorf("stats = %+v, want a total of 1 and no groups", stats)
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type linkRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+// NewLinkRepository creates a new Link repository
+func NewLinkRepository(db *gorm.DB, logger *logrus.Logger) LinkRepository {
+	return &linkRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateBatch adds links to an issue
+func (l *linkRepository) CreateBatch(ctx context.Context, issueID string, links []models.Link) error {
+	if len(links) == 0 {
+		return nil
+	}
+	for idx := range links {
+		links[idx].IssueID = issueID
+	}
+
+	err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Issue").Create(&links).Error; err != nil {
+			return err
+		}
+		return bumpVersions(tx, issueID)
+	})
+	if err != nil {
+		l.logger.WithError(err).WithField("issue_id", issueID).Error("Failed to create links")
+		return fmt.Errorf("failed to create links: %w", err)
+	}
+	return nil
+}
+
+// DeleteByIssueID removes all links of an issue
+func (l *linkRepository) DeleteByIssueID(ctx context.Context, issueID string) error {
+	err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("issue_id = ?", issueID).Delete(&models.Link{}).Error; err != nil {
+			return err
+		}
+		return bumpVersions(tx, issueID)
+	})
+	if err != nil {
+		l.logger.WithError(err).WithField("issue_id", issueID).Error("Failed to delete links")
+		return fmt.Errorf("failed to delete links: %w", err)
+	}
+	return nil
+}
+
+// FindByIssueID returns all links of an issue
+func (l *linkRepository) FindByIssueID(ctx context.Context, issueID string) ([]models.Link, error) {
+	var links []models.Link
+	if err := l.db.WithContext(ctx).Where("issue_id = ?", issueID).Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to find links: %w", err)
+	}
+	return links, nil
+}
+
+// FindByURL returns the link with the URL passed along with its issue
+func (l *linkRepository) FindByURL(ctx context.Context, url string) (*models.Link, error) {
+	var link models.Link
+	err := l.db.WithContext(ctx).Preload("Issue").Where("url = ?", url).First(&link).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find link: %w", err)
+	}
+	return &link, nil
+}
+
+// FindByURLPrefix returns all links whose URL starts with the prefix passed, along with their issues
+func (l *linkRepository) FindByURLPrefix(ctx context.Context, prefix string) ([]models.Link, error) {
+	var links []models.Link
+	if err := l.db.WithContext(ctx).
+		Preload("Issue").
+		Where("url LIKE ?", escapeLike(prefix)+"%").
+		Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to find links: %w", err)
+	}
+	return links, nil
+}
+
+// Helper function to escape the wildcard characters of a LIKE pattern
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
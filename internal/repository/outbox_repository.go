@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EventNotifyChannel is the Postgres channel notified with the ID of every new outbox event
+const EventNotifyChannel = "kite_issue_events"
+
+// ErrEventNotFound is returned when looking up an event that isn't in the outbox
+var ErrEventNotFound = apperrors.New(apperrors.ErrNotFound, "event not found")
+
+type outboxRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+// NewOutboxRepository creates a new outbox event repository
+func NewOutboxRepository(db *gorm.DB, logger *logrus.Logger) OutboxRepository {
+	return &outboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// DispatchPending passes up to limit undispatched events, oldest first, to the dispatch function.
+//
+// The events are locked while being dispatched so multiple replicas never send the same event.
+// Dispatching stops at the first failure to keep events in order, the failed event is retried on the next call.
+// Once an event has failed maxAttempts times it's parked instead, and dispatching carries on with the events after it.
+// The number of events dispatched or parked is returned, so it's less than limit once the outbox is drained
+// or dispatching stopped at a failure.
+func (o *outboxRepository) DispatchPending(ctx context.Context, limit, maxAttempts int, dispatch func(event models.OutboxEvent) error) (int, error) {
+	handled := 0
+
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var events []models.OutboxEvent
+		if err := tx.
+			Where("dispatched_at IS NULL AND parked_at IS NULL").
+			Order("id ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&events).Error; err != nil {
+			return fmt.Errorf("failed to find pending events: %w", err)
+		}
+
+		for _, event := range events {
+			if dispatchErr := dispatch(event); dispatchErr != nil {
+				updates := map[string]any{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": dispatchErr.Error(),
+				}
+				parked := event.Attempts+1 >= maxAttempts
+				if parked {
+					updates["parked_at"] = time.Now()
+				}
+				if err := tx.Model(&event).Updates(updates).Error; err != nil {
+					return fmt.Errorf("failed to record dispatch failure: %w", err)
+				}
+
+				logger := o.logger.WithError(dispatchErr).WithField("event_id", event.ID)
+				if !parked {
+					logger.Warn("Failed to dispatch event")
+					return nil
+				}
+				logger.WithField("attempts", event.Attempts+1).Error("Parked event that failed too many times")
+				handled++
+				continue
+			}
+
+			if err := tx.Model(&event).Updates(map[string]any{
+				"attempts":      gorm.Expr("attempts + 1"),
+				"dispatched_at": time.Now(),
+			}).Error; err != nil {
+				return fmt.Errorf("failed to mark event dispatched: %w", err)
+			}
+			handled++
+		}
+		return nil
+	})
+
+	if err != nil {
+		o.logger.WithError(err).Error("Failed to dispatch outbox events")
+		return 0, err
+	}
+	return handled, nil
+}
+
+// DeleteOlderThan removes events created before the time passed.
+// Events still to be dispatched are only removed when includePending is true, parked events always are.
+func (o *outboxRepository) DeleteOlderThan(ctx context.Context, before time.Time, includePending bool) (int64, error) {
+	query := o.db.WithContext(ctx).Where("created_at < ?", before)
+	if !includePending {
+		query = query.Where("dispatched_at IS NOT NULL OR parked_at IS NOT NULL")
+	}
+
+	result := query.Delete(&models.OutboxEvent{})
+	if result.Error != nil {
+		o.logger.WithError(result.Error).Error("Failed to delete old outbox events")
+		return 0, fmt.Errorf("failed to delete old events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// EventPosition is the place of an event in the order events are read in.
+//
+// Event IDs are taken when events are inserted, so transactions committing out of order would make events
+// appear behind ones already read if they were read by ID. Events are read by the transaction that recorded
+// them instead, and only once every transaction before it has finished, so nothing can appear behind them.
+type EventPosition struct {
+	TxID int64
+	ID   int64
+}
+
+// PositionOf returns the position of an event
+func PositionOf(event models.OutboxEvent) EventPosition {
+	return EventPosition{TxID: event.TxID, ID: event.ID}
+}
+
+// After returns whether the position comes after the one passed
+func (p EventPosition) After(other EventPosition) bool {
+	return p.TxID > other.TxID || p.TxID == other.TxID && p.ID > other.ID
+}
+
+// Transactions before this one have all finished, those after it may still record events
+const finishedTransactions = "pg_snapshot_xmin(pg_current_snapshot())::text::bigint"
+
+// FindAfter returns up to limit events after the position passed, in the order they're read in.
+// Events of transactions that haven't finished yet, or that wait on ones that haven't, are left for later.
+func (o *outboxRepository) FindAfter(ctx context.Context, after EventPosition, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	if err := o.db.WithContext(ctx).
+		Where("(tx_id, id) > (?, ?)", after.TxID, after.ID).
+		Where("tx_id < " + finishedTransactions).
+		Order("tx_id ASC, id ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to find events: %w", err)
+	}
+	return events, nil
+}
+
+// LatestPosition returns the position of the last event that can be read, or the zero position if there are none
+func (o *outboxRepository) LatestPosition(ctx context.Context) (EventPosition, error) {
+	var event models.OutboxEvent
+	err := o.db.WithContext(ctx).
+		Select("tx_id", "id").
+		Where("tx_id < " + finishedTransactions).
+		Order("tx_id DESC, id DESC").
+		Take(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return EventPosition{}, nil
+	}
+	if err != nil {
+		return EventPosition{}, fmt.Errorf("failed to find latest event: %w", err)
+	}
+	return PositionOf(event), nil
+}
+
+// HeldBack returns whether events after the position passed have been committed, but can't be read yet
+// because transactions started before theirs are still running
+func (o *outboxRepository) HeldBack(ctx context.Context, after EventPosition) (bool, error) {
+	var heldBack bool
+	if err := o.db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM outbox_events WHERE (tx_id, id) > (?, ?) AND tx_id >= "+finishedTransactions+")", after.TxID, after.ID).
+		Scan(&heldBack).Error; err != nil {
+		return false, fmt.Errorf("failed to check for held back events: %w", err)
+	}
+	return heldBack, nil
+}
+
+// FindPosition returns the position of an event, or ErrEventNotFound if it's not in the outbox, such as once it's pruned
+func (o *outboxRepository) FindPosition(ctx context.Context, id int64) (EventPosition, error) {
+	var event models.OutboxEvent
+	err := o.db.WithContext(ctx).Select("tx_id", "id").Take(&event, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return EventPosition{}, ErrEventNotFound
+	}
+	if err != nil {
+		return EventPosition{}, fmt.Errorf("failed to find event: %w", err)
+	}
+	return PositionOf(event), nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/konflux-ci/kite/pkg/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Helper function to open an in-memory database holding an outbox with the attempts made so far on each event
+func outboxDB(t *testing.T, attempts ...int) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(`CREATE TABLE outbox_events (id INTEGER PRIMARY KEY, tx_id INTEGER, type TEXT, issue_id TEXT, namespace TEXT,
+		payload BLOB, attempts INTEGER NOT NULL DEFAULT 0, last_error TEXT, created_at DATETIME, dispatched_at DATETIME, parked_at DATETIME)`).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range attempts {
+		if err := db.Exec(`INSERT INTO outbox_events (type, issue_id, namespace, payload, attempts, created_at)
+			VALUES ('issue.created', 'issue-1', 'team-a', CAST('{}' AS BLOB), ?, CURRENT_TIMESTAMP)`, n).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// Helper function to get the IDs of the events in a state
+func outboxIDs(t *testing.T, db *gorm.DB, where string) []int64 {
+	var ids []int64
+	if err := db.Model(&models.OutboxEvent{}).Where(where).Order("id").Pluck("id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestDispatchPendingStopsAtFailure(t *testing.T) {
+	db := outboxDB(t, 0, 0, 0)
+	repo := NewOutboxRepository(db, quietLogger())
+
+	var sent []int64
+	count, err := repo.DispatchPending(context.Background(), 10, 3, func(event models.OutboxEvent) error {
+		sent = append(sent, event.ID)
+		if event.ID == 2 {
+			return errors.New("sink unavailable")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Events after the failure wait for it, to keep them in order
+	if !slices.Equal(sent, []int64{1, 2}) || count != 1 {
+		t.Errorf("sent %v and returned %d, want [1 2] and 1", sent, count)
+	}
+	if got := outboxIDs(t, db, "dispatched_at IS NOT NULL"); !slices.Equal(got, []int64{1}) {
+		t.Errorf("dispatched %v, want [1]", got)
+	}
+	var failed models.OutboxEvent
+	db.First(&failed, 2)
+	if failed.Attempts != 1 || failed.LastError != "sink unavailable" || failed.ParkedAt != nil || failed.DispatchedAt != nil {
+		t.Errorf("failed event = %+v, want one attempt recorded and still pending", failed)
+	}
+}
+
+func TestDispatchPendingParksAndCountsFailedEvents(t *testing.T) {
+	// The second event has one attempt left
+	db := outboxDB(t, 0, 2, 0)
+	repo := NewOutboxRepository(db, quietLogger())
+
+	var sent []int64
+	count, err := repo.DispatchPending(context.Background(), 3, 3, func(event models.OutboxEvent) error {
+		sent = append(sent, event.ID)
+		if event.ID == 2 {
+			return errors.New("sink rejected the event")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Parking doesn't hold back the events after it, and counts towards a full batch
+	if !slices.Equal(sent, []int64{1, 2, 3}) || count != 3 {
+		t.Errorf("sent %v and returned %d, want [1 2 3] and 3", sent, count)
+	}
+	if got := outboxIDs(t, db, "parked_at IS NOT NULL"); !slices.Equal(got, []int64{2}) {
+		t.Errorf("parked %v, want [2]", got)
+	}
+	if got := outboxIDs(t, db, "dispatched_at IS NOT NULL"); !slices.Equal(got, []int64{1, 3}) {
+		t.Errorf("dispatched %v, want [1 3]", got)
+	}
+
+	// Parked events aren't retried
+	sent = nil
+	count, err = repo.DispatchPending(context.Background(), 3, 3, func(event models.OutboxEvent) error {
+		sent = append(sent, event.ID)
+		return nil
+	})
+	if err != nil || count != 0 || len(sent) != 0 {
+		t.Errorf("sent %v and returned %d, %v once drained", sent, count, err)
+	}
+}
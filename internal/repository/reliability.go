@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/query"
+)
+
+// ReliabilityQuery selects the scopes and time window reliability metrics cover
+type ReliabilityQuery struct {
+	Namespace string
+	// Optional, limits the metrics to scopes of this type or name
+	ResourceType string
+	ResourceName string
+	Since        time.Time
+	Until        time.Time
+}
+
+// ReliabilityMetrics describes how often a scope failed during a window and how quickly it recovered.
+// Durations are in seconds.
+type ReliabilityMetrics struct {
+	// Empty for the namespace total
+	ResourceType string
+	ResourceName string
+	// Issues detected during the window
+	FailureCount int64
+	// Issues resolved during the window, the resolve times cover these
+	ResolvedCount     int64
+	MeanResolveTime   *float64
+	MedianResolveTime *float64
+	P90ResolveTime    *float64
+	// Time during the window with at least one active issue
+	FailingSeconds float64
+	// Whether the scope was failing at the end of the window, and since when it's been failing or healthy
+	FailingAtEnd bool
+	StreakSince  *time.Time
+}
+
+// Row scanned from the reliability query
+type reliabilityRow struct {
+	ResourceType      *string
+	ResourceName      *string
+	IsTotal           bool
+	FailureCount      int64
+	ResolvedCount     int64
+	MeanResolveTime   *float64
+	MedianResolveTime *float64
+	P90ResolveTime    *float64
+	FailingSeconds    float64
+	FailingAtEnd      *bool
+	StreakSince       *time.Time
+}
+
+// Each issue is failing from its detection until it's resolved, overlapping issues on the same scope are merged
+// with range_agg so time isn't counted twice. The empty grouping set adds a total for the whole namespace.
+const reliabilitySQL = `
+WITH scoped AS (
+ SELECT issue_scopes.resource_type, issue_scopes.resource_name, issues.detected_at, issues.resolved_at,
+  EXTRACT(EPOCH FROM issues.resolved_at - issues.detected_at)::float8 AS resolve_seconds,
+  tstzrange(issues.detected_at, LEAST(GREATEST(COALESCE(issues.resolved_at, 'infinity'), issues.detected_at), @until), '[)') AS failing
+ FROM issues JOIN issue_scopes ON issues.scope_id = issue_scopes.id
+ WHERE issues.namespace = @namespace AND issues.detected_at < @until %s
+), grouped AS (
+ SELECT resource_type, resource_name, GROUPING(resource_type, resource_name) <> 0 AS is_total,
+  COUNT(*) FILTER (WHERE detected_at >= @since) AS failure_count,
+  COUNT(*) FILTER (WHERE resolved_at >= @since AND resolved_at < @until) AS resolved_count,
+  AVG(resolve_seconds) FILTER (WHERE resolved_at >= @since AND resolved_at < @until) AS mean_resolve_time,
+  percentile_cont(0.5) WITHIN GROUP (ORDER BY resolve_seconds) FILTER (WHERE resolved_at >= @since AND resolved_at < @until) AS median_resolve_time,
+  percentile_cont(0.9) WITHIN GROUP (ORDER BY resolve_seconds) FILTER (WHERE resolved_at >= @since AND resolved_at < @until) AS p90_resolve_time,
+  range_agg(failing) AS failing
+ FROM scoped
+ GROUP BY GROUPING SETS ((resource_type, resource_name), ())
+)
+SELECT resource_type, resource_name, is_total, failure_count, resolved_count, mean_resolve_time, median_resolve_time, p90_resolve_time,
+ COALESCE((SELECT EXTRACT(EPOCH FROM SUM(upper(r) - lower(r)))::float8
+  FROM unnest(failing * tstzmultirange(tstzrange(@since, @until, '[)'))) AS r), 0) AS failing_seconds,
+ streak.failing_at_end, streak.streak_since
+FROM grouped
+LEFT JOIN LATERAL (
+ SELECT upper(r) >= @until AS failing_at_end, CASE WHEN upper(r) >= @until THEN lower(r) ELSE upper(r) END AS streak_since
+ FROM unnest(failing) AS r ORDER BY lower(r) DESC LIMIT 1
+) streak ON true
+ORDER BY is_total DESC, resource_type, resource_name`
+
+// GetReliability computes reliability metrics for each scope in a namespace, along with a total for the namespace.
+// The total always comes first.
+func (i *issueRepository) GetReliability(ctx context.Context, q ReliabilityQuery) ([]ReliabilityMetrics, error) {
+	args := map[string]any{"namespace": q.Namespace, "since": q.Since, "until": q.Until}
+
+	var scopeFilters []string
+	if q.ResourceType != "" {
+		scopeFilters = append(scopeFilters, "AND issue_scopes.resource_type = @resourceType")
+		args["resourceType"] = q.ResourceType
+	}
+	if q.ResourceName != "" {
+		scopeFilters = append(scopeFilters, "AND issue_scopes.resource_name = @resourceName")
+		args["resourceName"] = q.ResourceName
+	}
+
+	var rows []reliabilityRow
+	if err := i.db.WithContext(ctx).
+		Raw(fmt.Sprintf(reliabilitySQL, strings.Join(scopeFilters, " ")), args).
+		Scan(&rows).Error; err != nil {
+		i.logger.WithError(err).WithField("namespace", q.Namespace).Error("Failed to compute reliability metrics")
+		return nil, fmt.Errorf("failed to compute reliability metrics: %w", err)
+	}
+
+	metrics := make([]ReliabilityMetrics, 0, len(rows))
+	for _, row := range rows {
+		m := ReliabilityMetrics{
+			FailureCount:      row.FailureCount,
+			ResolvedCount:     row.ResolvedCount,
+			MeanResolveTime:   row.MeanResolveTime,
+			MedianResolveTime: row.MedianResolveTime,
+			P90ResolveTime:    row.P90ResolveTime,
+			FailingSeconds:    row.FailingSeconds,
+			FailingAtEnd:      row.FailingAtEnd != nil && *row.FailingAtEnd,
+			StreakSince:       row.StreakSince,
+		}
+		if !row.IsTotal {
+			m.ResourceType = derefString(row.ResourceType)
+			m.ResourceName = derefString(row.ResourceName)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+// ParseTime parses an absolute time (RFC 3339 or a date) or a time relative to now, e.g. now-7d
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	parsed, err := parseTimeValue(query.Value{Text: value})
+	if err != nil {
+		var queryErr *query.Error
+		if errors.As(err, &queryErr) {
+			return time.Time{}, errors.New(queryErr.Message)
+		}
+		return time.Time{}, err
+	}
+	return parsed.time(now), nil
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestGetReliabilityQuery(t *testing.T) {
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       ReliabilityQuery
+		wantFilters []string
+	}{
+		{name: "whole namespace", query: ReliabilityQuery{Namespace: "team-a", Since: since, Until: until}},
+		{
+			name:        "resource type",
+			query:       ReliabilityQuery{Namespace: "team-a", ResourceType: "component", Since: since, Until: until},
+			wantFilters: []string{"AND issue_scopes.resource_type = 'component'"},
+		},
+		{
+			name:  "resource type and name",
+			query: ReliabilityQuery{Namespace: "team-a", ResourceType: "component", ResourceName: "api's", Since: since, Until: until},
+			wantFilters: []string{
+				"AND issue_scopes.resource_type = 'component'",
+				"AND issue_scopes.resource_name = 'api''s'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, statements := dryRunDB(t)
+			repo := &issueRepository{db: db, logger: quietLogger()}
+
+			if _, err := repo.GetReliability(context.Background(), tt.query); !errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+				t.Fatalf("error = %v, want the query to be built without running", err)
+			}
+			if len(*statements) != 1 {
+				t.Fatalf("ran %d statements, want 1", len(*statements))
+			}
+			sql := (*statements)[0]
+
+			for _, want := range []string{
+				"WHERE issues.namespace = 'team-a' AND issues.detected_at < '2024-05-08 00:00:00'",
+				// Resolve times only cover issues resolved during the window
+				"AVG(resolve_seconds) FILTER (WHERE resolved_at >= '2024-05-01 00:00:00' AND resolved_at < '2024-05-08 00:00:00') AS mean_resolve_time",
+				"percentile_cont(0.5) WITHIN GROUP (ORDER BY resolve_seconds)",
+				"percentile_cont(0.9) WITHIN GROUP (ORDER BY resolve_seconds)",
+				"tstzrange('2024-05-01 00:00:00', '2024-05-08 00:00:00', '[)')",
+				"GROUP BY GROUPING SETS ((resource_type, resource_name), ())",
+				"ORDER BY is_total DESC",
+			} {
+				if !strings.Contains(sql, want) {
+					t.Errorf("SQL doesn't contain %s:\n%s", want, sql)
+				}
+			}
+
+			// Scope filters are only added when set
+			if got := strings.Count(sql, "AND issue_scopes."); got != len(tt.wantFilters) {
+				t.Errorf("SQL has %d scope filters, want %d:\n%s", got, len(tt.wantFilters), sql)
+			}
+			for _, want := range tt.wantFilters {
+				if !strings.Contains(sql, want) {
+					t.Errorf("SQL doesn't contain %s:\n%s", want, sql)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "now", want: now},
+		{value: "now-7d", want: now.AddDate(0, 0, -7)},
+		{value: "now-90m", want: now.Add(-90 * time.Minute)},
+		{value: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2024-05-01T08:30:00+02:00", want: time.Date(2024, 5, 1, 6, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseTime(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "yesterday", "now-7x", "2024-13-01"} {
+		if _, err := ParseTime(value, now); err == nil {
+			t.Errorf("ParseTime(%q) should fail", value)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ErrViewNameTaken is returned when the owner already has a view with the same name in the namespace
+var ErrViewNameTaken = apperrors.New(apperrors.ErrConflict, "a view with this name already exists")
+
+// Postgres error code for unique constraint violations
+const uniqueViolationCode = "23505"
+
+type savedViewRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+// NewSavedViewRepository creates a new saved view repository
+func NewSavedViewRepository(db *gorm.DB, logger *logrus.Logger) SavedViewRepository {
+	return &savedViewRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new view
+func (v *savedViewRepository) Create(ctx context.Context, view *models.SavedView) error {
+	if err := v.db.WithContext(ctx).Create(view).Error; err != nil {
+		if isUniqueViolation(err) {
+			return ErrViewNameTaken
+		}
+		v.logger.WithError(err).WithField("namespace", view.Namespace).Error("Failed to create view")
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns a view, or nil if it doesn't exist
+func (v *savedViewRepository) FindByID(ctx context.Context, id string) (*models.SavedView, error) {
+	var view models.SavedView
+	if err := v.db.WithContext(ctx).First(&view, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		v.logger.WithError(err).WithField("view_id", id).Error("Failed to find view")
+		return nil, fmt.Errorf("failed to find view: %w", err)
+	}
+	return &view, nil
+}
+
+// FindVisible returns the views in a namespace shared with everyone, along with the owner's private views
+func (v *savedViewRepository) FindVisible(ctx context.Context, namespace, owner string) ([]models.SavedView, error) {
+	var views []models.SavedView
+	if err := v.db.WithContext(ctx).
+		Where("namespace = ?", namespace).
+		Where("visibility = ? OR owner = ?", models.ViewVisibilityShared, owner).
+		Order("name ASC, id ASC").
+		Find(&views).Error; err != nil {
+		v.logger.WithError(err).WithField("namespace", namespace).Error("Failed to find views")
+		return nil, fmt.Errorf("failed to find views: %w", err)
+	}
+	return views, nil
+}
+
+// Update saves the name, visibility and filters of a view
+func (v *savedViewRepository) Update(ctx context.Context, view *models.SavedView) error {
+	if err := v.db.WithContext(ctx).Model(view).
+		Select("name", "visibility", "filters", "updated_at").
+		Updates(view).Error; err != nil {
+		if isUniqueViolation(err) {
+			return ErrViewNameTaken
+		}
+		v.logger.WithError(err).WithField("view_id", view.ID).Error("Failed to update view")
+		return fmt.Errorf("failed to update view: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a view
+func (v *savedViewRepository) Delete(ctx context.Context, id string) error {
+	if err := v.db.WithContext(ctx).Delete(&models.SavedView{}, "id = ?", id).Error; err != nil {
+		v.logger.WithError(err).WithField("view_id", id).Error("Failed to delete view")
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	return nil
+}
+
+// Helper function to check if an error is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
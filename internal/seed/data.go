@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/konflux-ci/kite/internal/models"
+	"github.com/konflux-ci/kite/pkg/models"
 	"gorm.io/gorm"
 )
 
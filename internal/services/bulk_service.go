@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/dto"
+)
+
+// Most issues a single bulk request can change, they're all changed in one transaction
+const MaxBulkIssues = 500
+
+// Outcomes of a bulk action for each issue
+const (
+	bulkMatched   = "matched"
+	bulkUpdated   = "updated"
+	bulkDeleted   = "deleted"
+	bulkUnchanged = "unchanged"
+	bulkNotFound  = "not_found"
+)
+
+var ErrBulkTooLarge = fmt.Errorf("bulk actions are limited to %d issues", MaxBulkIssues)
+
+type BulkRequest struct {
+	// IDs of the issues to change, Filters is used when empty
+	IDs     []string
+	Filters repository.IssueQueryFilters
+	Action  repository.BulkAction
+	// Only report which issues match, without changing them
+	Preview bool
+}
+
+// BulkUpdateIssues applies an action to many issues in one transaction, reporting the outcome for each.
+//
+// authorize is called once for each namespace the issues belong to. Issues in namespaces it denies,
+// with an error wrapping apperrors.ErrForbidden, are reported as not found so they can't be told apart
+// from issues that don't exist, and other errors fail the request. Previews report every issue that
+// would be changed, along with the total matched even when it's more than a single request can change.
+func (s *IssueService) BulkUpdateIssues(ctx context.Context, req BulkRequest, authorize func(namespace string) error) (*dto.BulkIssueResponse, error) {
+	if len(req.IDs) > MaxBulkIssues {
+		return nil, ErrBulkTooLarge
+	}
+
+	filters := req.Filters
+	if len(req.IDs) > 0 {
+		filters = repository.IssueQueryFilters{IDs: req.IDs}
+	}
+	filters.Limit = MaxBulkIssues
+	filters.Fields = []string{"namespace"}
+	filters.Include = &repository.IssueIncludes{}
+
+	page, err := s.repo.FindAll(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if page.Total > MaxBulkIssues && !req.Preview {
+		return nil, fmt.Errorf("%w, %d issues match", ErrBulkTooLarge, page.Total)
+	}
+
+	response := &dto.BulkIssueResponse{
+		Action:  string(req.Action.Type),
+		Preview: req.Preview,
+		Items:   []dto.BulkIssueResult{},
+	}
+
+	// Issues are reported in the order their IDs were given, or the default order when filtered
+	namespaces := make(map[string]string, len(page.Issues))
+	ids := req.IDs
+	if len(ids) == 0 {
+		ids = make([]string, len(page.Issues))
+		for idx, issue := range page.Issues {
+			ids[idx] = issue.ID
+		}
+	}
+	for _, issue := range page.Issues {
+		namespaces[issue.ID] = issue.Namespace
+	}
+
+	access := make(map[string]error)
+	var allowed []string
+	denied := 0
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		namespace, found := namespaces[id]
+		if found {
+			accessErr, checked := access[namespace]
+			if !checked {
+				accessErr = authorize(namespace)
+				if accessErr != nil && !errors.Is(accessErr, apperrors.ErrForbidden) {
+					return nil, accessErr
+				}
+				access[namespace] = accessErr
+			}
+			if accessErr != nil {
+				found = false
+				denied++
+			}
+		}
+
+		// Nothing about issues the caller can't access is reported, not even their namespace
+		if !found {
+			response.Items = append(response.Items, dto.BulkIssueResult{ID: id, Status: bulkNotFound})
+			continue
+		}
+		allowed = append(allowed, id)
+		response.Items = append(response.Items, dto.BulkIssueResult{ID: id, Namespace: namespace, Status: bulkMatched})
+	}
+
+	// Filtered issues past the limit aren't listed, they're counted when every issue listed could be accessed
+	response.Matched = int64(len(allowed))
+	if len(req.IDs) == 0 && denied == 0 {
+		response.Matched = page.Total
+	}
+
+	if req.Preview || len(allowed) == 0 {
+		return response, nil
+	}
+
+	changed, err := s.repo.BulkUpdate(ctx, allowed, req.Action)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range response.Items {
+		result := &response.Items[idx]
+		if result.Status != bulkMatched {
+			continue
+		}
+		applied, found := changed[result.ID]
+		switch {
+		case !found:
+			// Deleted since the issues were matched
+			result.Status = bulkNotFound
+		case !applied:
+			result.Status = bulkUnchanged
+		case req.Action.Type == repository.BulkActionDelete:
+			result.Status = bulkDeleted
+			response.Applied++
+		default:
+			result.Status = bulkUpdated
+			response.Applied++
+		}
+	}
+
+	s.logger.WithField("action", req.Action.Type).WithField("applied", response.Applied).Info("Applied bulk action to issues")
+	return response, nil
+}
+
+// ValidateBulkAction checks the action is known and has what it needs, returning a ValidationError
+func ValidateBulkAction(action repository.BulkAction) error {
+	switch action.Type {
+	case repository.BulkActionResolve, repository.BulkActionDelete, repository.BulkActionAssign:
+		return nil
+	case repository.BulkActionSeverity:
+		if action.Severity.Rank() < 0 {
+			return apperrors.InvalidField("severity", "invalid severity value")
+		}
+		return nil
+	case repository.BulkActionLabel:
+		if action.Label == "" {
+			return apperrors.InvalidField("label", "is required")
+		}
+		return nil
+	}
+	return apperrors.InvalidField("action", fmt.Sprintf("unknown action %q", action.Type))
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// bulkRepository serves the issues of a bulk action, the rest of the repository isn't used
+type bulkRepository struct {
+	repository.IssueRepository
+	issues  []models.Issue
+	total   int64
+	updated []string
+}
+
+func (r *bulkRepository) FindAll(_ context.Context, filters repository.IssueQueryFilters) (*repository.IssuePage, error) {
+	page := &repository.IssuePage{Total: r.total}
+	for _, issue := range r.issues {
+		if len(filters.IDs) == 0 || slices.Contains(filters.IDs, issue.ID) {
+			page.Issues = append(page.Issues, issue)
+		}
+	}
+	if page.Total == 0 {
+		page.Total = int64(len(page.Issues))
+	}
+	return page, nil
+}
+
+func (r *bulkRepository) BulkUpdate(_ context.Context, ids []string, _ repository.BulkAction) (map[string]bool, error) {
+	r.updated = ids
+	changed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		changed[id] = true
+	}
+	return changed, nil
+}
+
+func allowNamespace(allowed string) func(namespace string) error {
+	return func(namespace string) error {
+		if namespace != allowed {
+			return apperrors.New(apperrors.ErrForbidden, "access denied to this namespace")
+		}
+		return nil
+	}
+}
+
+func TestBulkUpdateIssuesHidesInaccessibleIssues(t *testing.T) {
+	repo := &bulkRepository{issues: []models.Issue{
+		{ID: "mine", Namespace: "team-a"},
+		{ID: "theirs", Namespace: "team-b"},
+	}}
+	service := NewIssueService(repo, logrus.New())
+
+	req := BulkRequest{
+		IDs:    []string{"mine", "theirs", "missing"},
+		Action: repository.BulkAction{Type: repository.BulkActionResolve},
+	}
+	result, err := service.BulkUpdateIssues(context.Background(), req, allowNamespace("team-a"))
+	if err != nil {
+		t.Fatalf("BulkUpdateIssues() error = %v", err)
+	}
+
+	if result.Matched != 1 || result.Applied != 1 {
+		t.Errorf("matched %d and applied %d, want 1 and 1", result.Matched, result.Applied)
+	}
+	if len(repo.updated) != 1 || repo.updated[0] != "mine" {
+		t.Errorf("updated %v, want only the accessible issue", repo.updated)
+	}
+	theirs, missing := result.Items[1], result.Items[2]
+	theirs.ID, missing.ID = "", ""
+	if theirs != missing {
+		t.Errorf("inaccessible issue reported as %+v, unlike a missing one: %+v", result.Items[1], result.Items[2])
+	}
+	if result.Items[0].Namespace != "team-a" || result.Items[0].Status != bulkUpdated {
+		t.Errorf("accessible issue reported as %+v", result.Items[0])
+	}
+}
+
+func TestBulkUpdateIssuesPreviewCountsFilteredTotal(t *testing.T) {
+	repo := &bulkRepository{issues: []models.Issue{{ID: "one", Namespace: "team-a"}}, total: 800}
+	service := NewIssueService(repo, logrus.New())
+
+	req := BulkRequest{
+		Filters: repository.IssueQueryFilters{Namespace: "team-a"},
+		Action:  repository.BulkAction{Type: repository.BulkActionResolve},
+		Preview: true,
+	}
+	result, err := service.BulkUpdateIssues(context.Background(), req, allowNamespace("team-a"))
+	if err != nil {
+		t.Fatalf("BulkUpdateIssues() error = %v", err)
+	}
+	if result.Matched != 800 {
+		t.Errorf("matched %d, want the total of 800", result.Matched)
+	}
+
+	// Issues of a namespace that's denied aren't counted
+	result, err = service.BulkUpdateIssues(context.Background(), req, allowNamespace("team-b"))
+	if err != nil {
+		t.Fatalf("BulkUpdateIssues() error = %v", err)
+	}
+	if result.Matched != 0 {
+		t.Errorf("matched %d inaccessible issues, want 0", result.Matched)
+	}
+}
+
+func TestBulkUpdateIssuesFailsWhenAccessCantBeChecked(t *testing.T) {
+	repo := &bulkRepository{issues: []models.Issue{{ID: "one", Namespace: "team-a"}}}
+	service := NewIssueService(repo, logrus.New())
+	reviewErr := errors.New("access review failed")
+
+	req := BulkRequest{IDs: []string{"one"}, Action: repository.BulkAction{Type: repository.BulkActionResolve}}
+	_, err := service.BulkUpdateIssues(context.Background(), req, func(string) error { return reviewErr })
+	if !errors.Is(err, reviewErr) {
+		t.Errorf("BulkUpdateIssues() error = %v, want %v", err, reviewErr)
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
+)
+
+// NewValidationError converts the errors of checking binding tags, from gin's validator, to an apperrors.ValidationError.
+// Other errors, such as a request body that isn't JSON, are returned as they are.
+func NewValidationError(err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	validationErr := &apperrors.ValidationError{Fields: make([]dto.FieldError, len(validationErrs))}
+	for i, fieldErr := range validationErrs {
+		validationErr.Fields[i] = dto.FieldError{Field: fieldPath(fieldErr), Message: fieldMessage(fieldErr)}
+	}
+	return validationErr
+}
+
+func init() {
+	// Name fields by their JSON keys, the way clients send them
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// Helper function to get the path of a field in the request body, without the name of the request type
+func fieldPath(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+// Helper function to describe why a field is invalid
+func fieldMessage(fieldErr validator.FieldError) string {
+	if fieldErr.Tag() == "required" {
+		return "is required"
+	}
+	return "failed the " + fieldErr.Tag() + " check"
+}
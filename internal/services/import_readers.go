@@ -0,0 +1,161 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// importRow is a row read from an import, Err is set when the row couldn't be decoded
+type importRow struct {
+	Request dto.ImportIssueRequest
+	Err     error
+}
+
+// readImportRows decodes every row of an import in the format given.
+// An error is only returned when the input as a whole can't be read, errors in single rows are kept with the row.
+func readImportRows(r io.Reader, format ImportFormat) ([]importRow, error) {
+	switch format {
+	case ImportFormatNDJSON:
+		return readNDJSONRows(r)
+	case ImportFormatCSV:
+		return readCSVRows(r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrMalformedImport, format)
+	}
+}
+
+// readNDJSONRows reads one issue per line, in the same shape as an exported or created issue. Blank lines are skipped.
+func readNDJSONRows(r io.Reader) ([]importRow, error) {
+	var rows []importRow
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedImport, err)
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if len(rows) == maxImportRows {
+				return nil, ErrImportTooLarge
+			}
+			var row importRow
+			if decodeErr := json.Unmarshal(line, &row.Request); decodeErr != nil {
+				row.Err = fmt.Errorf("invalid JSON: %w", decodeErr)
+			}
+			rows = append(rows, row)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+	}
+}
+
+// readCSVRows reads issues with the columns of a CSV export, matched by the header row.
+// Unknown columns are ignored so an export can be imported as it is.
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %w", ErrMalformedImport, err)
+	}
+	columns := make(map[string]int, len(header))
+	for idx, name := range header {
+		columns[strings.TrimSpace(name)] = idx
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedImport, err)
+		}
+		if len(rows) == maxImportRows {
+			return nil, ErrImportTooLarge
+		}
+
+		value := func(column string) string {
+			idx, ok := columns[column]
+			if !ok {
+				return ""
+			}
+			return unescapeCSVFormula(record[idx])
+		}
+		row := importRow{Request: dto.ImportIssueRequest{
+			CreateIssueRequest: dto.CreateIssueRequest{
+				Title:       value("title"),
+				Description: value("description"),
+				Severity:    models.Severity(value("severity")),
+				IssueType:   models.IssueType(value("issueType")),
+				State:       models.IssueState(value("state")),
+				Namespace:   value("namespace"),
+				Scope: dto.ScopeReqBody{
+					ResourceType:      value("scopeResourceType"),
+					ResourceName:      value("scopeResourceName"),
+					ResourceNamespace: value("scopeResourceNamespace"),
+				},
+			},
+		}}
+		row.Request.Links, row.Err = parseCSVLinks(value("linkTitles"), value("linkUrls"))
+		if row.Err == nil {
+			row.Request.DetectedAt, row.Err = parseCSVTime("detectedAt", value("detectedAt"))
+		}
+		if row.Err == nil {
+			row.Request.ResolvedAt, row.Err = parseCSVTime("resolvedAt", value("resolvedAt"))
+		}
+		rows = append(rows, row)
+	}
+}
+
+// unescapeCSVFormula removes the quote an export puts in front of cells that spreadsheets would treat as formulas
+func unescapeCSVFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// parseCSVLinks pairs up the newline separated link titles and URLs of a row
+func parseCSVLinks(titles, urls string) ([]dto.CreateLinkRequest, error) {
+	if titles == "" && urls == "" {
+		return nil, nil
+	}
+	titleList := strings.Split(titles, "\n")
+	urlList := strings.Split(urls, "\n")
+	if len(titleList) != len(urlList) {
+		return nil, fmt.Errorf("linkTitles has %d entries but linkUrls has %d", len(titleList), len(urlList))
+	}
+
+	links := make([]dto.CreateLinkRequest, len(titleList))
+	for idx := range titleList {
+		links[idx] = dto.CreateLinkRequest{Title: titleList[idx], URL: urlList[idx]}
+	}
+	return links, nil
+}
+
+func parseCSVTime(column, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: expected an RFC3339 timestamp", column)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+type ImportFormat string
+
+const (
+	ImportFormatNDJSON ImportFormat = "ndjson"
+	ImportFormatCSV    ImportFormat = "csv"
+)
+
+// Most rows accepted by a single import, they're all created in one transaction
+const maxImportRows = 10000
+
+// How far in the future imported timestamps can be, for clocks of the exporting system that are slightly ahead
+const importClockSkew = 5 * time.Minute
+
+// Row statuses in an import report
+const (
+	importRowValid     = "valid"
+	importRowInvalid   = "invalid"
+	importRowDuplicate = "duplicate"
+	importRowCreated   = "created"
+)
+
+var (
+	ErrMalformedImport = errors.New("malformed import")
+	ErrImportTooLarge  = fmt.Errorf("import has more than %d rows", maxImportRows)
+	ErrInvalidImport   = errors.New("import has invalid rows")
+)
+
+type ImportOptions struct {
+	Format ImportFormat
+	// Namespace rows are imported into, rows without one are put in it and rows for other namespaces are rejected.
+	// When empty every row must set its own namespace.
+	Namespace string
+	// Only validate and check for duplicates, without creating anything
+	DryRun bool
+}
+
+// ImportIssues creates issues from an NDJSON or CSV export, keeping their original detection and resolution times.
+//
+// Every row is validated like a request to create an issue and active issues duplicating an existing issue,
+// or an earlier row, are skipped. If any row is invalid nothing is imported and ErrInvalidImport is returned
+// along with the report describing which rows failed.
+func (s *IssueService) ImportIssues(ctx context.Context, r io.Reader, options ImportOptions) (*dto.ImportReport, error) {
+	rows, err := readImportRows(r, options.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.ImportReport{
+		DryRun: options.DryRun,
+		Total:  len(rows),
+		Rows:   make([]dto.ImportRowResult, len(rows)),
+	}
+	// Rows to create, by their index in the report
+	var toImport []dto.ImportIssueRequest
+	var importIndexes []int
+	// First row seen for each active scope, to find duplicates within the import
+	activeRows := make(map[string]int)
+
+	for idx := range rows {
+		result := &report.Rows[idx]
+		result.Row = idx + 1
+		req := &rows[idx].Request
+
+		err := rows[idx].Err
+		if err == nil {
+			err = validateImportRequest(req, options.Namespace)
+		}
+		if err != nil {
+			result.Status = importRowInvalid
+			result.Error = err.Error()
+			report.Invalid++
+			continue
+		}
+
+		if req.State == models.IssueStateActive {
+			key := strings.Join([]string{req.Namespace, string(req.IssueType), req.Scope.ResourceType, req.Scope.ResourceName}, "\x00")
+			if first, ok := activeRows[key]; ok {
+				result.Status = importRowDuplicate
+				result.Error = fmt.Sprintf("duplicate of row %d", first+1)
+				report.Duplicates++
+				continue
+			}
+			activeRows[key] = idx
+
+			duplicate, err := s.repo.CheckDuplicate(ctx, req.CreateIssueRequest)
+			if err != nil {
+				return nil, err
+			}
+			if duplicate.IsDuplicate && duplicate.ExistingIssue != nil {
+				result.Status = importRowDuplicate
+				result.ExistingIssueID = duplicate.ExistingIssue.ID
+				report.Duplicates++
+				continue
+			}
+		}
+
+		result.Status = importRowValid
+		report.Valid++
+		toImport = append(toImport, *req)
+		importIndexes = append(importIndexes, idx)
+	}
+
+	if report.Invalid > 0 && !options.DryRun {
+		return report, ErrInvalidImport
+	}
+	if options.DryRun || len(toImport) == 0 {
+		return report, nil
+	}
+
+	issues, err := s.repo.Import(ctx, toImport)
+	if err != nil {
+		return nil, err
+	}
+	for idx, issue := range issues {
+		result := &report.Rows[importIndexes[idx]]
+		result.Status = importRowCreated
+		result.IssueID = issue.ID
+	}
+	report.Created = len(issues)
+
+	s.logger.WithField("namespace", options.Namespace).WithField("created", report.Created).
+		WithField("duplicates", report.Duplicates).Info("Imported issues")
+	return report, nil
+}
+
+// validateImportRequest applies the rules for creating an issue to an imported one, along with its timestamps.
+// Defaults for the namespace and state are filled in on req.
+func validateImportRequest(req *dto.ImportIssueRequest, namespace string) error {
+	if req.Namespace == "" {
+		req.Namespace = namespace
+	}
+	if namespace != "" && req.Namespace != namespace {
+		return apperrors.InvalidField("namespace", fmt.Sprintf("%q does not match the namespace being imported into", req.Namespace))
+	}
+	if req.State == "" {
+		req.State = models.IssueStateActive
+		if req.ResolvedAt != nil {
+			req.State = models.IssueStateResolved
+		}
+	}
+
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return NewValidationError(err)
+	}
+	if err := ValidateCreateIssueRequest(req.CreateIssueRequest); err != nil {
+		return err
+	}
+
+	latest := time.Now().Add(importClockSkew)
+	switch {
+	case req.DetectedAt != nil && req.DetectedAt.After(latest):
+		return apperrors.InvalidField("detectedAt", "is in the future")
+	case req.ResolvedAt != nil && req.ResolvedAt.After(latest):
+		return apperrors.InvalidField("resolvedAt", "is in the future")
+	case req.State == models.IssueStateResolved && req.ResolvedAt == nil:
+		return apperrors.InvalidField("resolvedAt", "is required for resolved issues")
+	case req.State == models.IssueStateActive && req.ResolvedAt != nil:
+		return apperrors.InvalidField("resolvedAt", "is set on an active issue")
+	case req.ResolvedAt != nil && req.DetectedAt != nil && req.ResolvedAt.Before(*req.DetectedAt):
+		return apperrors.InvalidField("resolvedAt", "is before detectedAt")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+func importRequest(detectedAt, resolvedAt *time.Time) *dto.ImportIssueRequest {
+	return &dto.ImportIssueRequest{
+		CreateIssueRequest: dto.CreateIssueRequest{
+			Title:       "Build failed",
+			Description: "The build of the component failed",
+			Severity:    models.SeverityMajor,
+			IssueType:   models.IssueTypeBuild,
+			Namespace:   "team-a",
+			Scope:       dto.ScopeReqBody{ResourceType: "component", ResourceName: "frontend"},
+		},
+		DetectedAt: detectedAt,
+		ResolvedAt: resolvedAt,
+	}
+}
+
+func TestValidateImportRequestTimestamps(t *testing.T) {
+	past := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	skewed := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name       string
+		detectedAt *time.Time
+		resolvedAt *time.Time
+		wantField  string
+	}{
+		{name: "active issue in the past", detectedAt: &past},
+		{name: "resolved issue", detectedAt: &past, resolvedAt: &recent},
+		{name: "detected slightly ahead of our clock", detectedAt: &skewed},
+		{name: "detected in the future", detectedAt: &future, wantField: "detectedAt"},
+		{name: "resolved in the future", detectedAt: &past, resolvedAt: &future, wantField: "resolvedAt"},
+		{name: "resolved before detected", detectedAt: &recent, resolvedAt: &past, wantField: "resolvedAt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImportRequest(importRequest(tt.detectedAt, tt.resolvedAt), "team-a")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *apperrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a validation error for %s, got %v", tt.wantField, err)
+			}
+			if got := validationErr.Fields[0].Field; got != tt.wantField {
+				t.Errorf("invalid field = %s, want %s", got, tt.wantField)
+			}
+		})
+	}
+}
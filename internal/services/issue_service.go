@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"time"
 
-	"github.com/konflux-ci/kite/internal/handlers/dto"
-	"github.com/konflux-ci/kite/internal/models"
+	"github.com/konflux-ci/kite/internal/apperrors"
 	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
 	"github.com/sirupsen/logrus"
 )
 
@@ -49,16 +51,18 @@ func (s *IssueService) CheckForDuplicateIssue(ctx context.Context, req dto.Creat
 
 // FindIssues retrieves issues with optional filters
 func (s *IssueService) FindIssues(ctx context.Context, filters repository.IssueQueryFilters) (*dto.IssueResponse, error) {
-	issues, total, err := s.repo.FindAll(ctx, filters)
+	page, err := s.repo.FindAll(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
 
 	return &dto.IssueResponse{
-		Data:   issues,
-		Total:  total,
-		Limit:  filters.Limit,
-		Offset: filters.Offset,
+		Data:       page.Issues,
+		Total:      page.Total,
+		Limit:      filters.Limit,
+		Offset:     filters.Offset,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
 	}, nil
 }
 
@@ -71,6 +75,33 @@ func (s *IssueService) FindIssueByID(ctx context.Context, id string) (*models.Is
 	return issue, nil
 }
 
+// FindScopes retrieves the scopes with the IDs passed
+func (s *IssueService) FindScopes(ctx context.Context, ids []string) ([]models.IssueScope, error) {
+	scopes, err := s.repo.FindScopes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
+
+// FindLinks retrieves the links of the issues passed
+func (s *IssueService) FindLinks(ctx context.Context, issueIDs []string) ([]models.Link, error) {
+	links, err := s.repo.FindLinks(ctx, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// FindRelations retrieves the relationships of the issues passed, in either direction
+func (s *IssueService) FindRelations(ctx context.Context, issueIDs []string) ([]models.RelatedIssue, error) {
+	relations, err := s.repo.FindRelations(ctx, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	return relations, nil
+}
+
 // CreateIssue creates a new issue
 func (s *IssueService) CreateIssue(ctx context.Context, req dto.CreateIssueRequest) (*models.Issue, error) {
 	issue, err := s.repo.Create(ctx, req)
@@ -80,26 +111,29 @@ func (s *IssueService) CreateIssue(ctx context.Context, req dto.CreateIssueReque
 	return issue, nil
 }
 
-// UpdateIssue updates and existing issue
-func (s *IssueService) UpdateIssue(ctx context.Context, id string, req dto.UpdateIssueRequest) (*models.Issue, error) {
-	issue, err := s.repo.Update(ctx, id, req)
+// UpdateIssue updates and existing issue, if version isn't 0 only when the issue is still at that version
+func (s *IssueService) UpdateIssue(ctx context.Context, id string, req dto.UpdateIssueRequest, version int64) (*models.Issue, error) {
+	issue, err := s.repo.Update(ctx, id, req, version)
 	if err != nil {
 		return nil, err
 	}
 	return issue, nil
 }
 
-// DeleteIssue deletes an issue and related entities
-func (s *IssueService) DeleteIssue(ctx context.Context, id string) error {
-	err := s.repo.Delete(ctx, id)
+// DeleteIssue deletes an issue and related entities, if version isn't 0 only when the issue is still at that version
+func (s *IssueService) DeleteIssue(ctx context.Context, id string, version int64) error {
+	err := s.repo.Delete(ctx, id, version)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// AddRelatedIsue creates a relationship between two issues
+// AddRelatedIsue creates a relationship between two issues, which can't be the same issue
 func (s *IssueService) AddRelatedIssue(ctx context.Context, sourceID, targetID string) error {
+	if sourceID == targetID {
+		return apperrors.InvalidField("relatedId", "an issue can't be related to itself")
+	}
 	if err := s.repo.AddRelatedIssue(ctx, sourceID, targetID); err != nil {
 		return err
 	}
@@ -122,3 +156,38 @@ func (s *IssueService) ResolveIssuesByScope(ctx context.Context, resourceType, r
 	}
 	return count, nil
 }
+
+// GetDigestSummary aggregates issue activity in a namespace for a digest report
+func (s *IssueService) GetDigestSummary(ctx context.Context, namespace string, since time.Time, limit int) (*repository.DigestSummary, error) {
+	summary, err := s.repo.GetDigestSummary(ctx, namespace, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// GetIssueStats counts issues matching the filters, grouped by the fields requested
+func (s *IssueService) GetIssueStats(ctx context.Context, filters repository.IssueQueryFilters, options repository.IssueStatsOptions) (*dto.IssueStatsResponse, error) {
+	stats, err := s.repo.GetStats(ctx, filters, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.IssueStatsResponse{
+		GroupBy: options.GroupBy,
+		Total:   stats.Total,
+		Groups:  make([]dto.IssueStatsGroup, len(stats.Groups)),
+	}
+	if response.GroupBy == nil {
+		response.GroupBy = []string{}
+	}
+	for idx, group := range stats.Groups {
+		response.Groups[idx] = dto.IssueStatsGroup{Key: group.Key, Count: group.Count}
+	}
+	return response, nil
+}
+
+// ExportIssues calls fn with every issue matching the filters, streaming them from the database
+func (s *IssueService) ExportIssues(ctx context.Context, filters repository.IssueQueryFilters, fn func(issue *models.Issue) error) error {
+	return s.repo.StreamAll(ctx, filters, fn)
+}
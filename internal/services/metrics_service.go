@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/cache"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/sirupsen/logrus"
+)
+
+// Windows are aligned to this so repeated requests with relative times share cached results
+const metricsWindowPrecision = time.Minute
+
+type MetricsService struct {
+	repo   repository.IssueRepository
+	cache  *cache.TTL[repository.ReliabilityQuery, *dto.ReliabilityResponse]
+	logger *logrus.Logger
+}
+
+// NewMetricsService returns a new metrics service, results are cached for cacheTTL
+func NewMetricsService(repo repository.IssueRepository, cacheTTL time.Duration, cacheSize int, logger *logrus.Logger) *MetricsService {
+	return &MetricsService{
+		repo:   repo,
+		cache:  cache.NewTTL[repository.ReliabilityQuery, *dto.ReliabilityResponse](cacheTTL, cacheSize),
+		logger: logger,
+	}
+}
+
+// GetReliability returns reliability metrics for the namespace and each of its scopes over a window
+func (s *MetricsService) GetReliability(ctx context.Context, q repository.ReliabilityQuery) (*dto.ReliabilityResponse, error) {
+	q.Since = q.Since.UTC().Truncate(metricsWindowPrecision)
+	q.Until = q.Until.UTC().Truncate(metricsWindowPrecision)
+
+	if cached, ok := s.cache.Get(q); ok {
+		return cached, nil
+	}
+
+	metrics, err := s.repo.GetReliability(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ReliabilityResponse{
+		Namespace:   q.Namespace,
+		Since:       q.Since,
+		Until:       q.Until,
+		GeneratedAt: time.Now().UTC(),
+		Scopes:      make([]dto.ScopeReliability, 0, len(metrics)),
+	}
+	for idx, m := range metrics {
+		converted := toReliabilityMetrics(m, q)
+		// The namespace total always comes first
+		if idx == 0 {
+			response.Summary = converted
+			continue
+		}
+		response.Scopes = append(response.Scopes, dto.ScopeReliability{
+			ResourceType:       m.ResourceType,
+			ResourceName:       m.ResourceName,
+			ReliabilityMetrics: converted,
+		})
+	}
+
+	s.cache.Set(q, response)
+	return response, nil
+}
+
+// Helper function to convert repository metrics to their response format
+func toReliabilityMetrics(m repository.ReliabilityMetrics, q repository.ReliabilityQuery) dto.ReliabilityMetrics {
+	converted := dto.ReliabilityMetrics{
+		FailureCount:  m.FailureCount,
+		ResolvedCount: m.ResolvedCount,
+		MTTR: dto.MTTR{
+			Mean:   m.MeanResolveTime,
+			Median: m.MedianResolveTime,
+			P90:    m.P90ResolveTime,
+		},
+		Streak: dto.Streak{State: "healthy", Since: m.StreakSince},
+	}
+
+	if window := q.Until.Sub(q.Since).Seconds(); window > 0 {
+		converted.FailingPercent = m.FailingSeconds / window * 100
+	}
+	if m.FailingAtEnd {
+		converted.Streak.State = "failing"
+	}
+	if m.StreakSince != nil {
+		duration := q.Until.Sub(*m.StreakSince).Seconds()
+		converted.Streak.DurationSeconds = &duration
+	}
+	return converted
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// reliabilityRepository returns the same metrics for every query, counting the queries it gets
+type reliabilityRepository struct {
+	repository.IssueRepository
+	metrics []repository.ReliabilityMetrics
+	queries []repository.ReliabilityQuery
+}
+
+func (r *reliabilityRepository) GetReliability(_ context.Context, q repository.ReliabilityQuery) ([]repository.ReliabilityMetrics, error) {
+	r.queries = append(r.queries, q)
+	return r.metrics, nil
+}
+
+func seconds(value float64) *float64 {
+	return &value
+}
+
+func TestGetReliabilityConvertsMetrics(t *testing.T) {
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(10 * time.Hour)
+	failingSince := until.Add(-90 * time.Minute)
+	healthySince := until.Add(-4 * time.Hour)
+
+	repo := &reliabilityRepository{metrics: []repository.ReliabilityMetrics{
+		{
+			FailureCount: 4, ResolvedCount: 3,
+			MeanResolveTime: seconds(600), MedianResolveTime: seconds(300), P90ResolveTime: seconds(1500),
+			FailingSeconds: 9000, FailingAtEnd: true, StreakSince: &failingSince,
+		},
+		{ResourceType: "component", ResourceName: "api", FailureCount: 1, ResolvedCount: 1, MeanResolveTime: seconds(600), FailingSeconds: 3600, StreakSince: &healthySince},
+		{ResourceType: "component", ResourceName: "web"},
+	}}
+	service := NewMetricsService(repo, time.Minute, 10, logrus.New())
+
+	// Times are aligned to the minute
+	response, err := service.GetReliability(context.Background(), repository.ReliabilityQuery{
+		Namespace: "team-a", Since: since.Add(42 * time.Second), Until: until.Add(59 * time.Second),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := repo.queries[0]; !q.Since.Equal(since) || !q.Until.Equal(until) {
+		t.Errorf("queried %s to %s, want %s to %s", q.Since, q.Until, since, until)
+	}
+
+	// The first row is the namespace total
+	summary := response.Summary
+	if summary.FailureCount != 4 || summary.ResolvedCount != 3 {
+		t.Errorf("summary counts = %d failures, %d resolved", summary.FailureCount, summary.ResolvedCount)
+	}
+	if *summary.MTTR.Mean != 600 || *summary.MTTR.Median != 300 || *summary.MTTR.P90 != 1500 {
+		t.Errorf("summary MTTR = %v, %v, %v", *summary.MTTR.Mean, *summary.MTTR.Median, *summary.MTTR.P90)
+	}
+	if summary.FailingPercent != 25 {
+		t.Errorf("summary failing = %v%%, want 25%%", summary.FailingPercent)
+	}
+	if summary.Streak.State != "failing" || *summary.Streak.DurationSeconds != 5400 {
+		t.Errorf("summary streak = %s for %v seconds, want failing for 5400", summary.Streak.State, *summary.Streak.DurationSeconds)
+	}
+
+	if len(response.Scopes) != 2 {
+		t.Fatalf("got %d scopes, want 2", len(response.Scopes))
+	}
+	api := response.Scopes[0]
+	if api.ResourceName != "api" || api.FailingPercent != 10 || api.Streak.State != "healthy" || *api.Streak.DurationSeconds != 14400 {
+		t.Errorf("api scope = %+v", api)
+	}
+	// Nothing resolved and never failing, there are no resolve times or streak to report
+	web := response.Scopes[1]
+	if web.MTTR.Mean != nil || web.MTTR.Median != nil || web.MTTR.P90 != nil {
+		t.Errorf("web MTTR = %+v, want null values", web.MTTR)
+	}
+	if web.Streak.State != "healthy" || web.Streak.Since != nil || web.Streak.DurationSeconds != nil {
+		t.Errorf("web streak = %+v, want healthy without a start", web.Streak)
+	}
+}
+
+func TestGetReliabilityCachesWindows(t *testing.T) {
+	repo := &reliabilityRepository{metrics: []repository.ReliabilityMetrics{{FailureCount: 1}}}
+	service := NewMetricsService(repo, time.Minute, 10, logrus.New())
+	until := time.Now().Truncate(time.Minute)
+	q := repository.ReliabilityQuery{Namespace: "team-a", Since: until.Add(-time.Hour), Until: until}
+
+	first, err := service.GetReliability(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Relative times resolved a few seconds apart share the window
+	q.Until = q.Until.Add(30 * time.Second)
+	second, err := service.GetReliability(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.queries) != 1 || first != second {
+		t.Errorf("queried the repository %d times, want the cached result reused", len(repo.queries))
+	}
+
+	// Other namespaces and windows aren't served from the cache
+	q.Namespace = "team-b"
+	service.GetReliability(context.Background(), q)
+	q.Namespace, q.Since = "team-a", q.Since.Add(-time.Hour)
+	service.GetReliability(context.Background(), q)
+	if len(repo.queries) != 3 {
+		t.Errorf("queried the repository %d times, want 3", len(repo.queries))
+	}
+}
+
+func TestGetReliabilityCacheExpires(t *testing.T) {
+	repo := &reliabilityRepository{metrics: []repository.ReliabilityMetrics{{FailureCount: 1}}}
+	service := NewMetricsService(repo, 20*time.Millisecond, 10, logrus.New())
+	until := time.Now()
+	q := repository.ReliabilityQuery{Namespace: "team-a", Since: until.Add(-time.Hour), Until: until}
+
+	service.GetReliability(context.Background(), q)
+	service.GetReliability(context.Background(), q)
+	if len(repo.queries) != 1 {
+		t.Fatalf("queried the repository %d times before the cache expired, want 1", len(repo.queries))
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	service.GetReliability(context.Background(), q)
+	if len(repo.queries) != 2 {
+		t.Errorf("queried the repository %d times after the cache expired, want 2", len(repo.queries))
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// PatchType is the media type of a patch, which decides how it's applied
+type PatchType string
+
+const (
+	// JSON Merge Patch, RFC 7396
+	PatchTypeMerge PatchType = "application/merge-patch+json"
+	// JSON Patch, RFC 6902
+	PatchTypeJSON PatchType = "application/json-patch+json"
+)
+
+var (
+	ErrInvalidPatch    = errors.New("invalid patch")
+	ErrPatchTestFailed = apperrors.New(apperrors.ErrConflict, "patch test operation failed")
+)
+
+// ReplaceIssue overwrites every editable field of an issue, see dto.ReplaceIssueRequest.
+// A ValidationError is returned if the replacement isn't valid. If version isn't 0
+// the issue is only replaced if it's still at that version.
+func (s *IssueService) ReplaceIssue(ctx context.Context, issue *models.Issue, req dto.ReplaceIssueRequest, version int64) (*models.Issue, error) {
+	if err := ValidateReplaceIssueRequest(&req, issue); err != nil {
+		return nil, err
+	}
+	return s.repo.Replace(ctx, issue.ID, req, version)
+}
+
+// PatchIssue applies a patch to the editable fields of an issue and saves the result like ReplaceIssue.
+//
+// The patch is applied to the issue in the shape of dto.ReplaceIssueRequest, with links sorted as
+// they are when the issue is fetched, so JSON Patch operations can add or remove single links and labels.
+// ErrInvalidPatch is returned if the patch can't be applied and a ValidationError if the patched issue isn't valid.
+// The patched issue is only saved if it hasn't changed since it was loaded, otherwise repository.ErrVersionMismatch is returned.
+func (s *IssueService) PatchIssue(ctx context.Context, issue *models.Issue, patchType PatchType, patch []byte) (*models.Issue, error) {
+	document, err := json.Marshal(editableIssue(issue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode issue for patching: %w", err)
+	}
+
+	var patched []byte
+	switch patchType {
+	case PatchTypeMerge:
+		patched, err = jsonpatch.MergePatch(document, patch)
+	case PatchTypeJSON:
+		var operations jsonpatch.Patch
+		if operations, err = jsonpatch.DecodePatch(patch); err == nil {
+			patched, err = operations.Apply(document)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch type %q", ErrInvalidPatch, patchType)
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return nil, ErrPatchTestFailed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+
+	// Namespace, scope and read-only fields can't be patched in
+	var req dto.ReplaceIssueRequest
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return nil, decodeError(err)
+	}
+	return s.ReplaceIssue(ctx, issue, req, issue.Version)
+}
+
+// Helper function to get the editable fields of an issue
+func editableIssue(issue *models.Issue) dto.ReplaceIssueRequest {
+	req := dto.ReplaceIssueRequest{
+		Title:       issue.Title,
+		Description: issue.Description,
+		Severity:    issue.Severity,
+		IssueType:   issue.IssueType,
+		State:       issue.State,
+		ResolvedAt:  issue.ResolvedAt,
+		Assignee:    issue.Assignee,
+		Labels:      make([]string, len(issue.Labels)),
+		Links:       make([]dto.ReplaceLinkRequest, len(issue.Links)),
+	}
+	copy(req.Labels, issue.Labels)
+	for idx, link := range issue.Links {
+		req.Links[idx] = dto.ReplaceLinkRequest{ID: link.ID, Title: link.Title, URL: link.URL}
+	}
+	return req
+}
+
+// Helper function to convert an error decoding a patched issue to a ValidationError for the field at fault
+func decodeError(err error) *apperrors.ValidationError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return apperrors.InvalidField(typeErr.Field, "can't be a "+typeErr.Value)
+	}
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		if field, unquoteErr := strconv.Unquote(name); unquoteErr == nil {
+			return apperrors.InvalidField(field, "can't be changed")
+		}
+	}
+	return &apperrors.ValidationError{Fields: []dto.FieldError{{Message: err.Error()}}}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// replacedIssues records the replacement saved for an issue, the rest of the repository isn't used
+type replacedIssues struct {
+	repository.IssueRepository
+	replaced *dto.ReplaceIssueRequest
+	version  int64
+}
+
+func (r *replacedIssues) Replace(_ context.Context, id string, req dto.ReplaceIssueRequest, version int64) (*models.Issue, error) {
+	r.replaced, r.version = &req, version
+	return &models.Issue{ID: id, Title: req.Title}, nil
+}
+
+func patchedIssue() *models.Issue {
+	assignee := "alice"
+	return &models.Issue{
+		ID:          "issue-1",
+		Title:       "Build failed",
+		Description: "The build failed",
+		Severity:    models.SeverityMajor,
+		IssueType:   models.IssueTypeBuild,
+		State:       models.IssueStateActive,
+		DetectedAt:  time.Now().Add(-time.Hour),
+		Assignee:    &assignee,
+		Labels:      []string{"flaky", "ci"},
+		Links:       []models.Link{{ID: "link-1", Title: "Logs", URL: "https://logs.example.com/1"}},
+		Version:     3,
+	}
+}
+
+func TestPatchIssueMergePatch(t *testing.T) {
+	repo := &replacedIssues{}
+	service := NewIssueService(repo, logrus.New())
+
+	// Null removes a field, fields left out are kept
+	_, err := service.PatchIssue(context.Background(), patchedIssue(), PatchTypeMerge,
+		[]byte(`{"title": "Build failed again", "assignee": null, "labels": ["ci"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := repo.replaced
+	if req.Title != "Build failed again" || req.Description != "The build failed" || req.Severity != models.SeverityMajor {
+		t.Errorf("replaced with %+v", req)
+	}
+	if req.Assignee != nil {
+		t.Errorf("assignee = %q, want it removed", *req.Assignee)
+	}
+	if !slices.Equal(req.Labels, []string{"ci"}) {
+		t.Errorf("labels = %v, want [ci]", req.Labels)
+	}
+	if len(req.Links) != 1 || req.Links[0].ID != "link-1" {
+		t.Errorf("links = %+v, want the existing link kept", req.Links)
+	}
+	if repo.version != 3 {
+		t.Errorf("saved over version %d, want the version loaded", repo.version)
+	}
+}
+
+func TestPatchIssueJSONPatch(t *testing.T) {
+	repo := &replacedIssues{}
+	service := NewIssueService(repo, logrus.New())
+
+	_, err := service.PatchIssue(context.Background(), patchedIssue(), PatchTypeJSON, []byte(`[
+		{"op": "test", "path": "/severity", "value": "major"},
+		{"op": "replace", "path": "/severity", "value": "critical"},
+		{"op": "remove", "path": "/labels/0"},
+		{"op": "add", "path": "/links/-", "value": {"title": "Run", "url": "https://ci.example.com/1"}},
+		{"op": "remove", "path": "/assignee"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := repo.replaced
+	if req.Severity != models.SeverityCritical || req.Assignee != nil || !slices.Equal(req.Labels, []string{"ci"}) {
+		t.Errorf("replaced with %+v", req)
+	}
+	if len(req.Links) != 2 || req.Links[0].ID != "link-1" || req.Links[1].ID != "" || req.Links[1].URL != "https://ci.example.com/1" {
+		t.Errorf("links = %+v, want the existing link kept and a new one added", req.Links)
+	}
+}
+
+func TestPatchIssueRejectsInvalidPatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		patchType PatchType
+		patch     string
+		check     func(err error) bool
+	}{
+		{
+			name:      "failed test operation",
+			patchType: PatchTypeJSON,
+			patch:     `[{"op": "test", "path": "/severity", "value": "minor"}, {"op": "replace", "path": "/severity", "value": "critical"}]`,
+			check: func(err error) bool {
+				return errors.Is(err, ErrPatchTestFailed) && errors.Is(err, apperrors.ErrConflict)
+			},
+		},
+		{
+			name:      "path that doesn't exist",
+			patchType: PatchTypeJSON,
+			patch:     `[{"op": "replace", "path": "/scope/resourceName", "value": "api"}]`,
+			check:     func(err error) bool { return errors.Is(err, ErrInvalidPatch) },
+		},
+		{
+			name:      "index out of range",
+			patchType: PatchTypeJSON,
+			patch:     `[{"op": "remove", "path": "/links/5"}]`,
+			check:     func(err error) bool { return errors.Is(err, ErrInvalidPatch) },
+		},
+		{
+			name:      "unknown operation",
+			patchType: PatchTypeJSON,
+			patch:     `[{"op": "rename", "path": "/title"}]`,
+			check:     func(err error) bool { return errors.Is(err, ErrInvalidPatch) },
+		},
+		{
+			name:      "not a JSON Patch",
+			patchType: PatchTypeJSON,
+			patch:     `{"title": "Build failed"}`,
+			check:     func(err error) bool { return errors.Is(err, ErrInvalidPatch) },
+		},
+		{
+			name:      "unsupported type",
+			patchType: "application/json",
+			patch:     `{"title": "Build failed"}`,
+			check:     func(err error) bool { return errors.Is(err, ErrInvalidPatch) },
+		},
+		{
+			name:      "field that can't be changed",
+			patchType: PatchTypeJSON,
+			patch:     `[{"op": "add", "path": "/namespace", "value": "team-b"}]`,
+			check:     isInvalidField("namespace"),
+		},
+		{
+			name:      "required field removed",
+			patchType: PatchTypeMerge,
+			patch:     `{"description": null}`,
+			check:     func(err error) bool { return errors.Is(err, apperrors.ErrValidation) },
+		},
+		{
+			name:      "wrong type",
+			patchType: PatchTypeMerge,
+			patch:     `{"labels": "ci"}`,
+			check:     isInvalidField("labels"),
+		},
+		{
+			name:      "link of another issue",
+			patchType: PatchTypeJSON,
+			patch:     `[{"op": "replace", "path": "/links/0/id", "value": "link-2"}]`,
+			check:     isInvalidField("links[0].id"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &replacedIssues{}
+			service := NewIssueService(repo, logrus.New())
+
+			_, err := service.PatchIssue(context.Background(), patchedIssue(), tt.patchType, []byte(tt.patch))
+			if !tt.check(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if repo.replaced != nil {
+				t.Errorf("saved %+v after a rejected patch", repo.replaced)
+			}
+		})
+	}
+}
+
+func isInvalidField(field string) func(err error) bool {
+	return func(err error) bool {
+		var validationErr *apperrors.ValidationError
+		return errors.As(err, &validationErr) && validationErr.Fields[0].Field == field
+	}
+}
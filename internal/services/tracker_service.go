@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/tracker"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrUnknownTracker  = apperrors.New(apperrors.ErrNotFound, "unknown tracker")
+	ErrAlreadyExported = apperrors.New(apperrors.ErrConflict, "issue already exported to tracker")
+)
+
+type registeredTracker struct {
+	tracker tracker.Tracker
+	// Active issues at or above this severity are exported automatically, empty to disable
+	autoExportSeverity models.Severity
+}
+
+// TrackerService exports issues to external trackers and keeps their states in sync
+type TrackerService struct {
+	issueRepo repository.IssueRepository
+	linkRepo  repository.LinkRepository
+	trackers  map[string]registeredTracker
+	logger    *logrus.Logger
+}
+
+func NewTrackerService(issueRepo repository.IssueRepository, linkRepo repository.LinkRepository, logger *logrus.Logger) *TrackerService {
+	return &TrackerService{
+		issueRepo: issueRepo,
+		linkRepo:  linkRepo,
+		trackers:  map[string]registeredTracker{},
+		logger:    logger,
+	}
+}
+
+// Register adds a tracker issues can be exported to
+func (s *TrackerService) Register(t tracker.Tracker, autoExportSeverity models.Severity) {
+	s.trackers[t.Name()] = registeredTracker{
+		tracker:            t,
+		autoExportSeverity: autoExportSeverity,
+	}
+}
+
+// Tracker returns a registered tracker by name
+func (s *TrackerService) Tracker(name string) (tracker.Tracker, error) {
+	registered, ok := s.trackers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTracker, name)
+	}
+	return registered.tracker, nil
+}
+
+// ExportIssue creates an external issue for a Kite issue and stores it as a link on the issue
+func (s *TrackerService) ExportIssue(ctx context.Context, issue *models.Issue, trackerName string) (*models.Link, error) {
+	t, err := s.Tracker(trackerName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only export an issue once per tracker
+	links, err := s.linkRepo.FindByIssueID(ctx, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if _, ok := t.KeyFromURL(link.URL); ok {
+			return &link, ErrAlreadyExported
+		}
+	}
+
+	external, err := t.CreateIssue(ctx, issue)
+	if err != nil {
+		return nil, err
+	}
+
+	link := models.Link{
+		Title: fmt.Sprintf("%s %s", strings.ToUpper(trackerName[:1])+trackerName[1:], external.Key),
+		URL:   external.URL,
+	}
+	if err := s.linkRepo.CreateBatch(ctx, issue.ID, []models.Link{link}); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"issue_id":     issue.ID,
+		"tracker":      trackerName,
+		"external_key": external.Key,
+	}).Info("Exported issue to tracker")
+
+	return &link, nil
+}
+
+// ResolveFromExternal resolves the Kite issue linked to an external issue that was closed.
+// Returns nil if no active Kite issue is linked to the external issue.
+func (s *TrackerService) ResolveFromExternal(ctx context.Context, trackerName, key string) (*models.Issue, error) {
+	t, err := s.Tracker(trackerName)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.linkRepo.FindByURL(ctx, t.LinkPrefix()+key)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.Issue.State != models.IssueStateActive {
+		return nil, nil
+	}
+
+	return s.resolveIssue(ctx, link.IssueID)
+}
+
+// Run syncs issue states with all trackers every interval until the context is cancelled
+func (s *TrackerService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Catch up on issues resolved before we started
+	since := time.Now().Add(-interval)
+	for {
+		syncStart := time.Now()
+		for name := range s.trackers {
+			if err := s.Sync(ctx, name, since); err != nil {
+				s.logger.WithError(err).WithField("tracker", name).Error("Failed to sync tracker")
+			}
+		}
+		since = syncStart
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync brings a tracker and Kite in line:
+//   - Active issues matching the tracker's auto export rule are exported
+//   - Active issues whose external issue was closed are resolved
+//   - External issues of issues resolved since the time passed are closed
+func (s *TrackerService) Sync(ctx context.Context, trackerName string, since time.Time) error {
+	registered, ok := s.trackers[trackerName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTracker, trackerName)
+	}
+	t := registered.tracker
+	logger := s.logger.WithField("tracker", trackerName)
+
+	if registered.autoExportSeverity != "" {
+		issues, err := s.issueRepo.FindActiveWithoutLink(ctx, t.LinkPrefix(), registered.autoExportSeverity.AtLeast())
+		if err != nil {
+			return err
+		}
+		for idx := range issues {
+			if _, err := s.ExportIssue(ctx, &issues[idx], trackerName); err != nil {
+				logger.WithError(err).WithField("issue_id", issues[idx].ID).Error("Failed to auto export issue")
+			}
+		}
+	}
+
+	links, err := s.linkRepo.FindByURLPrefix(ctx, t.LinkPrefix())
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		key, ok := t.KeyFromURL(link.URL)
+		if !ok {
+			continue
+		}
+		entry := logger.WithFields(logrus.Fields{"issue_id": link.IssueID, "external_key": key})
+
+		switch {
+		case link.Issue.State == models.IssueStateActive:
+			external, err := t.GetIssue(ctx, key)
+			if err != nil {
+				entry.WithError(err).Warn("Failed to get external issue")
+				continue
+			}
+			if external.Resolved {
+				if _, err := s.resolveIssue(ctx, link.IssueID); err != nil {
+					entry.WithError(err).Error("Failed to resolve issue closed in tracker")
+				}
+			}
+		case link.Issue.ResolvedAt != nil && !link.Issue.ResolvedAt.Before(since):
+			external, err := t.GetIssue(ctx, key)
+			if err != nil {
+				entry.WithError(err).Warn("Failed to get external issue")
+				continue
+			}
+			if !external.Resolved {
+				if err := t.ResolveIssue(ctx, key); err != nil {
+					entry.WithError(err).Error("Failed to close external issue")
+					continue
+				}
+				entry.Info("Closed external issue of resolved issue")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Helper function to mark an issue as resolved
+func (s *TrackerService) resolveIssue(ctx context.Context, id string) (*models.Issue, error) {
+	now := time.Now()
+	state := models.IssueStateResolved
+	issue, err := s.issueRepo.Update(ctx, id, dto.UpdateIssueRequest{
+		State:      &state,
+		ResolvedAt: &now,
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithField("issue_id", id).Info("Resolved issue closed in external tracker")
+	return issue, nil
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/tracker"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// trackedIssues keeps issues and their links in memory, the rest of the repositories isn't used
+type trackedIssues struct {
+	repository.IssueRepository
+	issues map[string]*models.Issue
+	links  []models.Link
+}
+
+func (r *trackedIssues) FindActiveWithoutLink(_ context.Context, linkPrefix string, severities []models.Severity) ([]models.Issue, error) {
+	var found []models.Issue
+	for _, issue := range r.issues {
+		linked := false
+		for _, link := range r.links {
+			linked = linked || (link.IssueID == issue.ID && strings.HasPrefix(link.URL, linkPrefix))
+		}
+		if !linked && issue.State == models.IssueStateActive && slices.Contains(severities, issue.Severity) {
+			found = append(found, *issue)
+		}
+	}
+	return found, nil
+}
+
+func (r *trackedIssues) Update(_ context.Context, id string, updates dto.UpdateIssueRequest, _ int64) (*models.Issue, error) {
+	issue := r.issues[id]
+	issue.State = *updates.State
+	issue.ResolvedAt = updates.ResolvedAt
+	return issue, nil
+}
+
+// trackedLinks is the link repository of the tracked issues, with the issue of each link loaded
+type trackedLinks struct {
+	*trackedIssues
+}
+
+func (l trackedLinks) withIssues(found []models.Link) []models.Link {
+	for i := range found {
+		found[i].Issue = *l.issues[found[i].IssueID]
+	}
+	return found
+}
+
+func (l trackedLinks) CreateBatch(_ context.Context, issueID string, created []models.Link) error {
+	for _, link := range created {
+		link.IssueID = issueID
+		l.links = append(l.links, link)
+	}
+	return nil
+}
+
+func (l trackedLinks) DeleteByIssueID(context.Context, string) error {
+	return nil
+}
+
+func (l trackedLinks) FindByIssueID(_ context.Context, issueID string) ([]models.Link, error) {
+	var found []models.Link
+	for _, link := range l.links {
+		if link.IssueID == issueID {
+			found = append(found, link)
+		}
+	}
+	return l.withIssues(found), nil
+}
+
+func (l trackedLinks) FindByURL(_ context.Context, url string) (*models.Link, error) {
+	for _, link := range l.links {
+		if link.URL == url {
+			return &l.withIssues([]models.Link{link})[0], nil
+		}
+	}
+	return nil, nil
+}
+
+func (l trackedLinks) FindByURLPrefix(_ context.Context, prefix string) ([]models.Link, error) {
+	var found []models.Link
+	for _, link := range l.links {
+		if strings.HasPrefix(link.URL, prefix) {
+			found = append(found, link)
+		}
+	}
+	return l.withIssues(found), nil
+}
+
+// Helper function to serve a fake Jira with tickets by key and their status category, closing tickets moves them to done
+func fakeJira(t *testing.T, tickets map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, transitions := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/transitions")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			key := fmt.Sprintf("KITE-%d", len(tickets)+1)
+			tickets[key] = "new"
+			json.NewEncoder(w).Encode(map[string]string{"key": key})
+		case tickets[key] == "":
+			http.NotFound(w, r)
+		case r.Method == http.MethodGet && !transitions:
+			json.NewEncoder(w).Encode(map[string]any{
+				"key":    key,
+				"fields": map[string]any{"status": map[string]any{"statusCategory": map[string]string{"key": tickets[key]}}},
+			})
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]any{"transitions": []map[string]any{
+				{"id": "31", "to": map[string]any{"statusCategory": map[string]string{"key": "done"}}},
+			}})
+		case r.Method == http.MethodPost:
+			tickets[key] = "done"
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func trackerTestService(repo *trackedIssues, jiraURL string, autoExportSeverity models.Severity) *TrackerService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := NewTrackerService(repo, trackedLinks{repo}, logger)
+	service.Register(tracker.NewJiraClient(config.JiraConfig{BaseURL: jiraURL, ProjectKey: "KITE", IssueType: "Bug"}), autoExportSeverity)
+	return service
+}
+
+func TestTrackerServiceSync(t *testing.T) {
+	tickets := map[string]string{"KITE-1": "done", "KITE-2": "indeterminate", "KITE-3": "new", "KITE-4": "new"}
+	jira := fakeJira(t, tickets)
+	browse := jira.URL + "/browse/"
+
+	lastSync := time.Now().Add(-time.Hour)
+	resolvedSince := lastSync.Add(time.Minute)
+	resolvedBefore := lastSync.Add(-time.Minute)
+	repo := &trackedIssues{
+		issues: map[string]*models.Issue{
+			// Ticket was closed in Jira
+			"closed-in-jira": {ID: "closed-in-jira", State: models.IssueStateActive, Severity: models.SeverityMajor},
+			// Ticket is still open
+			"open": {ID: "open", State: models.IssueStateActive, Severity: models.SeverityMajor},
+			// Issue was resolved since the last sync
+			"resolved-in-kite": {ID: "resolved-in-kite", State: models.IssueStateResolved, ResolvedAt: &resolvedSince},
+			// Issue was resolved before the last sync, the ticket was reopened since
+			"resolved-earlier": {ID: "resolved-earlier", State: models.IssueStateResolved, ResolvedAt: &resolvedBefore},
+			// Issues without tickets, only the critical one is exported
+			"critical": {ID: "critical", State: models.IssueStateActive, Severity: models.SeverityCritical},
+			"minor":    {ID: "minor", State: models.IssueStateActive, Severity: models.SeverityMinor},
+		},
+		links: []models.Link{
+			{IssueID: "closed-in-jira", URL: browse + "KITE-1"},
+			{IssueID: "open", URL: browse + "KITE-2"},
+			{IssueID: "resolved-in-kite", URL: browse + "KITE-3"},
+			{IssueID: "resolved-earlier", URL: browse + "KITE-4"},
+		},
+	}
+	service := trackerTestService(repo, jira.URL, models.SeverityCritical)
+
+	if err := service.Sync(context.Background(), "jira", lastSync); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if state := repo.issues["closed-in-jira"].State; state != models.IssueStateResolved {
+		t.Errorf("issue closed in Jira is %s, want it resolved", state)
+	}
+	if state := repo.issues["open"].State; state != models.IssueStateActive {
+		t.Errorf("issue of an open ticket is %s, want it active", state)
+	}
+	if status := tickets["KITE-3"]; status != "done" {
+		t.Errorf("ticket of an issue resolved since the last sync is %s, want it done", status)
+	}
+	if status := tickets["KITE-4"]; status != "new" {
+		t.Errorf("ticket of an issue resolved before the last sync is %s, want it left alone", status)
+	}
+
+	var exported []string
+	for _, link := range repo.links[4:] {
+		exported = append(exported, link.IssueID+" "+strings.TrimPrefix(link.URL, browse))
+	}
+	if len(exported) != 1 || exported[0] != "critical KITE-5" {
+		t.Errorf("exported %v, want only the critical issue as KITE-5", exported)
+	}
+}
+
+func TestTrackerServiceResolveFromExternal(t *testing.T) {
+	jira := fakeJira(t, map[string]string{})
+	browse := jira.URL + "/browse/"
+	repo := &trackedIssues{
+		issues: map[string]*models.Issue{
+			"active":   {ID: "active", State: models.IssueStateActive},
+			"resolved": {ID: "resolved", State: models.IssueStateResolved},
+		},
+		links: []models.Link{
+			{IssueID: "active", URL: browse + "KITE-1"},
+			{IssueID: "resolved", URL: browse + "KITE-2"},
+		},
+	}
+	service := trackerTestService(repo, jira.URL, "")
+
+	issue, err := service.ResolveFromExternal(context.Background(), "jira", "KITE-1")
+	if err != nil {
+		t.Fatalf("ResolveFromExternal() error = %v", err)
+	}
+	if issue == nil || issue.ID != "active" || issue.State != models.IssueStateResolved || issue.ResolvedAt == nil {
+		t.Errorf("ResolveFromExternal() = %+v, want the linked issue resolved", issue)
+	}
+
+	// Issues that were already resolved and tickets without an issue are ignored
+	for _, key := range []string{"KITE-2", "KITE-9"} {
+		if issue, err := service.ResolveFromExternal(context.Background(), "jira", key); issue != nil || err != nil {
+			t.Errorf("ResolveFromExternal(%s) = %+v, %v, want it ignored", key, issue, err)
+		}
+	}
+
+	if _, err := service.ResolveFromExternal(context.Background(), "github", "KITE-1"); err == nil {
+		t.Error("ResolveFromExternal() succeeded for a tracker that isn't registered")
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// ValidateCreateIssueRequest checks the enumerated values of a request to create an issue, returning a ValidationError
+func ValidateCreateIssueRequest(req dto.CreateIssueRequest) error {
+	return validateIssueValues(req.Severity, req.IssueType, req.State)
+}
+
+// ValidateReplaceIssueRequest checks a full replacement of an issue, normalizing it along the way.
+// Labels are trimmed and deduplicated and an empty assignee unassigns the issue.
+// Issues being resolved without a resolution time are resolved now, and reopened issues have theirs cleared.
+// Invalid requests return a ValidationError.
+func ValidateReplaceIssueRequest(req *dto.ReplaceIssueRequest, issue *models.Issue) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return NewValidationError(err)
+	}
+	if err := validateIssueValues(req.Severity, req.IssueType, req.State); err != nil {
+		return err
+	}
+
+	labels := make([]string, 0, len(req.Labels))
+	for _, label := range req.Labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return apperrors.InvalidField("labels", "can't be empty")
+		}
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	req.Labels = labels
+
+	if req.Assignee != nil && strings.TrimSpace(*req.Assignee) == "" {
+		req.Assignee = nil
+	}
+
+	for i, link := range req.Links {
+		if link.ID != "" && !slices.ContainsFunc(issue.Links, func(existing models.Link) bool { return existing.ID == link.ID }) {
+			return apperrors.InvalidField(fmt.Sprintf("links[%d].id", i), fmt.Sprintf("link %s does not belong to this issue", link.ID))
+		}
+	}
+
+	switch {
+	case req.State == models.IssueStateActive && req.ResolvedAt != nil:
+		if issue.ResolvedAt == nil || !req.ResolvedAt.Equal(*issue.ResolvedAt) {
+			return apperrors.InvalidField("resolvedAt", "must be null for active issues")
+		}
+		req.ResolvedAt = nil
+	case req.State == models.IssueStateResolved && req.ResolvedAt == nil:
+		if issue.State == models.IssueStateResolved && issue.ResolvedAt != nil {
+			return apperrors.InvalidField("resolvedAt", "is required for resolved issues")
+		}
+		now := time.Now()
+		req.ResolvedAt = &now
+	case req.ResolvedAt != nil && req.ResolvedAt.Before(issue.DetectedAt):
+		return apperrors.InvalidField("resolvedAt", "is before detectedAt")
+	}
+	return nil
+}
+
+// Helper function to check the enumerated values of an issue, state is optional
+func validateIssueValues(severity models.Severity, issueType models.IssueType, state models.IssueState) error {
+	// Validate severity
+	validSeverities := []models.Severity{
+		models.SeverityInfo, models.SeverityMinor,
+		models.SeverityMajor, models.SeverityCritical,
+	}
+
+	if !slices.Contains(validSeverities, severity) {
+		return apperrors.InvalidField("severity", "invalid severity value")
+	}
+
+	// Validate issue type
+	validTypes := []models.IssueType{
+		models.IssueTypeBuild, models.IssueTypeTest,
+		models.IssueTypeRelease, models.IssueTypeDependency,
+		models.IssueTypePipeline,
+	}
+	if !slices.Contains(validTypes, issueType) {
+		return apperrors.InvalidField("issueType", "invalid issueType value")
+	}
+
+	// validate state if provided
+	if state != "" {
+		validStates := []models.IssueState{models.IssueStateActive, models.IssueStateResolved}
+		if !slices.Contains(validStates, state) {
+			return apperrors.InvalidField("state", "invalid state value")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+
+	"github.com/konflux-ci/kite/internal/apperrors"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrViewNotFound is also returned for views the user can't see, so private views aren't revealed
+	ErrViewNotFound = apperrors.New(apperrors.ErrNotFound, "view not found")
+	ErrViewNotOwner = apperrors.New(apperrors.ErrForbidden, "only the owner can change a view")
+)
+
+type SavedViewService struct {
+	repo   repository.SavedViewRepository
+	logger *logrus.Logger
+}
+
+func NewSavedViewService(repo repository.SavedViewRepository, logger *logrus.Logger) *SavedViewService {
+	return &SavedViewService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ListViews returns the views a user can see in a namespace
+func (s *SavedViewService) ListViews(ctx context.Context, namespace, user string) ([]models.SavedView, error) {
+	return s.repo.FindVisible(ctx, namespace, user)
+}
+
+// GetView returns a view if it's in the namespace and visible to the user
+func (s *SavedViewService) GetView(ctx context.Context, id, namespace, user string) (*models.SavedView, error) {
+	view, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if view == nil || view.Namespace != namespace {
+		return nil, ErrViewNotFound
+	}
+	if view.Visibility != models.ViewVisibilityShared && view.Owner != user {
+		return nil, ErrViewNotFound
+	}
+	return view, nil
+}
+
+// CreateView saves a new view owned by the user
+func (s *SavedViewService) CreateView(ctx context.Context, namespace, owner string, req dto.SavedViewRequest) (*models.SavedView, error) {
+	view := &models.SavedView{
+		Name:       req.Name,
+		Owner:      owner,
+		Namespace:  namespace,
+		Visibility: req.Visibility,
+		Filters:    req.Filters,
+	}
+	if view.Visibility == "" {
+		view.Visibility = models.ViewVisibilityPrivate
+	}
+
+	if err := s.repo.Create(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// UpdateView replaces the name, visibility and filters of a view, only its owner can update it
+func (s *SavedViewService) UpdateView(ctx context.Context, id, namespace, user string, req dto.SavedViewRequest) (*models.SavedView, error) {
+	view, err := s.ownedView(ctx, id, namespace, user)
+	if err != nil {
+		return nil, err
+	}
+
+	view.Name = req.Name
+	view.Filters = req.Filters
+	if req.Visibility != "" {
+		view.Visibility = req.Visibility
+	}
+
+	if err := s.repo.Update(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// DeleteView removes a view, only its owner can delete it
+func (s *SavedViewService) DeleteView(ctx context.Context, id, namespace, user string) error {
+	if _, err := s.ownedView(ctx, id, namespace, user); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Helper function to find a view the user is allowed to change
+func (s *SavedViewService) ownedView(ctx context.Context, id, namespace, user string) (*models.SavedView, error) {
+	view, err := s.GetView(ctx, id, namespace, user)
+	if err != nil {
+		return nil, err
+	}
+	if view.Owner != user {
+		return nil, ErrViewNotOwner
+	}
+	return view, nil
+}
@@ -0,0 +1,192 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// Jira status category key for issues that are considered done
+const jiraStatusCategoryDone = "done"
+
+// Maps Kite severities to default Jira priorities
+var jiraPriorities = map[models.Severity]string{
+	models.SeverityInfo:     "Low",
+	models.SeverityMinor:    "Medium",
+	models.SeverityMajor:    "High",
+	models.SeverityCritical: "Highest",
+}
+
+// JiraClient talks to the Jira REST API (v2)
+type JiraClient struct {
+	cfg        config.JiraConfig
+	httpClient *http.Client
+}
+
+// NewJiraClient returns a new Jira tracker client
+func NewJiraClient(cfg config.JiraConfig) *JiraClient {
+	return &JiraClient{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Status struct {
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+type jiraTransition struct {
+	ID string `json:"id"`
+	To struct {
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	} `json:"to"`
+}
+
+func (j *JiraClient) Name() string {
+	return "jira"
+}
+
+func (j *JiraClient) LinkPrefix() string {
+	return strings.TrimSuffix(j.cfg.BaseURL, "/") + "/browse/"
+}
+
+func (j *JiraClient) KeyFromURL(url string) (string, bool) {
+	key, found := strings.CutPrefix(url, j.LinkPrefix())
+	if !found || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+// CreateIssue creates a Jira issue in the configured project
+func (j *JiraClient) CreateIssue(ctx context.Context, issue *models.Issue) (*ExternalIssue, error) {
+	description := fmt.Sprintf("%s\n\nNamespace: %s\nScope: %s/%s\nDetected at: %s\nKite issue: %s",
+		issue.Description, issue.Namespace, issue.Scope.ResourceType, issue.Scope.ResourceName,
+		issue.DetectedAt.UTC().Format(time.RFC3339), issue.ID)
+
+	body := map[string]any{
+		"fields": map[string]any{
+			"project":     map[string]string{"key": j.cfg.ProjectKey},
+			"issuetype":   map[string]string{"name": j.cfg.IssueType},
+			"summary":     issue.Title,
+			"description": description,
+			"priority":    map[string]string{"name": jiraPriorities[issue.Severity]},
+			"labels":      []string{"kite", string(issue.IssueType), issue.Namespace},
+		},
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create jira issue: %w", err)
+	}
+
+	return &ExternalIssue{
+		Key: created.Key,
+		URL: j.LinkPrefix() + created.Key,
+	}, nil
+}
+
+// GetIssue fetches a Jira issue and its status
+func (j *JiraClient) GetIssue(ctx context.Context, key string) (*ExternalIssue, error) {
+	var issue jiraIssue
+	if err := j.do(ctx, http.MethodGet, "/rest/api/2/issue/"+key+"?fields=status", nil, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get jira issue %s: %w", key, err)
+	}
+
+	return &ExternalIssue{
+		Key:      issue.Key,
+		URL:      j.LinkPrefix() + issue.Key,
+		Resolved: issue.Fields.Status.StatusCategory.Key == jiraStatusCategoryDone,
+	}, nil
+}
+
+// ResolveIssue moves a Jira issue to the first available status in the "done" category
+func (j *JiraClient) ResolveIssue(ctx context.Context, key string) error {
+	var transitions struct {
+		Transitions []jiraTransition `json:"transitions"`
+	}
+	if err := j.do(ctx, http.MethodGet, "/rest/api/2/issue/"+key+"/transitions", nil, &transitions); err != nil {
+		return fmt.Errorf("failed to get jira transitions for %s: %w", key, err)
+	}
+
+	for _, transition := range transitions.Transitions {
+		if transition.To.StatusCategory.Key != jiraStatusCategoryDone {
+			continue
+		}
+		body := map[string]any{
+			"transition": map[string]string{"id": transition.ID},
+		}
+		if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue/"+key+"/transitions", body, nil); err != nil {
+			return fmt.Errorf("failed to transition jira issue %s: %w", key, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no transition to a done status available for jira issue %s", key)
+}
+
+// Helper function to send a request to the Jira API and decode the response
+func (j *JiraClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(j.cfg.BaseURL, "/")+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Jira Cloud uses email + API token, Jira Data Center uses personal access tokens
+	if j.cfg.Username != "" {
+		req.SetBasicAuth(j.cfg.Username, j.cfg.APIToken)
+	} else if j.cfg.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+j.cfg.APIToken)
+	}
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,239 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/kite/internal/config"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// fakeJira serves the parts of the Jira REST API the client uses, with tickets and their status categories
+type fakeJira struct {
+	server *httptest.Server
+	// Status category of each ticket by key
+	tickets map[string]string
+	// Status category each transition of a ticket leads to by ID
+	transitions map[string]string
+	// Authorization header of the last request
+	authorization string
+	// Body of the last issue created
+	created map[string]any
+}
+
+func newFakeJira(t *testing.T) *fakeJira {
+	jira := &fakeJira{
+		tickets:     map[string]string{},
+		transitions: map[string]string{"11": "new", "21": "indeterminate", "31": "done"},
+	}
+	jira.server = httptest.NewServer(http.HandlerFunc(jira.serve))
+	t.Cleanup(jira.server.Close)
+	return jira
+}
+
+func (f *fakeJira) serve(w http.ResponseWriter, r *http.Request) {
+	f.authorization = r.Header.Get("Authorization")
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/rest/api/2/issue")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	key, transitions := strings.CutSuffix(strings.TrimPrefix(path, "/"), "/transitions")
+
+	switch {
+	case r.Method == http.MethodPost && key == "":
+		if err := json.NewDecoder(r.Body).Decode(&f.created); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		newKey := fmt.Sprintf("KITE-%d", len(f.tickets)+1)
+		f.tickets[newKey] = "new"
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"key": newKey})
+	case f.tickets[key] == "":
+		http.Error(w, `{"errorMessages":["Issue does not exist"]}`, http.StatusNotFound)
+	case r.Method == http.MethodGet && !transitions:
+		json.NewEncoder(w).Encode(map[string]any{
+			"key":    key,
+			"fields": map[string]any{"status": map[string]any{"statusCategory": map[string]string{"key": f.tickets[key]}}},
+		})
+	case r.Method == http.MethodGet:
+		var available []map[string]any
+		for _, id := range []string{"11", "21", "31"} {
+			if category, ok := f.transitions[id]; ok {
+				available = append(available, map[string]any{"id": id, "to": map[string]any{"statusCategory": map[string]string{"key": category}}})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"transitions": available})
+	case r.Method == http.MethodPost:
+		var body struct {
+			Transition struct {
+				ID string `json:"id"`
+			} `json:"transition"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || f.transitions[body.Transition.ID] == "" {
+			http.Error(w, `{"errorMessages":["Invalid transition"]}`, http.StatusBadRequest)
+			return
+		}
+		f.tickets[key] = f.transitions[body.Transition.ID]
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeJira) client(cfg config.JiraConfig) *JiraClient {
+	cfg.BaseURL = f.server.URL + "/"
+	cfg.ProjectKey = "KITE"
+	cfg.IssueType = "Bug"
+	return NewJiraClient(cfg)
+}
+
+func TestJiraClientCreateIssue(t *testing.T) {
+	jira := newFakeJira(t)
+	client := jira.client(config.JiraConfig{Username: "kite@example.com", APIToken: "token"})
+
+	issue := &models.Issue{
+		ID:          "issue-1",
+		Title:       "Build failed",
+		Description: "The build of frontend failed",
+		Severity:    models.SeverityCritical,
+		IssueType:   models.IssueTypeBuild,
+		Namespace:   "team-a",
+		DetectedAt:  time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		Scope:       models.IssueScope{ResourceType: "component", ResourceName: "frontend"},
+	}
+	external, err := client.CreateIssue(context.Background(), issue)
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+
+	if external.Key != "KITE-1" || external.URL != jira.server.URL+"/browse/KITE-1" {
+		t.Errorf("created %+v, want KITE-1 linked to the browse URL", external)
+	}
+	fields, _ := jira.created["fields"].(map[string]any)
+	if fields["summary"] != "Build failed" {
+		t.Errorf("summary = %v, want the issue title", fields["summary"])
+	}
+	if priority, _ := fields["priority"].(map[string]any); priority["name"] != "Highest" {
+		t.Errorf("priority = %v, want Highest for a critical issue", fields["priority"])
+	}
+	if project, _ := fields["project"].(map[string]any); project["key"] != "KITE" {
+		t.Errorf("project = %v, want the configured project", fields["project"])
+	}
+	description, _ := fields["description"].(string)
+	for _, want := range []string{"Namespace: team-a", "Scope: component/frontend", "Detected at: 2026-10-19T12:00:00Z", "Kite issue: issue-1"} {
+		if !strings.Contains(description, want) {
+			t.Errorf("description doesn't contain %q: %s", want, description)
+		}
+	}
+	if !strings.HasPrefix(jira.authorization, "Basic ") {
+		t.Errorf("authorization = %q, want basic auth with a username", jira.authorization)
+	}
+}
+
+func TestJiraClientBearerToken(t *testing.T) {
+	jira := newFakeJira(t)
+	jira.tickets["KITE-1"] = "new"
+
+	if _, err := jira.client(config.JiraConfig{APIToken: "pat"}).GetIssue(context.Background(), "KITE-1"); err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+	if jira.authorization != "Bearer pat" {
+		t.Errorf("authorization = %q, want the personal access token", jira.authorization)
+	}
+}
+
+func TestJiraClientGetIssue(t *testing.T) {
+	jira := newFakeJira(t)
+	jira.tickets["KITE-1"] = "indeterminate"
+	jira.tickets["KITE-2"] = "done"
+	client := jira.client(config.JiraConfig{})
+
+	tests := []struct {
+		key          string
+		wantResolved bool
+		wantErr      error
+	}{
+		{key: "KITE-1", wantResolved: false},
+		{key: "KITE-2", wantResolved: true},
+		{key: "KITE-9", wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		external, err := client.GetIssue(context.Background(), tt.key)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetIssue(%s) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("GetIssue(%s) error = %v", tt.key, err)
+			continue
+		}
+		if external.Resolved != tt.wantResolved {
+			t.Errorf("GetIssue(%s) resolved = %v, want %v", tt.key, external.Resolved, tt.wantResolved)
+		}
+	}
+}
+
+func TestJiraClientResolveIssue(t *testing.T) {
+	jira := newFakeJira(t)
+	jira.tickets["KITE-1"] = "new"
+	client := jira.client(config.JiraConfig{})
+
+	if err := client.ResolveIssue(context.Background(), "KITE-1"); err != nil {
+		t.Fatalf("ResolveIssue() error = %v", err)
+	}
+	if jira.tickets["KITE-1"] != "done" {
+		t.Errorf("ticket status = %s, want done", jira.tickets["KITE-1"])
+	}
+
+	// Without a transition to a done status, the ticket can't be resolved
+	delete(jira.transitions, "31")
+	jira.tickets["KITE-2"] = "new"
+	if err := client.ResolveIssue(context.Background(), "KITE-2"); err == nil {
+		t.Error("ResolveIssue() succeeded without a transition to a done status")
+	}
+}
+
+func TestJiraClientReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errorMessages":["You do not have permission"]}`, http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+	client := NewJiraClient(config.JiraConfig{BaseURL: server.URL})
+
+	_, err := client.GetIssue(context.Background(), "KITE-1")
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "You do not have permission") {
+		t.Errorf("GetIssue() error = %v, want the status and message from Jira", err)
+	}
+}
+
+func TestJiraClientKeyFromURL(t *testing.T) {
+	client := NewJiraClient(config.JiraConfig{BaseURL: "https://jira.example.com/"})
+
+	tests := []struct {
+		url     string
+		wantKey string
+		wantOK  bool
+	}{
+		{"https://jira.example.com/browse/KITE-1", "KITE-1", true},
+		{"https://jira.example.com/browse/", "", false},
+		{"https://other.example.com/browse/KITE-1", "", false},
+	}
+	for _, tt := range tests {
+		key, ok := client.KeyFromURL(tt.url)
+		if key != tt.wantKey || ok != tt.wantOK {
+			t.Errorf("KeyFromURL(%s) = %q, %v, want %q, %v", tt.url, key, ok, tt.wantKey, tt.wantOK)
+		}
+	}
+}
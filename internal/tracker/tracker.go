@@ -0,0 +1,34 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// ErrNotFound is returned when an issue does not exist in the external tracker
+var ErrNotFound = errors.New("external issue not found")
+
+// ExternalIssue is an issue in an external tracker
+type ExternalIssue struct {
+	Key      string // Key identifying the issue in the tracker, e.g. KITE-123
+	URL      string // URL to view the issue in a browser
+	Resolved bool
+}
+
+// Tracker is an external issue tracker Kite issues can be exported to
+type Tracker interface {
+	// Name returns the name of the tracker, e.g. jira
+	Name() string
+	// CreateIssue creates an external issue from a Kite issue
+	CreateIssue(ctx context.Context, issue *models.Issue) (*ExternalIssue, error)
+	// GetIssue fetches an external issue by key
+	GetIssue(ctx context.Context, key string) (*ExternalIssue, error)
+	// ResolveIssue closes an external issue
+	ResolveIssue(ctx context.Context, key string) error
+	// LinkPrefix returns the prefix shared by the URLs of all issues in the tracker
+	LinkPrefix() string
+	// KeyFromURL extracts the issue key from an issue URL
+	KeyFromURL(url string) (string, bool)
+}
@@ -0,0 +1,15 @@
+-- Create "outbox_events" table
+CREATE TABLE "public"."outbox_events" (
+ "id" bigserial NOT NULL,
+ "type" character varying(100) NOT NULL,
+ "issue_id" uuid NOT NULL,
+ "namespace" text NOT NULL,
+ "payload" jsonb NOT NULL,
+ "attempts" bigint NOT NULL DEFAULT 0,
+ "last_error" text NULL,
+ "created_at" timestamptz NULL,
+ "dispatched_at" timestamptz NULL,
+ PRIMARY KEY ("id")
+);
+-- Create index "idx_outbox_events_pending" to table: "outbox_events"
+CREATE INDEX "idx_outbox_events_pending" ON "public"."outbox_events" ("dispatched_at") WHERE (dispatched_at IS NULL);
@@ -0,0 +1,4 @@
+-- Create index "idx_issues_detected_at_id" to table: "issues"
+CREATE INDEX "idx_issues_detected_at_id" ON "public"."issues" ("detected_at" DESC, "id" DESC);
+-- Create index "idx_issues_namespace_detected_at_id" to table: "issues"
+CREATE INDEX "idx_issues_namespace_detected_at_id" ON "public"."issues" ("namespace", "detected_at" DESC, "id" DESC);
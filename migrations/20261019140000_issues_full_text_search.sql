@@ -0,0 +1,8 @@
+-- Modify "issues" table
+ALTER TABLE "public"."issues" ADD COLUMN "scope_terms" text NOT NULL DEFAULT '';
+-- Backfill "scope_terms" from "issue_scopes"
+UPDATE "public"."issues" SET "scope_terms" = btrim("issue_scopes"."resource_type" || ' ' || "issue_scopes"."resource_name") FROM "public"."issue_scopes" WHERE "issues"."scope_id" = "issue_scopes"."id";
+-- Modify "issues" table
+ALTER TABLE "public"."issues" ADD COLUMN "search_vector" tsvector NULL GENERATED ALWAYS AS ((setweight(to_tsvector('english'::regconfig, COALESCE(title, ''::text)), 'A'::"char") || setweight(to_tsvector('english'::regconfig, COALESCE(description, ''::text)), 'B'::"char")) || setweight(to_tsvector('simple'::regconfig, COALESCE(scope_terms, ''::text)), 'C'::"char")) STORED;
+-- Create index "idx_issues_search_vector" to table: "issues"
+CREATE INDEX "idx_issues_search_vector" ON "public"."issues" USING gin ("search_vector");
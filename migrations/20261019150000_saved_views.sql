@@ -0,0 +1,14 @@
+-- Create "saved_views" table
+CREATE TABLE "public"."saved_views" (
+ "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+ "name" text NOT NULL,
+ "owner" text NOT NULL,
+ "namespace" text NOT NULL,
+ "visibility" character varying(20) NOT NULL DEFAULT 'private',
+ "filters" jsonb NOT NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ PRIMARY KEY ("id")
+);
+-- Create index "idx_saved_views_namespace_owner_name" to table: "saved_views"
+CREATE UNIQUE INDEX "idx_saved_views_namespace_owner_name" ON "public"."saved_views" ("namespace", "owner", "name");
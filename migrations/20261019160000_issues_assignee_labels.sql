@@ -0,0 +1,2 @@
+-- Modify "issues" table
+ALTER TABLE "public"."issues" ADD COLUMN "assignee" text NULL, ADD COLUMN "labels" jsonb NOT NULL DEFAULT '[]';
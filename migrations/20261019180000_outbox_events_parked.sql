@@ -0,0 +1,6 @@
+-- Modify "outbox_events" table
+ALTER TABLE "public"."outbox_events" ADD COLUMN "parked_at" timestamptz NULL;
+-- Drop index "idx_outbox_events_pending" from table: "outbox_events"
+DROP INDEX "public"."idx_outbox_events_pending";
+-- Create index "idx_outbox_events_pending" to table: "outbox_events"
+CREATE INDEX "idx_outbox_events_pending" ON "public"."outbox_events" ("dispatched_at") WHERE ((dispatched_at IS NULL) AND (parked_at IS NULL));
@@ -0,0 +1,4 @@
+-- Modify "outbox_events" table
+ALTER TABLE "public"."outbox_events" ADD COLUMN "tx_id" bigint NOT NULL DEFAULT (pg_current_xact_id())::text::bigint;
+-- Create index "idx_outbox_events_position" to table: "outbox_events"
+CREATE INDEX "idx_outbox_events_position" ON "public"."outbox_events" ("tx_id", "id");
@@ -0,0 +1,417 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: kite/v1/issues.proto
+
+package kitev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IssueService_ListIssues_FullMethodName           = "/kite.v1.IssueService/ListIssues"
+	IssueService_GetIssue_FullMethodName             = "/kite.v1.IssueService/GetIssue"
+	IssueService_CreateIssue_FullMethodName          = "/kite.v1.IssueService/CreateIssue"
+	IssueService_UpdateIssue_FullMethodName          = "/kite.v1.IssueService/UpdateIssue"
+	IssueService_ResolveIssue_FullMethodName         = "/kite.v1.IssueService/ResolveIssue"
+	IssueService_ResolveIssuesByScope_FullMethodName = "/kite.v1.IssueService/ResolveIssuesByScope"
+	IssueService_AddRelatedIssue_FullMethodName      = "/kite.v1.IssueService/AddRelatedIssue"
+	IssueService_RemoveRelatedIssue_FullMethodName   = "/kite.v1.IssueService/RemoveRelatedIssue"
+)
+
+// IssueServiceClient is the client API for IssueService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IssueService manages issues, the same as the /api/v1/issues REST endpoints.
+//
+// Every request is made in a namespace, which the caller must be able to access.
+// Issues in other namespaces are reported as permission denied.
+type IssueServiceClient interface {
+	// ListIssues streams every issue matching the filters, most recently detected first.
+	ListIssues(ctx context.Context, in *ListIssuesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListIssuesResponse], error)
+	// GetIssue gets an issue by ID.
+	GetIssue(ctx context.Context, in *GetIssueRequest, opts ...grpc.CallOption) (*GetIssueResponse, error)
+	// CreateIssue creates an issue, or updates the active issue with the same scope.
+	CreateIssue(ctx context.Context, in *CreateIssueRequest, opts ...grpc.CallOption) (*CreateIssueResponse, error)
+	// UpdateIssue changes the fields set in the request, the others are left as they are.
+	UpdateIssue(ctx context.Context, in *UpdateIssueRequest, opts ...grpc.CallOption) (*UpdateIssueResponse, error)
+	// ResolveIssue marks an issue as resolved.
+	ResolveIssue(ctx context.Context, in *ResolveIssueRequest, opts ...grpc.CallOption) (*ResolveIssueResponse, error)
+	// ResolveIssuesByScope resolves every active issue for a resource, such as a pipeline run that now succeeds.
+	ResolveIssuesByScope(ctx context.Context, in *ResolveIssuesByScopeRequest, opts ...grpc.CallOption) (*ResolveIssuesByScopeResponse, error)
+	// AddRelatedIssue relates two issues.
+	AddRelatedIssue(ctx context.Context, in *AddRelatedIssueRequest, opts ...grpc.CallOption) (*AddRelatedIssueResponse, error)
+	// RemoveRelatedIssue removes the relationship between two issues.
+	RemoveRelatedIssue(ctx context.Context, in *RemoveRelatedIssueRequest, opts ...grpc.CallOption) (*RemoveRelatedIssueResponse, error)
+}
+
+type issueServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIssueServiceClient(cc grpc.ClientConnInterface) IssueServiceClient {
+	return &issueServiceClient{cc}
+}
+
+func (c *issueServiceClient) ListIssues(ctx context.Context, in *ListIssuesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ListIssuesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IssueService_ServiceDesc.Streams[0], IssueService_ListIssues_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListIssuesRequest, ListIssuesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IssueService_ListIssuesClient = grpc.ServerStreamingClient[ListIssuesResponse]
+
+func (c *issueServiceClient) GetIssue(ctx context.Context, in *GetIssueRequest, opts ...grpc.CallOption) (*GetIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIssueResponse)
+	err := c.cc.Invoke(ctx, IssueService_GetIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *issueServiceClient) CreateIssue(ctx context.Context, in *CreateIssueRequest, opts ...grpc.CallOption) (*CreateIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateIssueResponse)
+	err := c.cc.Invoke(ctx, IssueService_CreateIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *issueServiceClient) UpdateIssue(ctx context.Context, in *UpdateIssueRequest, opts ...grpc.CallOption) (*UpdateIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateIssueResponse)
+	err := c.cc.Invoke(ctx, IssueService_UpdateIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *issueServiceClient) ResolveIssue(ctx context.Context, in *ResolveIssueRequest, opts ...grpc.CallOption) (*ResolveIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveIssueResponse)
+	err := c.cc.Invoke(ctx, IssueService_ResolveIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *issueServiceClient) ResolveIssuesByScope(ctx context.Context, in *ResolveIssuesByScopeRequest, opts ...grpc.CallOption) (*ResolveIssuesByScopeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveIssuesByScopeResponse)
+	err := c.cc.Invoke(ctx, IssueService_ResolveIssuesByScope_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *issueServiceClient) AddRelatedIssue(ctx context.Context, in *AddRelatedIssueRequest, opts ...grpc.CallOption) (*AddRelatedIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddRelatedIssueResponse)
+	err := c.cc.Invoke(ctx, IssueService_AddRelatedIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *issueServiceClient) RemoveRelatedIssue(ctx context.Context, in *RemoveRelatedIssueRequest, opts ...grpc.CallOption) (*RemoveRelatedIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveRelatedIssueResponse)
+	err := c.cc.Invoke(ctx, IssueService_RemoveRelatedIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IssueServiceServer is the server API for IssueService service.
+// All implementations must embed UnimplementedIssueServiceServer
+// for forward compatibility.
+//
+// IssueService manages issues, the same as the /api/v1/issues REST endpoints.
+//
+// Every request is made in a namespace, which the caller must be able to access.
+// Issues in other namespaces are reported as permission denied.
+type IssueServiceServer interface {
+	// ListIssues streams every issue matching the filters, most recently detected first.
+	ListIssues(*ListIssuesRequest, grpc.ServerStreamingServer[ListIssuesResponse]) error
+	// GetIssue gets an issue by ID.
+	GetIssue(context.Context, *GetIssueRequest) (*GetIssueResponse, error)
+	// CreateIssue creates an issue, or updates the active issue with the same scope.
+	CreateIssue(context.Context, *CreateIssueRequest) (*CreateIssueResponse, error)
+	// UpdateIssue changes the fields set in the request, the others are left as they are.
+	UpdateIssue(context.Context, *UpdateIssueRequest) (*UpdateIssueResponse, error)
+	// ResolveIssue marks an issue as resolved.
+	ResolveIssue(context.Context, *ResolveIssueRequest) (*ResolveIssueResponse, error)
+	// ResolveIssuesByScope resolves every active issue for a resource, such as a pipeline run that now succeeds.
+	ResolveIssuesByScope(context.Context, *ResolveIssuesByScopeRequest) (*ResolveIssuesByScopeResponse, error)
+	// AddRelatedIssue relates two issues.
+	AddRelatedIssue(context.Context, *AddRelatedIssueRequest) (*AddRelatedIssueResponse, error)
+	// RemoveRelatedIssue removes the relationship between two issues.
+	RemoveRelatedIssue(context.Context, *RemoveRelatedIssueRequest) (*RemoveRelatedIssueResponse, error)
+	mustEmbedUnimplementedIssueServiceServer()
+}
+
+// UnimplementedIssueServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIssueServiceServer struct{}
+
+func (UnimplementedIssueServiceServer) ListIssues(*ListIssuesRequest, grpc.ServerStreamingServer[ListIssuesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ListIssues not implemented")
+}
+func (UnimplementedIssueServiceServer) GetIssue(context.Context, *GetIssueRequest) (*GetIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssue not implemented")
+}
+func (UnimplementedIssueServiceServer) CreateIssue(context.Context, *CreateIssueRequest) (*CreateIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIssue not implemented")
+}
+func (UnimplementedIssueServiceServer) UpdateIssue(context.Context, *UpdateIssueRequest) (*UpdateIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateIssue not implemented")
+}
+func (UnimplementedIssueServiceServer) ResolveIssue(context.Context, *ResolveIssueRequest) (*ResolveIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveIssue not implemented")
+}
+func (UnimplementedIssueServiceServer) ResolveIssuesByScope(context.Context, *ResolveIssuesByScopeRequest) (*ResolveIssuesByScopeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveIssuesByScope not implemented")
+}
+func (UnimplementedIssueServiceServer) AddRelatedIssue(context.Context, *AddRelatedIssueRequest) (*AddRelatedIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRelatedIssue not implemented")
+}
+func (UnimplementedIssueServiceServer) RemoveRelatedIssue(context.Context, *RemoveRelatedIssueRequest) (*RemoveRelatedIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRelatedIssue not implemented")
+}
+func (UnimplementedIssueServiceServer) mustEmbedUnimplementedIssueServiceServer() {}
+func (UnimplementedIssueServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeIssueServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IssueServiceServer will
+// result in compilation errors.
+type UnsafeIssueServiceServer interface {
+	mustEmbedUnimplementedIssueServiceServer()
+}
+
+func RegisterIssueServiceServer(s grpc.ServiceRegistrar, srv IssueServiceServer) {
+	// If the following call pancis, it indicates UnimplementedIssueServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IssueService_ServiceDesc, srv)
+}
+
+func _IssueService_ListIssues_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListIssuesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IssueServiceServer).ListIssues(m, &grpc.GenericServerStream[ListIssuesRequest, ListIssuesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IssueService_ListIssuesServer = grpc.ServerStreamingServer[ListIssuesResponse]
+
+func _IssueService_GetIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IssueServiceServer).GetIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IssueService_GetIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IssueServiceServer).GetIssue(ctx, req.(*GetIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IssueService_CreateIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IssueServiceServer).CreateIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IssueService_CreateIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IssueServiceServer).CreateIssue(ctx, req.(*CreateIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IssueService_UpdateIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IssueServiceServer).UpdateIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IssueService_UpdateIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IssueServiceServer).UpdateIssue(ctx, req.(*UpdateIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IssueService_ResolveIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IssueServiceServer).ResolveIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IssueService_ResolveIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IssueServiceServer).ResolveIssue(ctx, req.(*ResolveIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IssueService_ResolveIssuesByScope_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveIssuesByScopeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IssueServiceServer).ResolveIssuesByScope(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IssueService_ResolveIssuesByScope_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IssueServiceServer).ResolveIssuesByScope(ctx, req.(*ResolveIssuesByScopeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IssueService_AddRelatedIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRelatedIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IssueServiceServer).AddRelatedIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IssueService_AddRelatedIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IssueServiceServer).AddRelatedIssue(ctx, req.(*AddRelatedIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IssueService_RemoveRelatedIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRelatedIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IssueServiceServer).RemoveRelatedIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IssueService_RemoveRelatedIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IssueServiceServer).RemoveRelatedIssue(ctx, req.(*RemoveRelatedIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IssueService_ServiceDesc is the grpc.ServiceDesc for IssueService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IssueService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kite.v1.IssueService",
+	HandlerType: (*IssueServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetIssue",
+			Handler:    _IssueService_GetIssue_Handler,
+		},
+		{
+			MethodName: "CreateIssue",
+			Handler:    _IssueService_CreateIssue_Handler,
+		},
+		{
+			MethodName: "UpdateIssue",
+			Handler:    _IssueService_UpdateIssue_Handler,
+		},
+		{
+			MethodName: "ResolveIssue",
+			Handler:    _IssueService_ResolveIssue_Handler,
+		},
+		{
+			MethodName: "ResolveIssuesByScope",
+			Handler:    _IssueService_ResolveIssuesByScope_Handler,
+		},
+		{
+			MethodName: "AddRelatedIssue",
+			Handler:    _IssueService_AddRelatedIssue_Handler,
+		},
+		{
+			MethodName: "RemoveRelatedIssue",
+			Handler:    _IssueService_RemoveRelatedIssue_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListIssues",
+			Handler:       _IssueService_ListIssues_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "kite/v1/issues.proto",
+}
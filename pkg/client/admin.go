@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/dto"
+)
+
+// ReliabilityOptions limits reliability metrics to some scopes and a window
+type ReliabilityOptions struct {
+	ResourceType string
+	ResourceName string
+	// RFC 3339 times, dates or relative times like now-7d, the server's default window when empty
+	Since string
+	Until string
+}
+
+// GetReliability returns reliability metrics for a namespace and each scope in it
+func (c *Client) GetReliability(ctx context.Context, namespace string, opts ReliabilityOptions) (*dto.ReliabilityResponse, error) {
+	query := namespaceQuery(namespace)
+	for name, value := range map[string]string{
+		"resourceType": opts.ResourceType,
+		"resourceName": opts.ResourceName,
+		"since":        opts.Since,
+		"until":        opts.Until,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	var metrics dto.ReliabilityResponse
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/api/v1/metrics/reliability", query: query}, &metrics); err != nil {
+		return nil, err
+	}
+	return &metrics, nil
+}
+
+// ImportIssues creates issues in a namespace from a csv or ndjson file, such as one from ExportIssues.
+// When some rows are invalid nothing is imported, and the report is returned along with an *Error with status 422.
+func (c *Client) ImportIssues(ctx context.Context, namespace, format string, file io.Reader, dryRun bool) (*dto.ImportReport, error) {
+	query := url.Values{
+		"namespace": {namespace},
+		"format":    {format},
+		"dryRun":    {strconv.FormatBool(dryRun)},
+	}
+
+	contentType := "application/x-ndjson"
+	if format == "csv" {
+		contentType = "text/csv"
+	}
+
+	resp, err := c.send(ctx, &request{
+		method:      http.MethodPost,
+		path:        "/api/v1/admin/import",
+		query:       query,
+		rawBody:     file,
+		contentType: contentType,
+	})
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		// The report of each row is sent back instead of an error message
+		var report dto.ImportReport
+		if err := json.Unmarshal(apiErr.body, &report); err != nil {
+			return nil, fmt.Errorf("failed to decode import report: %w", err)
+		}
+		return &report, apiErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var report dto.ImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &report, nil
+}
+
+// Health is the status of the service
+type Health struct {
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GetHealth checks the service is healthy
+func (c *Client) GetHealth(ctx context.Context) (*Health, error) {
+	var health Health
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/health"}, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Version describes the API
+type Version struct {
+	Version     string `json:"version"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GetVersion returns the API version
+func (c *Client) GetVersion(ctx context.Context) (*Version, error) {
+	var version Version
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/version"}, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
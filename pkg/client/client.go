@@ -0,0 +1,314 @@
+// Package client is a Go client for the Konflux Issues API.
+//
+// Every method takes the namespace the call is made in, since the API checks the caller's access to it.
+// Requests that are safe to repeat are retried when the server is unavailable or rate limiting.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/dto"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultRetryWait  = 500 * time.Millisecond
+	maxRetryWait      = 30 * time.Second
+)
+
+// Client calls the API at a base URL, such as https://kite.example.com
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	tokenSource func(ctx context.Context) (string, error)
+	userAgent   string
+	maxRetries  int
+	retryWait   time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with, http.DefaultClient by default
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sends token as a bearer token with every request
+func WithToken(token string) Option {
+	return WithTokenSource(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// WithTokenSource sends the token returned by source as a bearer token, it's called before every request
+// so it can refresh tokens that expire
+func WithTokenSource(source func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) {
+		c.tokenSource = source
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetries sets how many times a request is retried, and the wait before the first retry which doubles each time.
+// Zero retries disables them.
+func WithRetries(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryWait = wait
+	}
+}
+
+// New returns a client for the API at baseURL
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme and host are required", baseURL)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: http.DefaultClient,
+		userAgent:  "kite-go-client",
+		maxRetries: defaultMaxRetries,
+		retryWait:  defaultRetryWait,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Error is an error response from the API. Errors are RFC 7807 problems, Code tells them apart
+// and Errors has the invalid fields of requests that failed validation.
+type Error struct {
+	StatusCode int
+	dto.Problem
+
+	// Some errors respond with something other than a problem, such as an import report
+	body []byte
+}
+
+func (e *Error) Error() string {
+	title := e.Title
+	if title == "" {
+		title = http.StatusText(e.StatusCode)
+	}
+	if e.Detail != "" {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, title, e.Detail)
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, title)
+}
+
+// IsNotFound reports whether err is an API error with status 404
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsPreconditionFailed reports whether err is an API error with status 412,
+// returned when the version passed is no longer the issue's current version
+func IsPreconditionFailed(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusPreconditionFailed
+}
+
+// request is an API call for send
+type request struct {
+	method string
+	// Path after the base URL, with segments escaped
+	path   string
+	query  url.Values
+	header http.Header
+	// JSON encoded as the request body when set
+	body any
+	// Sent as is instead of body, such as an import file. Requests with a raw body aren't retried.
+	rawBody     io.Reader
+	contentType string
+}
+
+// Helper function to send a request and decode a JSON response into out, which may be nil
+func (c *Client) do(ctx context.Context, req *request, out any) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Helper function to send a request, retrying it when allowed. Responses with an error status
+// are returned as *Error, otherwise the caller has to close the response body.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	var body []byte
+	if req.body != nil {
+		encoded, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = encoded
+	}
+
+	retries := 0
+	if req.rawBody == nil && isIdempotent(req.method) {
+		retries = c.maxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		httpReq, err := c.newRequest(ctx, req, body)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+
+		if err != nil {
+			if ctx.Err() != nil || attempt >= retries {
+				return nil, err
+			}
+		} else {
+			apiErr := responseError(resp)
+			if attempt >= retries || !isRetryable(resp.StatusCode) {
+				return nil, apiErr
+			}
+		}
+
+		wait := c.retryDelay(attempt, resp)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Helper function to build the HTTP request for an attempt
+func (c *Client) newRequest(ctx context.Context, req *request, body []byte) (*http.Request, error) {
+	// Paths are built with escaped segments
+	u, err := url.Parse(c.baseURL.String() + req.path)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = req.query.Encode()
+
+	var reader io.Reader
+	contentType := req.contentType
+	switch {
+	case req.rawBody != nil:
+		reader = req.rawBody
+	case body != nil:
+		reader = bytes.NewReader(body)
+		if contentType == "" {
+			contentType = "application/json"
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if httpReq.Header.Get("Accept") == "" {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+	httpReq.Header.Set("User-Agent", c.userAgent)
+
+	if c.tokenSource != nil {
+		token, err := c.tokenSource(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	return httpReq, nil
+}
+
+// Helper function to work out how long to wait before retrying, honouring Retry-After when the server sent it
+func (c *Client) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, maxRetryWait)
+		}
+	}
+	return min(c.retryWait<<attempt, maxRetryWait)
+}
+
+// Helper function to read an error response, closing its body
+func responseError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	apiErr.body, _ = io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	// Not every error has a JSON body, such as those from proxies in front of the API
+	_ = json.Unmarshal(apiErr.body, apiErr)
+	apiErr.StatusCode = resp.StatusCode
+	return apiErr
+}
+
+// Helper function to check whether repeating a request has the same effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Helper function to check whether a status means the request may succeed if it's sent again
+func isRetryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Helper function to set the If-Match header for a version, 0 for an unconditional request
+func ifMatch(version int64) http.Header {
+	if version == 0 {
+		return nil
+	}
+	return http.Header{"If-Match": {strconv.Quote(strconv.FormatInt(version, 10))}}
+}
+
+// Helper function to build the query of a call in a namespace
+func namespaceQuery(namespace string) url.Values {
+	return url.Values{"namespace": {namespace}}
+}
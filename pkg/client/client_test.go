@@ -0,0 +1,767 @@
+package client
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/konflux-ci/kite/internal/config"
+	handlers "github.com/konflux-ci/kite/internal/handlers/http"
+	"github.com/konflux-ci/kite/internal/middleware"
+	"github.com/konflux-ci/kite/internal/repository"
+	"github.com/konflux-ci/kite/internal/services"
+	"github.com/konflux-ci/kite/internal/tracker"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// memoryIssues keeps issues in memory in place of the database, following the issue repository's rules
+// for duplicates, versions and pagination. Methods the client's routes don't reach aren't implemented.
+type memoryIssues struct {
+	repository.IssueRepository
+	mu        sync.Mutex
+	issues    map[string]*models.Issue
+	relations []models.RelatedIssue
+}
+
+func newMemoryIssues() *memoryIssues {
+	return &memoryIssues{issues: map[string]*models.Issue{}}
+}
+
+// Helper function to add an issue as it would be stored, returning its ID
+func (r *memoryIssues) add(issue models.Issue) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if issue.ID == "" {
+		issue.ID = uuid.NewString()
+	}
+	if issue.DetectedAt.IsZero() {
+		issue.DetectedAt = time.Now()
+	}
+	issue.State = cmp.Or(issue.State, models.IssueStateActive)
+	issue.Version = max(issue.Version, 1)
+	if issue.Labels == nil {
+		issue.Labels = []string{}
+	}
+	if issue.Scope.ResourceNamespace == "" {
+		issue.Scope.ResourceNamespace = issue.Namespace
+	}
+	issue.Scope.ID = uuid.NewString()
+	issue.ScopeID = issue.Scope.ID
+	for i := range issue.Links {
+		issue.Links[i].ID = uuid.NewString()
+		issue.Links[i].IssueID = issue.ID
+	}
+	r.issues[issue.ID] = &issue
+	return issue.ID
+}
+
+// Helper function to copy an issue with its relationships, the caller holds the lock
+func (r *memoryIssues) load(id string) *models.Issue {
+	stored, ok := r.issues[id]
+	if !ok {
+		return nil
+	}
+	issue := *stored
+	issue.Links = slices.Clone(stored.Links)
+	issue.RelatedFrom, issue.RelatedTo = nil, nil
+	for _, relation := range r.relations {
+		if relation.SourceID == id {
+			issue.RelatedFrom = append(issue.RelatedFrom, relation)
+		}
+		if relation.TargetID == id {
+			issue.RelatedTo = append(issue.RelatedTo, relation)
+		}
+	}
+	return &issue
+}
+
+// Helper function to find an issue to change at a version, the caller holds the lock
+func (r *memoryIssues) find(id string, version int64) (*models.Issue, error) {
+	issue, ok := r.issues[id]
+	if !ok {
+		return nil, repository.ErrIssueNotFound
+	}
+	if version != 0 && issue.Version != version {
+		return nil, repository.ErrVersionMismatch
+	}
+	return issue, nil
+}
+
+// Helper function to list the issues matching filters in the default order, newest first
+func (r *memoryIssues) matching(filters repository.IssueQueryFilters) []models.Issue {
+	var found []models.Issue
+	for id, issue := range r.issues {
+		if filters.Matches(issue) {
+			found = append(found, *r.load(id))
+		}
+	}
+	slices.SortFunc(found, func(a, b models.Issue) int {
+		return cmp.Or(b.DetectedAt.Compare(a.DetectedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return found
+}
+
+func (r *memoryIssues) CheckDuplicate(_ context.Context, req dto.CreateIssueRequest) (*repository.DuplicateCheckResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, issue := range r.issues {
+		if issue.Namespace == req.Namespace && issue.IssueType == req.IssueType && issue.State == models.IssueStateActive &&
+			issue.Scope.ResourceType == req.Scope.ResourceType && issue.Scope.ResourceName == req.Scope.ResourceName {
+			return &repository.DuplicateCheckResult{IsDuplicate: true, ExistingIssue: r.load(id)}, nil
+		}
+	}
+	return &repository.DuplicateCheckResult{}, nil
+}
+
+func (r *memoryIssues) Create(ctx context.Context, req dto.CreateIssueRequest) (*models.Issue, error) {
+	duplicate, err := r.CheckDuplicate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate.IsDuplicate {
+		return r.Update(ctx, duplicate.ExistingIssue.ID, dto.UpdateIssueRequest{
+			Title:       &req.Title,
+			Description: &req.Description,
+			Severity:    &req.Severity,
+			IssueType:   &req.IssueType,
+		}, 0)
+	}
+
+	issue := models.Issue{
+		Title:       req.Title,
+		Description: req.Description,
+		Severity:    req.Severity,
+		IssueType:   req.IssueType,
+		State:       req.State,
+		Namespace:   req.Namespace,
+		Scope: models.IssueScope{
+			ResourceType:      req.Scope.ResourceType,
+			ResourceName:      req.Scope.ResourceName,
+			ResourceNamespace: req.Scope.ResourceNamespace,
+		},
+	}
+	for _, link := range req.Links {
+		issue.Links = append(issue.Links, models.Link{Title: link.Title, URL: link.URL})
+	}
+	return r.FindByID(ctx, r.add(issue))
+}
+
+func (r *memoryIssues) FindByID(_ context.Context, id string) (*models.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load(id), nil
+}
+
+func (r *memoryIssues) Update(ctx context.Context, id string, req dto.UpdateIssueRequest, version int64) (*models.Issue, error) {
+	r.mu.Lock()
+	issue, err := r.find(id, version)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	issue.Title = *cmp.Or(req.Title, &issue.Title)
+	issue.Description = *cmp.Or(req.Description, &issue.Description)
+	issue.Severity = *cmp.Or(req.Severity, &issue.Severity)
+	issue.IssueType = *cmp.Or(req.IssueType, &issue.IssueType)
+	if req.State != nil {
+		if *req.State == models.IssueStateResolved && issue.State != models.IssueStateResolved {
+			now := time.Now()
+			issue.ResolvedAt = &now
+		}
+		issue.State = *req.State
+	}
+	issue.ResolvedAt = cmp.Or(req.ResolvedAt, issue.ResolvedAt)
+	issue.Version++
+	r.mu.Unlock()
+	return r.FindByID(ctx, id)
+}
+
+func (r *memoryIssues) Replace(ctx context.Context, id string, req dto.ReplaceIssueRequest, version int64) (*models.Issue, error) {
+	r.mu.Lock()
+	issue, err := r.find(id, version)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	issue.Title, issue.Description = req.Title, req.Description
+	issue.Severity, issue.IssueType, issue.State = req.Severity, req.IssueType, req.State
+	issue.ResolvedAt, issue.Assignee = req.ResolvedAt, req.Assignee
+	issue.Labels = append([]string{}, req.Labels...)
+	issue.Links = nil
+	for _, link := range req.Links {
+		issue.Links = append(issue.Links, models.Link{ID: cmp.Or(link.ID, uuid.NewString()), Title: link.Title, URL: link.URL, IssueID: id})
+	}
+	issue.Version++
+	r.mu.Unlock()
+	return r.FindByID(ctx, id)
+}
+
+func (r *memoryIssues) Delete(_ context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.find(id, version); err != nil {
+		return err
+	}
+	delete(r.issues, id)
+	r.relations = slices.DeleteFunc(r.relations, func(relation models.RelatedIssue) bool {
+		return relation.SourceID == id || relation.TargetID == id
+	})
+	return nil
+}
+
+func (r *memoryIssues) FindAll(_ context.Context, filters repository.IssueQueryFilters) (*repository.IssuePage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if filters.Cursor != nil && len(filters.Sort) > 0 {
+		return nil, repository.ErrCursorWithSort
+	}
+
+	// Sorts other than the default are left out, pages still follow one order without cursors
+	found := r.matching(filters)
+	page := &repository.IssuePage{Total: int64(len(found))}
+	start := min(filters.Offset, len(found))
+	if filters.Cursor != nil {
+		start = slices.IndexFunc(found, func(issue models.Issue) bool {
+			return issue.DetectedAt.Before(filters.Cursor.DetectedAt) ||
+				(issue.DetectedAt.Equal(filters.Cursor.DetectedAt) && issue.ID < filters.Cursor.ID)
+		})
+		if start < 0 {
+			start = len(found)
+		}
+	}
+	end := min(start+cmp.Or(filters.Limit, 50), len(found))
+	page.Issues = found[start:end]
+	if end < len(found) && len(page.Issues) > 0 && len(filters.Sort) == 0 {
+		last := page.Issues[len(page.Issues)-1]
+		page.NextCursor = repository.Cursor{DetectedAt: last.DetectedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+func (r *memoryIssues) AddRelatedIssue(_ context.Context, sourceID, targetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.issues[sourceID] == nil || r.issues[targetID] == nil {
+		return repository.ErrRelatedIssueNotFound
+	}
+	for _, relation := range r.relations {
+		if (relation.SourceID == sourceID && relation.TargetID == targetID) || (relation.SourceID == targetID && relation.TargetID == sourceID) {
+			return repository.ErrRelationshipExists
+		}
+	}
+	r.relations = append(r.relations, models.RelatedIssue{ID: uuid.NewString(), SourceID: sourceID, TargetID: targetID})
+	return nil
+}
+
+func (r *memoryIssues) RemoveRelatedIssue(_ context.Context, sourceID, targetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := len(r.relations)
+	r.relations = slices.DeleteFunc(r.relations, func(relation models.RelatedIssue) bool {
+		return (relation.SourceID == sourceID && relation.TargetID == targetID) || (relation.SourceID == targetID && relation.TargetID == sourceID)
+	})
+	if len(r.relations) == count {
+		return repository.ErrRelationshipNotFound
+	}
+	return nil
+}
+
+func (r *memoryIssues) ResolveByScope(_ context.Context, resourceType, resourceName, namespace string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	now := time.Now()
+	for _, issue := range r.issues {
+		if issue.State == models.IssueStateActive && issue.Namespace == namespace &&
+			issue.Scope.ResourceType == resourceType && issue.Scope.ResourceName == resourceName {
+			issue.State, issue.ResolvedAt = models.IssueStateResolved, &now
+			issue.Version++
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetStats only groups by severity
+func (r *memoryIssues) GetStats(_ context.Context, filters repository.IssueQueryFilters, options repository.IssueStatsOptions) (*repository.IssueStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := r.matching(filters)
+	stats := &repository.IssueStats{Total: int64(len(found))}
+	if !slices.Equal(options.GroupBy, []string{"severity"}) {
+		return stats, nil
+	}
+	counts := map[models.Severity]int64{}
+	for _, issue := range found {
+		counts[issue.Severity]++
+	}
+	for _, severity := range slices.Sorted(func(yield func(models.Severity) bool) {
+		for severity := range counts {
+			if !yield(severity) {
+				return
+			}
+		}
+	}) {
+		key := string(severity)
+		stats.Groups = append(stats.Groups, repository.IssueStatsGroup{Key: map[string]*string{"severity": &key}, Count: counts[severity]})
+	}
+	return stats, nil
+}
+
+func (r *memoryIssues) StreamAll(_ context.Context, filters repository.IssueQueryFilters, fn func(issue *models.Issue) error) error {
+	r.mu.Lock()
+	found := r.matching(filters)
+	r.mu.Unlock()
+	for i := range found {
+		if err := fn(&found[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryIssues) Import(ctx context.Context, reqs []dto.ImportIssueRequest) ([]models.Issue, error) {
+	var created []models.Issue
+	for _, req := range reqs {
+		issue, err := r.Create(ctx, req.CreateIssueRequest)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, *issue)
+	}
+	return created, nil
+}
+
+// BulkUpdate only resolves and deletes issues
+func (r *memoryIssues) BulkUpdate(_ context.Context, ids []string, action repository.BulkAction) (map[string]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := map[string]bool{}
+	now := time.Now()
+	for _, id := range ids {
+		issue, ok := r.issues[id]
+		if !ok {
+			continue
+		}
+		switch action.Type {
+		case repository.BulkActionResolve:
+			changed[id] = issue.State != models.IssueStateResolved
+			if changed[id] {
+				issue.State, issue.ResolvedAt = models.IssueStateResolved, &now
+				issue.Version++
+			}
+		case repository.BulkActionDelete:
+			delete(r.issues, id)
+			changed[id] = true
+		default:
+			return nil, errors.New("bulk action not implemented in memory: " + string(action.Type))
+		}
+	}
+	return changed, nil
+}
+
+// memoryLinks is the link repository of the issues in memory
+type memoryLinks struct {
+	repository.LinkRepository
+	issues *memoryIssues
+}
+
+func (l memoryLinks) FindByIssueID(_ context.Context, issueID string) ([]models.Link, error) {
+	l.issues.mu.Lock()
+	defer l.issues.mu.Unlock()
+	if issue, ok := l.issues.issues[issueID]; ok {
+		return slices.Clone(issue.Links), nil
+	}
+	return nil, nil
+}
+
+func (l memoryLinks) CreateBatch(_ context.Context, issueID string, links []models.Link) error {
+	l.issues.mu.Lock()
+	defer l.issues.mu.Unlock()
+	issue := l.issues.issues[issueID]
+	for _, link := range links {
+		link.ID, link.IssueID = uuid.NewString(), issueID
+		issue.Links = append(issue.Links, link)
+	}
+	return nil
+}
+
+// Helper function to serve the Jira API as far as creating tickets, numbering them from KITE-1
+func fakeJira(t *testing.T) *httptest.Server {
+	var created int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/2/issue" {
+			http.NotFound(w, r)
+			return
+		}
+		created++
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"key": fmt.Sprintf("KITE-%d", created)})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testServer is the real router serving issues from memory
+type testServer struct {
+	*httptest.Server
+	issues *memoryIssues
+	jira   *httptest.Server
+}
+
+// Helper function to serve the router with issues kept in memory and tickets exported to a fake Jira.
+// Nothing listens on the database, so routes that don't go through the issue service fail.
+// With a checker the namespaces each token can access are checked, otherwise every caller can access every namespace.
+func newTestServer(t *testing.T, checker *middleware.NamespaceChecker) *testServer {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("FEATURE_NAMESPACE_CHECKING", "false")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 user=x dbname=x"}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := testLogger()
+
+	shared, err := handlers.NewServices(db, cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &testServer{issues: newMemoryIssues(), jira: fakeJira(t)}
+	shared.Issues = services.NewIssueService(server.issues, logger)
+	shared.Trackers = services.NewTrackerService(server.issues, memoryLinks{issues: server.issues}, logger)
+	shared.Trackers.Register(tracker.NewJiraClient(config.JiraConfig{BaseURL: server.jira.URL, ProjectKey: "KITE", IssueType: "Bug"}), "")
+	shared.NamespaceChecker = checker
+
+	router, err := handlers.SetupRouter(db, cfg, shared, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Server = httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Helper function to create a client of a test server, without retries unless they're asked for
+func (s *testServer) client(t *testing.T, opts ...Option) *Client {
+	c, err := New(s.URL, append([]Option{WithRetries(0, 0)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// Helper function to check err is an API error with a status and code
+func checkAPIError(t *testing.T, err error, status int, code string) *Error {
+	t.Helper()
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want an API error with status %d", err, status)
+	}
+	if apiErr.StatusCode != status || apiErr.Code != code {
+		t.Errorf("error = %d %s (%v), want %d %s", apiErr.StatusCode, apiErr.Code, apiErr, status, code)
+	}
+	return apiErr
+}
+
+// namespaceTokens authenticates tokens by name, each able to access a list of namespaces
+type namespaceTokens map[string][]string
+
+func (n namespaceTokens) AuthenticateToken(_ context.Context, token string) (*middleware.Principal, error) {
+	if _, ok := n[token]; !ok {
+		return nil, middleware.ErrUnauthenticated
+	}
+	return &middleware.Principal{Name: token, AuthMethod: "test", Authorizer: namespaceAuthorizer(n[token])}, nil
+}
+
+type namespaceAuthorizer []string
+
+func (a namespaceAuthorizer) Allowed(_ context.Context, namespace string, _ middleware.Operation) (bool, error) {
+	return slices.Contains(a, namespace), nil
+}
+
+func TestGetVersionAndHealth(t *testing.T) {
+	c := newTestServer(t, nil).client(t)
+	ctx := context.Background()
+
+	version, err := c.GetVersion(ctx)
+	if err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+	if version.Version != "1.0.0" || version.Name == "" {
+		t.Errorf("GetVersion() = %+v, want the API version", version)
+	}
+
+	health, err := c.GetHealth(ctx)
+	if err != nil {
+		t.Fatalf("GetHealth() error = %v", err)
+	}
+	if health.Status != "UP" || health.Timestamp.IsZero() {
+		t.Errorf("GetHealth() = %+v, want the service up", health)
+	}
+}
+
+func TestErrorDecoding(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ctx := context.Background()
+
+	_, err := c.GetIssue(ctx, "team-a", uuid.NewString())
+	apiErr := checkAPIError(t, err, http.StatusNotFound, dto.CodeNotFound)
+	if !IsNotFound(err) || apiErr.Title == "" || apiErr.Detail == "" {
+		t.Errorf("GetIssue() error = %+v, want a not found problem with a title and detail", apiErr)
+	}
+
+	// Routes that aren't registered respond with problems too
+	unknown, err := New(server.URL+"/nothing", WithRetries(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = unknown.GetVersion(ctx)
+	checkAPIError(t, err, http.StatusNotFound, dto.CodeNotFound)
+
+	// Saved views and metrics are read from the database, which isn't reachable
+	_, err = c.ListViews(ctx, "team-a")
+	checkAPIError(t, err, http.StatusInternalServerError, dto.CodeInternal)
+	_, err = c.GetReliability(ctx, "team-a", ReliabilityOptions{})
+	checkAPIError(t, err, http.StatusInternalServerError, dto.CodeInternal)
+}
+
+func TestAuthorization(t *testing.T) {
+	checker := middleware.NewNamespaceCheckerWith(testLogger(), namespaceTokens{"team-a-token": {"team-a"}})
+	server := newTestServer(t, checker)
+	issueID := server.issues.add(models.Issue{Title: "Build failed", Namespace: "team-a", Severity: models.SeverityMajor, IssueType: models.IssueTypeBuild})
+	ctx := context.Background()
+
+	_, err := server.client(t).GetIssue(ctx, "team-a", issueID)
+	checkAPIError(t, err, http.StatusUnauthorized, dto.CodeUnauthorized)
+
+	_, err = server.client(t, WithToken("unknown")).GetIssue(ctx, "team-a", issueID)
+	checkAPIError(t, err, http.StatusUnauthorized, dto.CodeUnauthorized)
+
+	c := server.client(t, WithToken("team-a-token"))
+	if _, err := c.GetIssue(ctx, "team-a", issueID); err != nil {
+		t.Errorf("GetIssue() error = %v with a token for the namespace", err)
+	}
+	_, err = c.ListIssues(ctx, IssueFilters{Namespace: "team-b"}, ListOptions{})
+	checkAPIError(t, err, http.StatusForbidden, dto.CodeForbidden)
+
+	// Tokens from a source are fetched for each request
+	var fetched int
+	source := WithTokenSource(func(context.Context) (string, error) {
+		fetched++
+		return "team-a-token", nil
+	})
+	c = server.client(t, source)
+	for range 2 {
+		if _, err := c.ListIssues(ctx, IssueFilters{Namespace: "team-a"}, ListOptions{}); err != nil {
+			t.Fatalf("ListIssues() error = %v", err)
+		}
+	}
+	if fetched != 2 {
+		t.Errorf("token fetched %d times, want once for each request", fetched)
+	}
+}
+
+// Helper function to send a request with a JSON body and a token, the way a client other than this one could
+func sendJSON(t *testing.T, method, url, token, contentType, body string) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestBodyNamespaceSpoofing(t *testing.T) {
+	checker := middleware.NewNamespaceCheckerWith(testLogger(), namespaceTokens{"team-a-token": {"team-a"}})
+	server := newTestServer(t, checker)
+	own := server.issues.add(models.Issue{Title: "Build failed", Namespace: "team-a", Severity: models.SeverityMajor, IssueType: models.IssueTypeBuild})
+	other := server.issues.add(models.Issue{Title: "Deploy failed", Namespace: "team-b", Severity: models.SeverityMajor, IssueType: models.IssueTypeBuild})
+	issues := server.URL + "/api/v1/issues/"
+
+	// The namespace the caller has access to is only given in the body, the issues are in another one
+	replace := `{"namespace":"team-a","title":"Spoofed","description":"Spoofed","severity":"minor","issueType":"build","state":"ACTIVE"}`
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{name: "resolve", method: http.MethodPost, url: issues + other + "/resolve", body: `{"namespace":"team-a"}`, wantStatus: http.StatusForbidden},
+		{name: "replace", method: http.MethodPut, url: issues + other, body: replace, wantStatus: http.StatusForbidden},
+		// Patch bodies aren't read for the namespace, so it's missing
+		{name: "patch", method: http.MethodPatch, url: issues + other, contentType: "application/merge-patch+json",
+			body: `{"namespace":"team-a","title":"Spoofed"}`, wantStatus: http.StatusBadRequest},
+		{name: "export", method: http.MethodPost, url: issues + other + "/export/jira", body: `{"namespace":"team-a"}`, wantStatus: http.StatusForbidden},
+		{name: "add related", method: http.MethodPost, url: issues + other + "/related",
+			body: `{"namespace":"team-a","relatedId":"` + own + `"}`, wantStatus: http.StatusNotFound},
+		{name: "add related from own issue", method: http.MethodPost, url: issues + own + "/related",
+			body: `{"namespace":"team-a","relatedId":"` + other + `"}`, wantStatus: http.StatusNotFound},
+		// Bodies naming another namespace than the one checked in the query are rejected
+		{name: "create in other namespace", method: http.MethodPost, url: issues + "?namespace=team-a",
+			body: `{"namespace":"team-b","title":"Spoofed","description":"Spoofed","severity":"minor","issueType":"build",` +
+				`"scope":{"resourceType":"component","resourceName":"frontend"}}`,
+			wantStatus: http.StatusUnprocessableEntity},
+		{name: "pipeline success in other namespace", method: http.MethodPost, url: server.URL + "/api/v1/webhooks/pipeline-success?namespace=team-a",
+			body: `{"namespace":"team-b","pipelineName":"deploy"}`, wantStatus: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := sendJSON(t, tt.method, tt.url, "team-a-token", cmp.Or(tt.contentType, "application/json"), tt.body)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+
+	server.issues.mu.Lock()
+	defer server.issues.mu.Unlock()
+	issue := server.issues.load(other)
+	if issue.Title != "Deploy failed" || issue.State != models.IssueStateActive || issue.Version != 1 || len(issue.Links) != 0 || len(issue.RelatedTo)+len(issue.RelatedFrom) != 0 {
+		t.Errorf("issue in the other namespace = %+v, want it unchanged", issue)
+	}
+	if len(server.issues.issues) != 2 {
+		t.Errorf("%d issues stored, want no issue created", len(server.issues.issues))
+	}
+}
+
+func TestRetries(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	// A proxy in front of the router that's unavailable for the first requests
+	var requests []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		if len(requests) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	c, err := New(proxy.URL, WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, err := c.GetVersion(ctx); err != nil {
+		t.Fatalf("GetVersion() error = %v after retries", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("sent %d requests, want 3", len(requests))
+	}
+
+	// Requests that aren't idempotent are only sent once, errors without a problem keep their status
+	requests = nil
+	_, err = c.CreateIssue(ctx, dto.CreateIssueRequest{Namespace: "team-a"})
+	apiErr := checkAPIError(t, err, http.StatusServiceUnavailable, "")
+	if len(requests) != 1 || apiErr.Error() != "503 Service Unavailable" {
+		t.Errorf("CreateIssue() sent %d requests and failed with %q, want one request failing with the status", len(requests), apiErr)
+	}
+}
+
+func TestPipelineWebhooks(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ctx := context.Background()
+
+	result, err := c.PipelineFailure(ctx, dto.PipelineFailureRequest{PipelineName: "build", Namespace: "team-a", FailureReason: "timeout", RunID: "run-1"})
+	if err != nil {
+		t.Fatalf("PipelineFailure() error = %v", err)
+	}
+	if result.Status != "success" || result.Issue == nil || result.Issue.Scope.ResourceName != "build" || result.Issue.State != models.IssueStateActive {
+		t.Fatalf("PipelineFailure() = %+v, want an active issue for the pipeline", result)
+	}
+
+	issueID := result.Issue.ID
+
+	result, err = c.PipelineSuccess(ctx, dto.PipelineSuccessRequest{PipelineName: "build", Namespace: "team-a"})
+	if err != nil {
+		t.Fatalf("PipelineSuccess() error = %v", err)
+	}
+	if result.Status != "success" {
+		t.Errorf("PipelineSuccess() = %+v, want success", result)
+	}
+	issue, err := c.GetIssue(ctx, "team-a", issueID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.State != models.IssueStateResolved {
+		t.Errorf("issue of the pipeline is %s, want it resolved", issue.State)
+	}
+
+	_, err = c.PipelineFailure(ctx, dto.PipelineFailureRequest{Namespace: "team-a"})
+	apiErr := checkAPIError(t, err, http.StatusUnprocessableEntity, dto.CodeValidationFailed)
+	if len(apiErr.Errors) == 0 {
+		t.Errorf("PipelineFailure() error = %+v, want the invalid fields", apiErr)
+	}
+}
+
+func TestImportIssues(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ctx := context.Background()
+
+	valid := `{"title":"Build failed","description":"Timeout","severity":"major","issueType":"build","scope":{"resourceType":"component","resourceName":"frontend"}}` + "\n"
+	invalid := `{"title":"Test failed","severity":"loud","issueType":"test","scope":{"resourceType":"component","resourceName":"backend"}}` + "\n"
+
+	report, err := c.ImportIssues(ctx, "team-a", "ndjson", strings.NewReader(valid+invalid), false)
+	apiErr := checkAPIError(t, err, http.StatusUnprocessableEntity, "")
+	if report == nil || report.Invalid != 1 || report.Created != 0 {
+		t.Fatalf("ImportIssues() = %+v, %v, want the report of the invalid row", report, apiErr)
+	}
+
+	report, err = c.ImportIssues(ctx, "team-a", "ndjson", strings.NewReader(valid), true)
+	if err != nil || report.Valid != 1 || report.Created != 0 || len(server.issues.issues) != 0 {
+		t.Fatalf("ImportIssues() = %+v, %v, want a dry run creating nothing", report, err)
+	}
+
+	report, err = c.ImportIssues(ctx, "team-a", "ndjson", strings.NewReader(valid), false)
+	if err != nil {
+		t.Fatalf("ImportIssues() error = %v", err)
+	}
+	if report.Created != 1 || report.Rows[0].IssueID == "" {
+		t.Errorf("ImportIssues() = %+v, want the issue created", report)
+	}
+
+	_, err = c.ImportIssues(ctx, "team-a", "xml", strings.NewReader(valid), false)
+	checkAPIError(t, err, http.StatusBadRequest, dto.CodeInvalidRequest)
+}
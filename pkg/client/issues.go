@@ -0,0 +1,299 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// Patch formats accepted by PatchIssue
+const (
+	PatchTypeMerge = "application/merge-patch+json"
+	PatchTypeJSON  = "application/json-patch+json"
+)
+
+// IssueFilters selects issues in a namespace, in the same way as the GET /issues query params.
+// Empty fields don't filter.
+type IssueFilters struct {
+	Namespace    string
+	Severity     models.Severity
+	IssueType    models.IssueType
+	State        models.IssueState
+	ResourceType string
+	ResourceName string
+	// Full-text search of titles, descriptions and scope names
+	Search string
+	// Structured filter, e.g. severity>=major AND detectedAt>now-7d
+	Q string
+}
+
+func (f IssueFilters) values() url.Values {
+	values := namespaceQuery(f.Namespace)
+	set := func(name, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	set("severity", string(f.Severity))
+	set("issueType", string(f.IssueType))
+	set("state", string(f.State))
+	set("resourceType", f.ResourceType)
+	set("resourceName", f.ResourceName)
+	set("search", f.Search)
+	set("q", f.Q)
+	return values
+}
+
+// ListOptions sorts and pages an issue list
+type ListOptions struct {
+	// Comma separated fields, prefixed with - for descending order
+	Sort string
+	// Comma separated fields returned for each issue, the others are left empty
+	Fields string
+	// Comma separated associations to load, nil for all of them and an empty string for none
+	Include *string
+	Limit   int
+	Offset  int
+	// Cursor from a previous page, only with the default sort
+	Cursor string
+}
+
+func (o ListOptions) apply(values url.Values) {
+	if o.Sort != "" {
+		values.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		values.Set("fields", o.Fields)
+	}
+	if o.Include != nil {
+		values.Set("include", *o.Include)
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		values.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Cursor != "" {
+		values.Set("cursor", o.Cursor)
+	}
+}
+
+// ListIssues returns a page of the issues matching filters
+func (c *Client) ListIssues(ctx context.Context, filters IssueFilters, opts ListOptions) (*dto.IssueResponse, error) {
+	query := filters.values()
+	opts.apply(query)
+
+	var page dto.IssueResponse
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/api/v1/issues/", query: query}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// AllIssues iterates over every issue matching filters, fetching pages as they're needed.
+// Pages follow the cursors returned by the API, or the offset when a sort is set.
+func (c *Client) AllIssues(ctx context.Context, filters IssueFilters, opts ListOptions) iter.Seq2[models.Issue, error] {
+	return func(yield func(models.Issue, error) bool) {
+		for {
+			page, err := c.ListIssues(ctx, filters, opts)
+			if err != nil {
+				yield(models.Issue{}, err)
+				return
+			}
+			for _, issue := range page.Data {
+				if !yield(issue, nil) {
+					return
+				}
+			}
+
+			switch {
+			case page.NextCursor != "":
+				opts.Cursor = page.NextCursor
+				opts.Offset = 0
+			case opts.Cursor == "" && len(page.Data) > 0 && int64(opts.Offset+len(page.Data)) < page.Total:
+				opts.Offset += len(page.Data)
+			default:
+				return
+			}
+		}
+	}
+}
+
+// GetIssue returns an issue by ID
+func (c *Client) GetIssue(ctx context.Context, namespace, id string) (*models.Issue, error) {
+	var issue models.Issue
+	if err := c.do(ctx, &request{method: http.MethodGet, path: issuePath(id), query: namespaceQuery(namespace)}, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// CreateIssue creates an issue in req.Namespace, or updates the active issue with the same scope
+func (c *Client) CreateIssue(ctx context.Context, req dto.CreateIssueRequest) (*models.Issue, error) {
+	var issue models.Issue
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/api/v1/issues/", query: namespaceQuery(req.Namespace), body: req}, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// ReplaceIssue replaces every editable field of an issue. When version isn't 0 the issue is only changed
+// if it's still at that version, otherwise the error satisfies IsPreconditionFailed.
+func (c *Client) ReplaceIssue(ctx context.Context, namespace, id string, req dto.ReplaceIssueRequest, version int64) (*models.Issue, error) {
+	var issue models.Issue
+	if err := c.do(ctx, &request{
+		method: http.MethodPut,
+		path:   issuePath(id),
+		query:  namespaceQuery(namespace),
+		header: ifMatch(version),
+		body:   req,
+	}, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// PatchIssue applies a JSON Merge Patch or JSON Patch, as set by patchType, to an issue.
+// version works the same way as for ReplaceIssue.
+func (c *Client) PatchIssue(ctx context.Context, namespace, id, patchType string, patch []byte, version int64) (*models.Issue, error) {
+	var issue models.Issue
+	if err := c.do(ctx, &request{
+		method:      http.MethodPatch,
+		path:        issuePath(id),
+		query:       namespaceQuery(namespace),
+		header:      ifMatch(version),
+		body:        json.RawMessage(patch),
+		contentType: patchType,
+	}, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// DeleteIssue deletes an issue, version works the same way as for ReplaceIssue
+func (c *Client) DeleteIssue(ctx context.Context, namespace, id string, version int64) error {
+	return c.do(ctx, &request{
+		method: http.MethodDelete,
+		path:   issuePath(id),
+		query:  namespaceQuery(namespace),
+		header: ifMatch(version),
+	}, nil)
+}
+
+// ResolveIssue marks an issue resolved, version works the same way as for ReplaceIssue
+func (c *Client) ResolveIssue(ctx context.Context, namespace, id string, version int64) (*models.Issue, error) {
+	var issue models.Issue
+	if err := c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   issuePath(id) + "/resolve",
+		query:  namespaceQuery(namespace),
+		header: ifMatch(version),
+	}, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// AddRelatedIssue relates two issues
+func (c *Client) AddRelatedIssue(ctx context.Context, namespace, id, relatedID string) error {
+	return c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   issuePath(id) + "/related",
+		query:  namespaceQuery(namespace),
+		body:   dto.RelatedIssueRequest{RelatedID: relatedID},
+	}, nil)
+}
+
+// RemoveRelatedIssue removes the relationship between two issues
+func (c *Client) RemoveRelatedIssue(ctx context.Context, namespace, id, relatedID string) error {
+	return c.do(ctx, &request{
+		method: http.MethodDelete,
+		path:   issuePath(id) + "/related/" + url.PathEscape(relatedID),
+		query:  namespaceQuery(namespace),
+	}, nil)
+}
+
+// ExportIssueToTracker creates a ticket for an issue in a tracker such as jira, returning the link to it
+func (c *Client) ExportIssueToTracker(ctx context.Context, namespace, id, tracker string) (*models.Link, error) {
+	var link models.Link
+	if err := c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   issuePath(id) + "/export/" + url.PathEscape(tracker),
+		query:  namespaceQuery(namespace),
+	}, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// StatsOptions groups issue counts
+type StatsOptions struct {
+	// Comma separated fields to group by, day buckets issues by the UTC day of DateField
+	GroupBy string
+	// detectedAt by default, or resolvedAt
+	DateField string
+}
+
+// GetIssueStats counts the issues matching filters
+func (c *Client) GetIssueStats(ctx context.Context, filters IssueFilters, opts StatsOptions) (*dto.IssueStatsResponse, error) {
+	query := filters.values()
+	if opts.GroupBy != "" {
+		query.Set("groupBy", opts.GroupBy)
+	}
+	if opts.DateField != "" {
+		query.Set("dateField", opts.DateField)
+	}
+
+	var stats dto.IssueStatsResponse
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/api/v1/issues/stats", query: query}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ExportIssues streams every issue matching filters as csv or ndjson. The caller has to close the returned body.
+func (c *Client) ExportIssues(ctx context.Context, filters IssueFilters, format string) (io.ReadCloser, error) {
+	query := filters.values()
+	if format != "" {
+		query.Set("format", format)
+	}
+
+	resp, err := c.send(ctx, &request{
+		method: http.MethodGet,
+		path:   "/api/v1/issues/export",
+		query:  query,
+		header: http.Header{"Accept": {"*/*"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// BulkUpdateIssues applies an action to the issues listed in req, or matching its filter.
+// With preview set the matching issues are only listed.
+func (c *Client) BulkUpdateIssues(ctx context.Context, req dto.BulkIssueRequest, preview bool) (*dto.BulkIssueResponse, error) {
+	var query url.Values
+	if preview {
+		query = url.Values{"preview": {"true"}}
+	}
+
+	var result dto.BulkIssueResponse
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/api/v1/issues/bulk", query: query, body: req}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Helper function to get the path of an issue
+func issuePath(id string) string {
+	return "/api/v1/issues/" + url.PathEscape(id)
+}
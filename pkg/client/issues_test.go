@@ -0,0 +1,375 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+func createRequest(name string) dto.CreateIssueRequest {
+	return dto.CreateIssueRequest{
+		Title:       "Build failed: " + name,
+		Description: "The build of " + name + " failed",
+		Severity:    models.SeverityMajor,
+		IssueType:   models.IssueTypeBuild,
+		Namespace:   "team-a",
+		Scope:       dto.ScopeReqBody{ResourceType: "component", ResourceName: name},
+		Links:       []dto.CreateLinkRequest{{Title: "Logs", URL: "https://logs.example.com/" + name}},
+	}
+}
+
+func TestIssueLifecycle(t *testing.T) {
+	c := newTestServer(t, nil).client(t)
+	ctx := context.Background()
+
+	issue, err := c.CreateIssue(ctx, createRequest("frontend"))
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if issue.ID == "" || issue.State != models.IssueStateActive || issue.Version != 1 || len(issue.Links) != 1 {
+		t.Fatalf("CreateIssue() = %+v, want an active issue with its link", issue)
+	}
+
+	got, err := c.GetIssue(ctx, "team-a", issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+	if got.Title != issue.Title || got.Scope.ResourceName != "frontend" {
+		t.Errorf("GetIssue() = %+v, want the issue created", got)
+	}
+
+	// Issues are only found in their namespace
+	_, err = c.GetIssue(ctx, "team-b", issue.ID)
+	checkAPIError(t, err, http.StatusForbidden, dto.CodeForbidden)
+
+	assignee := "alice"
+	replace := dto.ReplaceIssueRequest{
+		Title:       "Build still failing",
+		Description: issue.Description,
+		Severity:    models.SeverityCritical,
+		IssueType:   issue.IssueType,
+		State:       models.IssueStateActive,
+		Assignee:    &assignee,
+		Labels:      []string{"flaky"},
+	}
+	replaced, err := c.ReplaceIssue(ctx, "team-a", issue.ID, replace, issue.Version)
+	if err != nil {
+		t.Fatalf("ReplaceIssue() error = %v", err)
+	}
+	if replaced.Title != replace.Title || replaced.Severity != models.SeverityCritical || replaced.Version != 2 ||
+		replaced.Assignee == nil || *replaced.Assignee != "alice" || !slices.Equal(replaced.Labels, []string{"flaky"}) || len(replaced.Links) != 0 {
+		t.Errorf("ReplaceIssue() = %+v, want every field replaced at version 2", replaced)
+	}
+
+	// Writes at a version the issue has moved on from are rejected
+	_, err = c.ReplaceIssue(ctx, "team-a", issue.ID, replace, issue.Version)
+	checkAPIError(t, err, http.StatusPreconditionFailed, dto.CodePreconditionFailed)
+	if !IsPreconditionFailed(err) {
+		t.Errorf("ReplaceIssue() error = %v, want IsPreconditionFailed", err)
+	}
+
+	patched, err := c.PatchIssue(ctx, "team-a", issue.ID, PatchTypeMerge, []byte(`{"title":"Build fixed?"}`), replaced.Version)
+	if err != nil {
+		t.Fatalf("PatchIssue() error = %v", err)
+	}
+	if patched.Title != "Build fixed?" || patched.Severity != models.SeverityCritical {
+		t.Errorf("PatchIssue() = %+v, want only the title changed", patched)
+	}
+	patched, err = c.PatchIssue(ctx, "team-a", issue.ID, PatchTypeJSON, []byte(`[{"op":"add","path":"/labels/-","value":"ci"}]`), 0)
+	if err != nil {
+		t.Fatalf("PatchIssue() error = %v", err)
+	}
+	if !slices.Equal(patched.Labels, []string{"flaky", "ci"}) {
+		t.Errorf("PatchIssue() labels = %v, want the label added", patched.Labels)
+	}
+	_, err = c.PatchIssue(ctx, "team-a", issue.ID, "application/json", []byte(`{}`), 0)
+	checkAPIError(t, err, http.StatusUnsupportedMediaType, dto.CodeUnsupportedMediaType)
+
+	resolved, err := c.ResolveIssue(ctx, "team-a", issue.ID, patched.Version)
+	if err != nil {
+		t.Fatalf("ResolveIssue() error = %v", err)
+	}
+	if resolved.State != models.IssueStateResolved || resolved.ResolvedAt == nil {
+		t.Errorf("ResolveIssue() = %+v, want the issue resolved", resolved)
+	}
+
+	checkAPIError(t, c.DeleteIssue(ctx, "team-a", issue.ID, patched.Version), http.StatusPreconditionFailed, dto.CodePreconditionFailed)
+	if err := c.DeleteIssue(ctx, "team-a", issue.ID, resolved.Version); err != nil {
+		t.Fatalf("DeleteIssue() error = %v", err)
+	}
+	if _, err := c.GetIssue(ctx, "team-a", issue.ID); !IsNotFound(err) {
+		t.Errorf("GetIssue() error = %v after the issue was deleted, want it not found", err)
+	}
+}
+
+func TestCreateIssueValidation(t *testing.T) {
+	c := newTestServer(t, nil).client(t)
+	ctx := context.Background()
+
+	req := createRequest("frontend")
+	req.Title = ""
+	_, err := c.CreateIssue(ctx, req)
+	apiErr := checkAPIError(t, err, http.StatusUnprocessableEntity, dto.CodeValidationFailed)
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Field != "title" {
+		t.Errorf("CreateIssue() errors = %+v, want the title", apiErr.Errors)
+	}
+
+	req = createRequest("frontend")
+	req.Severity = "loud"
+	_, err = c.CreateIssue(ctx, req)
+	apiErr = checkAPIError(t, err, http.StatusUnprocessableEntity, dto.CodeValidationFailed)
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Field != "severity" {
+		t.Errorf("CreateIssue() errors = %+v, want the severity", apiErr.Errors)
+	}
+}
+
+func TestRelatedIssues(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ctx := context.Background()
+
+	source := server.issues.add(models.Issue{Title: "Build failed", Namespace: "team-a"})
+	target := server.issues.add(models.Issue{Title: "Test failed", Namespace: "team-a"})
+	other := server.issues.add(models.Issue{Title: "Deploy failed", Namespace: "team-b"})
+
+	if err := c.AddRelatedIssue(ctx, "team-a", source, target); err != nil {
+		t.Fatalf("AddRelatedIssue() error = %v", err)
+	}
+	issue, err := c.GetIssue(ctx, "team-a", source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issue.RelatedFrom) != 1 || issue.RelatedFrom[0].TargetID != target {
+		t.Errorf("related issues = %+v, want the target", issue.RelatedFrom)
+	}
+
+	checkAPIError(t, c.AddRelatedIssue(ctx, "team-a", target, source), http.StatusConflict, dto.CodeConflict)
+	// Issues in other namespaces can't be related, or found out about
+	checkAPIError(t, c.AddRelatedIssue(ctx, "team-a", source, other), http.StatusNotFound, dto.CodeNotFound)
+
+	if err := c.RemoveRelatedIssue(ctx, "team-a", source, target); err != nil {
+		t.Fatalf("RemoveRelatedIssue() error = %v", err)
+	}
+	checkAPIError(t, c.RemoveRelatedIssue(ctx, "team-a", source, target), http.StatusNotFound, dto.CodeNotFound)
+}
+
+// Helper function to add issues to a namespace, detected a minute apart from the newest, returning their IDs newest first
+func addIssues(server *testServer, namespace string, count int) []string {
+	ids := make([]string, count)
+	now := time.Now().Truncate(time.Second)
+	for i := range ids {
+		ids[i] = server.issues.add(models.Issue{
+			Title:      "Issue " + uuid.NewString(),
+			Namespace:  namespace,
+			Severity:   models.SeverityMajor,
+			IssueType:  models.IssueTypeBuild,
+			DetectedAt: now.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+	return ids
+}
+
+func TestListIssuesPages(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ctx := context.Background()
+	ids := addIssues(server, "team-a", 5)
+	addIssues(server, "team-b", 2)
+
+	first, err := c.ListIssues(ctx, IssueFilters{Namespace: "team-a"}, ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListIssues() error = %v", err)
+	}
+	if first.Total != 5 || len(first.Data) != 2 || first.Data[0].ID != ids[0] || first.NextCursor == "" {
+		t.Fatalf("ListIssues() = %+v, want the 2 newest of 5 issues with a cursor", first)
+	}
+
+	second, err := c.ListIssues(ctx, IssueFilters{Namespace: "team-a"}, ListOptions{Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("ListIssues() error = %v", err)
+	}
+	if len(second.Data) != 2 || second.Data[0].ID != ids[2] {
+		t.Errorf("ListIssues() = %+v, want the page after the cursor", second)
+	}
+
+	_, err = c.ListIssues(ctx, IssueFilters{Namespace: "team-a"}, ListOptions{Cursor: "not-a-cursor"})
+	checkAPIError(t, err, http.StatusBadRequest, dto.CodeInvalidRequest)
+	_, err = c.ListIssues(ctx, IssueFilters{Namespace: "team-a"}, ListOptions{Sort: "title", Cursor: first.NextCursor})
+	checkAPIError(t, err, http.StatusBadRequest, dto.CodeInvalidRequest)
+	_, err = c.ListIssues(ctx, IssueFilters{Namespace: "team-a", Q: "severity>="}, ListOptions{})
+	apiErr := checkAPIError(t, err, http.StatusBadRequest, dto.CodeInvalidRequest)
+	if apiErr.Position == nil {
+		t.Errorf("ListIssues() error = %+v, want the position of the invalid filter", apiErr)
+	}
+}
+
+func TestAllIssues(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ctx := context.Background()
+	ids := addIssues(server, "team-a", 7)
+
+	tests := []struct {
+		name string
+		opts ListOptions
+	}{
+		// Pages follow the cursor of the previous page
+		{name: "cursor", opts: ListOptions{Limit: 3}},
+		// Sorted lists have no cursors, pages are fetched by offset
+		{name: "offset", opts: ListOptions{Limit: 3, Sort: "-detectedAt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for issue, err := range c.AllIssues(ctx, IssueFilters{Namespace: "team-a"}, tt.opts) {
+				if err != nil {
+					t.Fatalf("AllIssues() error = %v", err)
+				}
+				got = append(got, issue.ID)
+			}
+			if !slices.Equal(got, ids) {
+				t.Errorf("AllIssues() = %v, want every issue once, newest first %v", got, ids)
+			}
+		})
+	}
+
+	// Stopping early doesn't fetch the remaining pages
+	count := 0
+	for range c.AllIssues(ctx, IssueFilters{Namespace: "team-a"}, ListOptions{Limit: 3}) {
+		count++
+		if count == 4 {
+			break
+		}
+	}
+	if count != 4 {
+		t.Errorf("AllIssues() yielded %d issues before stopping, want 4", count)
+	}
+
+	for _, err := range c.AllIssues(ctx, IssueFilters{Namespace: "team-a"}, ListOptions{Cursor: "not-a-cursor"}) {
+		checkAPIError(t, err, http.StatusBadRequest, dto.CodeInvalidRequest)
+	}
+}
+
+func TestGetIssueStats(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	addIssues(server, "team-a", 3)
+	server.issues.add(models.Issue{Title: "Outage", Namespace: "team-a", Severity: models.SeverityCritical})
+
+	stats, err := c.GetIssueStats(context.Background(), IssueFilters{Namespace: "team-a"}, StatsOptions{GroupBy: "severity"})
+	if err != nil {
+		t.Fatalf("GetIssueStats() error = %v", err)
+	}
+	if stats.Total != 4 || len(stats.Groups) != 2 {
+		t.Fatalf("GetIssueStats() = %+v, want 4 issues in 2 groups", stats)
+	}
+	for _, group := range stats.Groups {
+		want := map[string]int64{"critical": 1, "major": 3}[*group.Key["severity"]]
+		if group.Count != want {
+			t.Errorf("%s issues = %d, want %d", *group.Key["severity"], group.Count, want)
+		}
+	}
+
+	_, err = c.GetIssueStats(context.Background(), IssueFilters{Namespace: "team-a"}, StatsOptions{GroupBy: "color"})
+	checkAPIError(t, err, http.StatusBadRequest, dto.CodeInvalidRequest)
+}
+
+func TestExportIssues(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ids := addIssues(server, "team-a", 3)
+
+	body, err := c.ExportIssues(context.Background(), IssueFilters{Namespace: "team-a"}, "ndjson")
+	if err != nil {
+		t.Fatalf("ExportIssues() error = %v", err)
+	}
+	defer body.Close()
+
+	var exported []string
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		var issue models.Issue
+		if err := json.Unmarshal(scanner.Bytes(), &issue); err != nil {
+			t.Fatalf("failed to decode exported issue %q: %v", scanner.Text(), err)
+		}
+		exported = append(exported, issue.ID)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(exported, ids) {
+		t.Errorf("exported %v, want %v", exported, ids)
+	}
+
+	_, err = c.ExportIssues(context.Background(), IssueFilters{Namespace: "team-a"}, "xml")
+	checkAPIError(t, err, http.StatusBadRequest, dto.CodeInvalidRequest)
+}
+
+func TestBulkUpdateIssues(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ctx := context.Background()
+	ids := addIssues(server, "team-a", 2)
+	missing := uuid.NewString()
+	req := dto.BulkIssueRequest{IDs: append(ids, missing), Action: "resolve"}
+
+	preview, err := c.BulkUpdateIssues(ctx, req, true)
+	if err != nil {
+		t.Fatalf("BulkUpdateIssues() error = %v", err)
+	}
+	if !preview.Preview || preview.Matched != 2 || len(preview.Items) != 3 || preview.Items[2].Status != "not_found" {
+		t.Errorf("BulkUpdateIssues() = %+v, want a preview matching 2 issues", preview)
+	}
+	if issue, _ := c.GetIssue(ctx, "team-a", ids[0]); issue.State != models.IssueStateActive {
+		t.Errorf("issue is %s after a preview, want it unchanged", issue.State)
+	}
+
+	result, err := c.BulkUpdateIssues(ctx, req, false)
+	if err != nil {
+		t.Fatalf("BulkUpdateIssues() error = %v", err)
+	}
+	if result.Applied != 2 || result.Items[0].Status != "updated" {
+		t.Errorf("BulkUpdateIssues() = %+v, want 2 issues updated", result)
+	}
+	for _, id := range ids {
+		if issue, _ := c.GetIssue(ctx, "team-a", id); issue.State != models.IssueStateResolved {
+			t.Errorf("issue %s is %s, want it resolved", id, issue.State)
+		}
+	}
+
+	_, err = c.BulkUpdateIssues(ctx, dto.BulkIssueRequest{IDs: []string{"not-an-id"}, Action: "resolve"}, false)
+	apiErr := checkAPIError(t, err, http.StatusUnprocessableEntity, dto.CodeValidationFailed)
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Field != "ids[0]" {
+		t.Errorf("BulkUpdateIssues() errors = %+v, want the invalid ID", apiErr.Errors)
+	}
+}
+
+func TestExportIssueToTracker(t *testing.T) {
+	server := newTestServer(t, nil)
+	c := server.client(t)
+	ctx := context.Background()
+	id := server.issues.add(models.Issue{Title: "Build failed", Namespace: "team-a", Severity: models.SeverityMajor})
+
+	link, err := c.ExportIssueToTracker(ctx, "team-a", id, "jira")
+	if err != nil {
+		t.Fatalf("ExportIssueToTracker() error = %v", err)
+	}
+	if link.Title != "Jira KITE-1" || !strings.HasSuffix(link.URL, "/browse/KITE-1") {
+		t.Errorf("ExportIssueToTracker() = %+v, want a link to KITE-1", link)
+	}
+
+	// Issues are only exported once to each tracker
+	_, err = c.ExportIssueToTracker(ctx, "team-a", id, "jira")
+	checkAPIError(t, err, http.StatusConflict, dto.CodeConflict)
+	_, err = c.ExportIssueToTracker(ctx, "team-a", id, "github")
+	checkAPIError(t, err, http.StatusNotFound, dto.CodeNotFound)
+}
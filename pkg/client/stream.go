@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// IssueEvent is a change to an issue received from StreamIssues
+type IssueEvent struct {
+	// Pass to StreamIssues as lastEventID to resume after this event
+	ID string
+	// created, updated, resolved or deleted
+	Type string
+	// The issue after the change, or before it was deleted
+	Issue models.Issue
+}
+
+// ErrStreamReset is returned by Next when a stream was resumed after an event that's no longer in the server's
+// event log. Changes were missed, so the issues should be fetched again, and the stream carries on with new changes.
+var ErrStreamReset = errors.New("issue stream reset, changes since the last event were missed")
+
+// IssueStream reads issue changes as they happen
+type IssueStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+}
+
+// StreamIssues streams changes to the issues matching filters. When lastEventID is set the changes
+// since that event are sent first, so a stream can be resumed after a disconnect.
+func (c *Client) StreamIssues(ctx context.Context, filters IssueFilters, lastEventID string) (*IssueStream, error) {
+	header := http.Header{"Accept": {"text/event-stream"}}
+	if lastEventID != "" {
+		header.Set("Last-Event-ID", lastEventID)
+	}
+
+	// Streams are long lived, so they're only retried when they can't be opened
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: "/api/v1/issues/stream", query: filters.values(), header: header})
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	// Events carry a whole issue, which can be bigger than the default token size
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &IssueStream{body: resp.Body, scanner: scanner}, nil
+}
+
+// Next blocks until the next event, returning io.EOF when the server closes the stream, and ErrStreamReset
+// when events were missed
+func (s *IssueStream) Next() (*IssueEvent, error) {
+	var event IssueEvent
+	var data strings.Builder
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			// A blank line ends an event, keep-alive comments have no data
+			if data.Len() == 0 {
+				continue
+			}
+			if event.Type == "reset" {
+				return nil, ErrStreamReset
+			}
+			if err := json.Unmarshal([]byte(data.String()), &event.Issue); err != nil {
+				return nil, fmt.Errorf("failed to decode event %s: %w", event.ID, err)
+			}
+			return &event, nil
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID = value
+		case "event":
+			event.Type = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Close stops the stream
+func (s *IssueStream) Close() error {
+	return s.body.Close()
+}
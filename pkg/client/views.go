@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// ListViews returns the shared views in a namespace and the caller's private ones
+func (c *Client) ListViews(ctx context.Context, namespace string) ([]models.SavedView, error) {
+	var views dto.SavedViewListResponse
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/api/v1/views/", query: namespaceQuery(namespace)}, &views); err != nil {
+		return nil, err
+	}
+	return views.Data, nil
+}
+
+// GetView returns a saved view by ID
+func (c *Client) GetView(ctx context.Context, namespace, id string) (*models.SavedView, error) {
+	var view models.SavedView
+	if err := c.do(ctx, &request{method: http.MethodGet, path: viewPath(id), query: namespaceQuery(namespace)}, &view); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// CreateView saves a view in a namespace
+func (c *Client) CreateView(ctx context.Context, namespace string, req dto.SavedViewRequest) (*models.SavedView, error) {
+	var view models.SavedView
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/api/v1/views/", query: namespaceQuery(namespace), body: req}, &view); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// UpdateView replaces the name, visibility and filters of a saved view
+func (c *Client) UpdateView(ctx context.Context, namespace, id string, req dto.SavedViewRequest) (*models.SavedView, error) {
+	var view models.SavedView
+	if err := c.do(ctx, &request{method: http.MethodPut, path: viewPath(id), query: namespaceQuery(namespace), body: req}, &view); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// DeleteView deletes a saved view
+func (c *Client) DeleteView(ctx context.Context, namespace, id string) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: viewPath(id), query: namespaceQuery(namespace)}, nil)
+}
+
+// ListViewIssues returns a page of the issues matching a saved view. Only the pagination fields of opts
+// are used, the rest come from the view.
+func (c *Client) ListViewIssues(ctx context.Context, namespace, id string, opts ListOptions) (*dto.IssueResponse, error) {
+	query := namespaceQuery(namespace)
+	ListOptions{Limit: opts.Limit, Offset: opts.Offset, Cursor: opts.Cursor}.apply(query)
+
+	var page dto.IssueResponse
+	if err := c.do(ctx, &request{method: http.MethodGet, path: viewPath(id) + "/issues", query: query}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Helper function to get the path of a saved view
+func viewPath(id string) string {
+	return "/api/v1/views/" + url.PathEscape(id)
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/konflux-ci/kite/pkg/dto"
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// WebhookResult is the response to a webhook
+type WebhookResult struct {
+	Status  string        `json:"status"`
+	Message string        `json:"message,omitempty"`
+	Issue   *models.Issue `json:"issue,omitempty"`
+}
+
+// PipelineFailure records a failed pipeline run, creating or updating the issue for the pipeline
+func (c *Client) PipelineFailure(ctx context.Context, req dto.PipelineFailureRequest) (*WebhookResult, error) {
+	var result WebhookResult
+	if err := c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   "/api/v1/webhooks/pipeline-failure",
+		query:  namespaceQuery(req.Namespace),
+		body:   req,
+	}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PipelineSuccess resolves the active issues of a pipeline that succeeded
+func (c *Client) PipelineSuccess(ctx context.Context, req dto.PipelineSuccessRequest) (*WebhookResult, error) {
+	var result WebhookResult
+	if err := c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   "/api/v1/webhooks/pipeline-success",
+		query:  namespaceQuery(req.Namespace),
+		body:   req,
+	}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,46 @@
+package dto
+
+// ProblemContentType is the media type of error responses
+const ProblemContentType = "application/problem+json"
+
+// Error codes of problem responses. Unlike titles and details they never change, so clients can rely on them.
+const (
+	CodeInvalidRequest       = "invalid_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeConflict             = "conflict"
+	CodePreconditionFailed   = "precondition_failed"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeUpstreamFailed       = "upstream_failed"
+	CodeInternal             = "internal_error"
+)
+
+// Problem is an error response, in the RFC 7807 problem details format
+type Problem struct {
+	// URI identifying the kind of problem, one per code
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Path of the request that failed
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	// Fields of the request with invalid values, for validation failures
+	Errors []FieldError `json:"errors,omitempty"`
+	// Offset in the q filter where parsing failed, for invalid filters
+	Position *int `json:"position,omitempty"`
+}
+
+// FieldError is an invalid value of a request field, named by its path in the request body such as scope.resourceName
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ProblemType returns the type URI of problems with a code
+func ProblemType(code string) string {
+	return "urn:kite:problem:" + code
+}
@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// DTOs (Data Transfer Objects)
+// These allow us to carry and format data between layers or services, without embedding any business logic.
+
+// For requests
+
+type ScopeReqBody struct {
+	ResourceType      string `json:"resourceType" binding:"required"`
+	ResourceName      string `json:"resourceName" binding:"required"`
+	ResourceNamespace string `json:"resourceNamespace"`
+}
+
+type CreateIssueRequest struct {
+	Title       string              `json:"title" binding:"required"`
+	Description string              `json:"description" binding:"required"`
+	Severity    models.Severity     `json:"severity" binding:"required"`
+	IssueType   models.IssueType    `json:"issueType" binding:"required"`
+	State       models.IssueState   `json:"state"`
+	Namespace   string              `json:"namespace" binding:"required"`
+	Scope       ScopeReqBody        `json:"scope" binding:"required"`
+	Links       []CreateLinkRequest `json:"links"`
+}
+
+// ReplaceIssueRequest holds every field of an issue that can be changed, namespace and scope are fixed when it's created.
+// Fields left out are cleared, and links without an ID are created.
+type ReplaceIssueRequest struct {
+	Title       string               `json:"title" binding:"required"`
+	Description string               `json:"description" binding:"required"`
+	Severity    models.Severity      `json:"severity" binding:"required"`
+	IssueType   models.IssueType     `json:"issueType" binding:"required"`
+	State       models.IssueState    `json:"state" binding:"required"`
+	ResolvedAt  *time.Time           `json:"resolvedAt"`
+	Assignee    *string              `json:"assignee"`
+	Labels      []string             `json:"labels" binding:"dive,required"`
+	Links       []ReplaceLinkRequest `json:"links" binding:"dive"`
+}
+
+type ReplaceLinkRequest struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title" binding:"required"`
+	URL   string `json:"url" binding:"required"`
+}
+
+// ImportIssueRequest is an issue brought in from elsewhere, keeping the timestamps it was recorded with
+type ImportIssueRequest struct {
+	CreateIssueRequest
+	DetectedAt *time.Time `json:"detectedAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+}
+
+type CreateLinkRequest struct {
+	Title string `json:"title" binding:"required"`
+	URL   string `json:"url" binding:"required"`
+}
+
+type UpdateIssueRequest struct {
+	Title       *string             `json:"title"`
+	Description *string             `json:"description"`
+	Severity    *models.Severity    `json:"severity"`
+	IssueType   *models.IssueType   `json:"issueType"`
+	State       *models.IssueState  `json:"state"`
+	ResolvedAt  *time.Time          `json:"resolvedAt"`
+	Links       []CreateLinkRequest `json:"links"`
+}
+
+type SavedViewRequest struct {
+	Name       string                `json:"name" binding:"required"`
+	Visibility models.ViewVisibility `json:"visibility"`
+	Filters    models.ViewFilters    `json:"filters"`
+}
+
+// BulkIssueRequest applies one action to the issues listed in IDs, or to those matching Filter in Namespace
+type BulkIssueRequest struct {
+	IDs       []string        `json:"ids"`
+	Filter    string          `json:"filter"`
+	Namespace string          `json:"namespace"`
+	Action    string          `json:"action" binding:"required"`
+	Severity  models.Severity `json:"severity"`
+	Assignee  *string         `json:"assignee"`
+	Label     string          `json:"label"`
+}
+
+// RelatedIssueRequest relates the issue in the path to another one
+type RelatedIssueRequest struct {
+	RelatedID string `json:"relatedId" binding:"required"`
+}
+
+type PipelineFailureRequest struct {
+	PipelineName  string `json:"pipelineName" binding:"required"`
+	Namespace     string `json:"namespace" binding:"required"`
+	FailureReason string `json:"failureReason" binding:"required"`
+	RunID         string `json:"runId"`
+	LogsURL       string `json:"logsUrl"`
+}
+
+type PipelineSuccessRequest struct {
+	PipelineName string `json:"pipelineName" binding:"required"`
+	Namespace    string `json:"namespace" binding:"required"`
+}
@@ -0,0 +1,120 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/konflux-ci/kite/pkg/models"
+)
+
+// DTOs (Data Transfer Objects)
+// These allow us to carry and format data between layers or services, without embedding any business logic.
+
+type IssueResponse struct {
+	Data   []models.Issue `json:"data"`
+	Total  int64          `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+	// Opaque tokens for the next and previous pages, passed back in the cursor query param
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+// ProjectedIssueResponse is an IssueResponse where each issue only has the fields that were requested
+type ProjectedIssueResponse struct {
+	Data       []map[string]any `json:"data"`
+	Total      int64            `json:"total"`
+	Limit      int              `json:"limit"`
+	Offset     int              `json:"offset"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+	PrevCursor string           `json:"prevCursor,omitempty"`
+}
+
+type SavedViewListResponse struct {
+	Data []models.SavedView `json:"data"`
+}
+
+type IssueStatsResponse struct {
+	GroupBy []string          `json:"groupBy"`
+	Total   int64             `json:"total"`
+	Groups  []IssueStatsGroup `json:"groups"`
+}
+
+// IssueStatsGroup is the number of issues sharing the values in Key, keyed by the grouped field
+type IssueStatsGroup struct {
+	Key   map[string]*string `json:"key"`
+	Count int64              `json:"count"`
+}
+
+type ReliabilityResponse struct {
+	Namespace   string             `json:"namespace"`
+	Since       time.Time          `json:"since"`
+	Until       time.Time          `json:"until"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Summary     ReliabilityMetrics `json:"summary"`
+	Scopes      []ScopeReliability `json:"scopes"`
+}
+
+// ReliabilityMetrics describes how often issues occurred over a window and how quickly they were resolved
+type ReliabilityMetrics struct {
+	FailureCount   int64   `json:"failureCount"`
+	ResolvedCount  int64   `json:"resolvedCount"`
+	MTTR           MTTR    `json:"mttr"`
+	FailingPercent float64 `json:"failingPercent"`
+	Streak         Streak  `json:"streak"`
+}
+
+// MTTR holds time-to-resolve statistics in seconds, null when nothing was resolved during the window
+type MTTR struct {
+	Mean   *float64 `json:"mean"`
+	Median *float64 `json:"median"`
+	P90    *float64 `json:"p90"`
+}
+
+// Streak is how long a scope has been failing or healthy as of the end of the window
+type Streak struct {
+	State string `json:"state"`
+	// Null for scopes that have never failed
+	Since           *time.Time `json:"since"`
+	DurationSeconds *float64   `json:"durationSeconds"`
+}
+
+type ScopeReliability struct {
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	ReliabilityMetrics
+}
+
+type ImportReport struct {
+	DryRun     bool              `json:"dryRun"`
+	Total      int               `json:"total"`
+	Valid      int               `json:"valid"`
+	Invalid    int               `json:"invalid"`
+	Duplicates int               `json:"duplicates"`
+	Created    int               `json:"created"`
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult is the outcome of importing one row, numbered from 1 in the order rows were read
+type ImportRowResult struct {
+	Row             int    `json:"row"`
+	Status          string `json:"status"`
+	Error           string `json:"error,omitempty"`
+	IssueID         string `json:"issueId,omitempty"`
+	ExistingIssueID string `json:"existingIssueId,omitempty"`
+}
+
+type BulkIssueResponse struct {
+	Action  string            `json:"action"`
+	Preview bool              `json:"preview"`
+	Matched int64             `json:"matched"`
+	Applied int               `json:"applied"`
+	Items   []BulkIssueResult `json:"items"`
+}
+
+// BulkIssueResult is the outcome of a bulk action for one issue
+type BulkIssueResult struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Enums
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityMinor    Severity = "minor"
+	SeverityMajor    Severity = "major"
+	SeverityCritical Severity = "critical"
+)
+
+// Severities lists all severities from least to most severe
+var Severities = []Severity{SeverityInfo, SeverityMinor, SeverityMajor, SeverityCritical}
+
+// Rank returns the position of the severity in Severities, or -1 if the severity is unknown
+func (s Severity) Rank() int {
+	for i, severity := range Severities {
+		if severity == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// AtLeast returns every severity at or above the severity passed
+func (s Severity) AtLeast() []Severity {
+	if rank := s.Rank(); rank >= 0 {
+		return Severities[rank:]
+	}
+	return nil
+}
+
+type IssueType string
+
+const (
+	IssueTypeBuild      IssueType = "build"
+	IssueTypeTest       IssueType = "test"
+	IssueTypeRelease    IssueType = "release"
+	IssueTypeDependency IssueType = "dependency"
+	IssueTypePipeline   IssueType = "pipeline"
+)
+
+// IssueTypes lists all issue types
+var IssueTypes = []IssueType{IssueTypeBuild, IssueTypeTest, IssueTypeRelease, IssueTypeDependency, IssueTypePipeline}
+
+type IssueState string
+
+const (
+	IssueStateActive   IssueState = "ACTIVE"
+	IssueStateResolved IssueState = "RESOLVED"
+)
+
+// IssueStates lists all issue states
+var IssueStates = []IssueState{IssueStateActive, IssueStateResolved}
+
+// Issue represents an issue in the cluster
+type Issue struct {
+	ID          string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid();index:idx_issues_detected_at_id,priority:2,sort:desc;index:idx_issues_namespace_detected_at_id,priority:3,sort:desc" json:"id"`
+	Title       string     `gorm:"not null" json:"title"`
+	Description string     `gorm:"not null" json:"description"`
+	Severity    Severity   `gorm:"type:varchar(20);not null" json:"severity"`
+	IssueType   IssueType  `gorm:"type:varchar(20);not null" json:"issueType"`
+	State       IssueState `gorm:"type:varchar(20);default:ACTIVE" json:"state"`
+	DetectedAt  time.Time  `gorm:"not null;index:idx_issues_detected_at_id,priority:1,sort:desc;index:idx_issues_namespace_detected_at_id,priority:2,sort:desc" json:"detectedAt"`
+	ResolvedAt  *time.Time `json:"resolvedAt"`
+	Namespace   string     `gorm:"not null;index:idx_issues_namespace_detected_at_id,priority:1" json:"namespace"`
+	Assignee    *string    `json:"assignee"`
+	Labels      []string   `gorm:"type:jsonb;not null;default:'[]';serializer:json" json:"labels"`
+
+	// Foreign key to IssueScope
+	ScopeID string     `gorm:"type:uuid;not null;unique" json:"scopeId"`
+	Scope   IssueScope `gorm:"foreignKey:ScopeID" json:"scope"`
+
+	// Relationships
+	Links       []Link         `gorm:"foreignKey:IssueID" json:"links"`
+	RelatedFrom []RelatedIssue `gorm:"foreignKey:SourceID" json:"relatedFrom"`
+	RelatedTo   []RelatedIssue `gorm:"foreignKey:TargetID" json:"relatedTo"`
+
+	// Full-text search
+	// Scope names copied from the issue's scope, generated columns can't reference other tables
+	ScopeTerms   string `gorm:"not null;default:''" json:"-"`
+	SearchVector string `gorm:"->:false;<-:false;type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(title, '')), 'A') || setweight(to_tsvector('english', coalesce(description, '')), 'B') || setweight(to_tsvector('simple', coalesce(scope_terms, '')), 'C')) STORED;index:idx_issues_search_vector,type:gin" json:"-"`
+	// Highlighted matches, only set on search results
+	Highlight *IssueHighlight `gorm:"-" json:"highlight,omitempty"`
+
+	// Incremented on every change, for optimistic concurrency
+	Version int64 `gorm:"not null;default:1" json:"version"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// IssueHighlight holds snippets of an issue with the search terms marked
+type IssueHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (i *Issue) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+
+	// Copy the scope names so the issue can be searched by them
+	if i.ScopeTerms == "" {
+		scope := i.Scope
+		if scope.ResourceName == "" && i.ScopeID != "" {
+			if err := tx.Session(&gorm.Session{NewDB: true}).First(&scope, "id = ?", i.ScopeID).Error; err != nil {
+				return err
+			}
+		}
+		i.ScopeTerms = strings.TrimSpace(scope.ResourceType + " " + scope.ResourceName)
+	}
+	return nil
+}
+
+// IssueScope represents the scope of an Issue
+type IssueScope struct {
+	ID                string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ResourceType      string `gorm:"not null" json:"resourceType"`
+	ResourceName      string `gorm:"not null" json:"resourceName"`
+	ResourceNamespace string `gorm:"not null" json:"resourceNamespace"`
+
+	// Relationship - one issue scope has one issue
+	Issue *Issue `gorm:"foreignKey:ScopeID" json:"issue,omitempty"`
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (s *IssueScope) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// RelatedIssue represetns relationships between issues
+type RelatedIssue struct {
+	ID       string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	SourceID string `gorm:"type:uuid;not null" json:"sourceId"`
+	TargetID string `gorm:"type:uuid;not null" json:"targetId"`
+
+	// Relationships
+	Source Issue `gorm:"foreignKey:SourceID" json:"source,omitempty"`
+	Target Issue `gorm:"foreignKey:TargetID" json:"target,omitempty"`
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (r *RelatedIssue) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Link represents a link associated with an issue
+type Link struct {
+	ID      string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Title   string `gorm:"not null" json:"title"`
+	URL     string `gorm:"not null" json:"url"`
+	IssueID string `gorm:"type:uuid;not null" json:"issueId"`
+	// Omit field when converting to JSON or deconverting from JSON
+	Issue Issue `gorm:"foreignKey:IssueID" json:"-"`
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (l *Link) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Event types recorded for issue changes
+const (
+	EventTypeIssueCreated  = "dev.konflux.kite.issue.created"
+	EventTypeIssueUpdated  = "dev.konflux.kite.issue.updated"
+	EventTypeIssueResolved = "dev.konflux.kite.issue.resolved"
+	EventTypeIssueDeleted  = "dev.konflux.kite.issue.deleted"
+)
+
+// OutboxEvent is an issue change, written in the same transaction as the change itself
+type OutboxEvent struct {
+	ID int64 `gorm:"primaryKey;autoIncrement;index:idx_outbox_events_position,priority:2" json:"id"`
+	// ID of the transaction that recorded the event. IDs are taken when events are inserted rather than committed,
+	// so events are read in the order of their transactions once they've finished, see repository.EventPosition.
+	TxID      int64  `gorm:"not null;default:(pg_current_xact_id()::text::bigint);index:idx_outbox_events_position,priority:1" json:"-"`
+	Type      string `gorm:"type:varchar(100);not null" json:"type"`
+	IssueID   string `gorm:"type:uuid;not null" json:"issueId"`
+	Namespace string `gorm:"not null" json:"namespace"`
+	// Full JSON of the issue after the change, or before it for deletions
+	Payload      json.RawMessage `gorm:"type:jsonb;not null" json:"payload"`
+	Attempts     int             `gorm:"not null;default:0" json:"attempts"`
+	LastError    string          `json:"lastError,omitempty"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	DispatchedAt *time.Time      `gorm:"index:idx_outbox_events_pending,where:dispatched_at IS NULL AND parked_at IS NULL" json:"dispatchedAt"`
+	// Set when the event is given up on after failing too many times, it's kept for inspection until pruned
+	ParkedAt *time.Time `json:"parkedAt,omitempty"`
+}
+
+type ViewVisibility string
+
+const (
+	// Private views are only visible to their owner
+	ViewVisibilityPrivate ViewVisibility = "private"
+	// Shared views are visible to everyone with access to the namespace
+	ViewVisibilityShared ViewVisibility = "shared"
+)
+
+// SavedView is a named set of issue filters that can be run again later
+type SavedView struct {
+	ID         string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name       string         `gorm:"not null;uniqueIndex:idx_saved_views_namespace_owner_name,priority:3" json:"name"`
+	Owner      string         `gorm:"not null;uniqueIndex:idx_saved_views_namespace_owner_name,priority:2" json:"owner"`
+	Namespace  string         `gorm:"not null;uniqueIndex:idx_saved_views_namespace_owner_name,priority:1" json:"namespace"`
+	Visibility ViewVisibility `gorm:"type:varchar(20);not null;default:private" json:"visibility"`
+	Filters    ViewFilters    `gorm:"type:jsonb;not null;serializer:json" json:"filters"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+// ViewFilters are the issue list query params saved in a view, in the same format as GET /issues
+type ViewFilters struct {
+	Severity     string `json:"severity,omitempty"`
+	IssueType    string `json:"issueType,omitempty"`
+	State        string `json:"state,omitempty"`
+	ResourceType string `json:"resourceType,omitempty"`
+	ResourceName string `json:"resourceName,omitempty"`
+	Search       string `json:"search,omitempty"`
+	Q            string `json:"q,omitempty"`
+	Sort         string `json:"sort,omitempty"`
+	Fields       string `json:"fields,omitempty"`
+	// Nil loads every association, an empty string loads none
+	Include *string `json:"include,omitempty"`
+	Limit   int     `json:"limit,omitempty"`
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (v *SavedView) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	return nil
+}